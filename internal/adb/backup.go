@@ -0,0 +1,378 @@
+package adb
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 备份归档格式版本，结构变化时递增
+const backupFormatVersion = 1
+
+// BackupExtension 应用备份归档的文件扩展名（zip格式）
+const BackupExtension = ".ykbak"
+
+// 归档内的固定条目
+const (
+	backupMetadataEntry = "metadata.json"
+	backupDataEntry     = "data.tar"
+	backupAPKDir        = "apk/"
+)
+
+// 备份/恢复阶段，用于进度回调
+const (
+	BackupStageAPK     = "apk"
+	BackupStageData    = "data"
+	BackupStageInstall = "install"
+	BackupStageRestore = "restore"
+)
+
+// BackupMetadata 备份归档中的 metadata.json，描述备份来源和内容
+type BackupMetadata struct {
+	FormatVersion int       `json:"format_version"`
+	PackageName   string    `json:"package_name"`
+	VersionCode   int64     `json:"version_code"`
+	VersionName   string    `json:"version_name"`
+	DeviceSerial  string    `json:"device_serial"`
+	DeviceModel   string    `json:"device_model"`
+	SDK           string    `json:"sdk"`
+	CreatedAt     time.Time `json:"created_at"`
+	APKs          []string  `json:"apks"`
+	HasData       bool      `json:"has_data"`
+	DataSize      int64     `json:"data_size"`
+	ExcludedDirs  []string  `json:"excluded_dirs,omitempty"`
+}
+
+// BackupOptions 备份选项
+type BackupOptions struct {
+	IncludeAPK   bool // 通过 pm path 提取APK（含拆分包）
+	IncludeData  bool // 通过 run-as tar 打包应用私有数据
+	ExcludeCache bool // 排除 cache 与 code_cache 目录
+}
+
+// RestoreOptions 恢复选项
+type RestoreOptions struct {
+	InstallAPK bool // 先安装归档中的APK
+	ClearData  bool // 恢复前先清除应用现有数据
+}
+
+// BackupProgress 备份/恢复进度回调，bytes 为当前阶段已传输的字节数
+type BackupProgress func(stage string, bytes int64)
+
+// CheckRunAs 检查应用是否可通过 run-as 访问（需要 debuggable 应用）
+func CheckRunAs(deviceID string, packageName string) error {
+	result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "run-as", shellQuote(packageName), "id"}, 10*time.Second)
+	output := ""
+	if result != nil {
+		output = strings.TrimSpace(result.Output + result.ErrorOutput)
+	}
+	if err != nil || strings.Contains(output, "run-as:") || !strings.Contains(output, "uid=") {
+		if strings.Contains(output, "not debuggable") {
+			return fmt.Errorf("应用 %s 不是可调试（debuggable）应用，无法通过 run-as 访问数据", packageName)
+		}
+		if output == "" && err != nil {
+			output = err.Error()
+		}
+		return fmt.Errorf("run-as 不可用: %s", output)
+	}
+	return nil
+}
+
+// GetPackagePaths 通过 pm path 获取应用全部APK（含拆分包）在设备上的路径
+func GetPackagePaths(deviceID string, packageName string) ([]string, error) {
+	result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "pm", "path", packageName}, 15*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, line := range strings.Split(result.Output, "\n") {
+		if p, ok := strings.CutPrefix(strings.TrimSpace(line), "package:"); ok && p != "" {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("设备上未安装应用: %s", packageName)
+	}
+	return paths, nil
+}
+
+// appDataDir 应用私有数据目录
+func appDataDir(packageName string) string {
+	return "/data/data/" + packageName
+}
+
+// BackupApp 将应用的APK与私有数据备份为自描述的归档文件（zip），
+// 数据部分通过 exec-out run-as tar 直接流式写入归档，不在设备上生成临时文件
+func BackupApp(deviceID string, packageName string, destPath string, opts BackupOptions, progress BackupProgress) (*BackupMetadata, error) {
+	log.Printf("开始备份应用: deviceID=%s, package=%s, dest=%s, opts=%+v", deviceID, packageName, destPath, opts)
+
+	if !opts.IncludeAPK && !opts.IncludeData {
+		return nil, fmt.Errorf("至少需要备份APK或数据之一")
+	}
+	if opts.IncludeData {
+		if err := CheckRunAs(deviceID, packageName); err != nil {
+			return nil, err
+		}
+	}
+
+	meta := &BackupMetadata{
+		FormatVersion: backupFormatVersion,
+		PackageName:   packageName,
+		DeviceSerial:  deviceID,
+		CreatedAt:     time.Now(),
+	}
+	if installed, err := GetInstalledPackage(deviceID, packageName); err == nil && installed != nil {
+		meta.VersionCode = installed.VersionCode
+		meta.VersionName = installed.VersionName
+	}
+	if model, err := GetDeviceName(deviceID); err == nil {
+		meta.DeviceModel = model
+	}
+	if result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "getprop", "ro.build.version.sdk"}, 10*time.Second); err == nil {
+		meta.SDK = strings.TrimSpace(result.Output)
+	}
+
+	out, err := os.Create(destPath)
+	if err != nil {
+		return nil, err
+	}
+	success := false
+	defer func() {
+		out.Close()
+		if !success {
+			os.Remove(destPath)
+		}
+	}()
+
+	archive := zip.NewWriter(out)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	if opts.IncludeAPK {
+		paths, err := GetPackagePaths(deviceID, packageName)
+		if err != nil {
+			return nil, err
+		}
+		for _, remote := range paths {
+			name := path.Base(remote)
+			entry, err := archive.CreateHeader(&zip.FileHeader{Name: backupAPKDir + name, Method: zip.Store, Modified: time.Now()})
+			if err != nil {
+				return nil, err
+			}
+			writer := &countingWriter{writer: entry, onProgress: func(n int64) {
+				if progress != nil {
+					progress(BackupStageAPK, n)
+				}
+			}}
+			if err := ExecOut(ctx, deviceID, "cat "+shellQuote(remote), writer); err != nil {
+				return nil, fmt.Errorf("提取APK失败 %s: %w", remote, err)
+			}
+			meta.APKs = append(meta.APKs, name)
+		}
+	}
+
+	if opts.IncludeData {
+		command := "run-as " + shellQuote(packageName) + " tar -cf - -C " + shellQuote(appDataDir(packageName))
+		if opts.ExcludeCache {
+			meta.ExcludedDirs = []string{"cache", "code_cache"}
+			for _, dir := range meta.ExcludedDirs {
+				command += " --exclude=./" + dir
+			}
+		}
+		command += " ."
+
+		entry, err := archive.Create(backupDataEntry)
+		if err != nil {
+			return nil, err
+		}
+		writer := &countingWriter{writer: entry, onProgress: func(n int64) {
+			if progress != nil {
+				progress(BackupStageData, n)
+			}
+		}}
+		if err := ExecOut(ctx, deviceID, command, writer); err != nil {
+			return nil, fmt.Errorf("打包应用数据失败: %w", err)
+		}
+		if writer.written < 512 {
+			// 有效的tar至少包含一个512字节的块，过短通常是错误信息
+			return nil, fmt.Errorf("打包应用数据失败: 设备返回的数据无效")
+		}
+		meta.HasData = true
+		meta.DataSize = writer.written
+	}
+
+	metaEntry, err := archive.Create(backupMetadataEntry)
+	if err != nil {
+		return nil, err
+	}
+	encoder := json.NewEncoder(metaEntry)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(meta); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	success = true
+	log.Printf("应用备份完成: package=%s, APK=%v, 数据=%d 字节", packageName, meta.APKs, meta.DataSize)
+	return meta, nil
+}
+
+// ReadBackupMetadata 读取备份归档中的元数据
+func ReadBackupMetadata(archivePath string) (*BackupMetadata, error) {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return readBackupMetadata(&reader.Reader)
+}
+
+func readBackupMetadata(reader *zip.Reader) (*BackupMetadata, error) {
+	f, err := reader.Open(backupMetadataEntry)
+	if err != nil {
+		return nil, fmt.Errorf("不是有效的备份文件: 缺少 %s", backupMetadataEntry)
+	}
+	defer f.Close()
+
+	var meta BackupMetadata
+	if err := json.NewDecoder(f).Decode(&meta); err != nil {
+		return nil, fmt.Errorf("解析备份元数据失败: %w", err)
+	}
+	if meta.FormatVersion > backupFormatVersion {
+		return nil, fmt.Errorf("备份文件版本 %d 高于当前支持的版本 %d", meta.FormatVersion, backupFormatVersion)
+	}
+	// 元数据来自归档文件，包名会拼入设备上的 shell 命令，APK 名会拼入本地路径
+	if !packageNamePattern.MatchString(meta.PackageName) {
+		return nil, fmt.Errorf("备份元数据中的包名无效: %q", meta.PackageName)
+	}
+	for _, name := range meta.APKs {
+		if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+			return nil, fmt.Errorf("备份元数据中的APK文件名无效: %q", name)
+		}
+	}
+	return &meta, nil
+}
+
+// RestoreApp 将备份归档恢复到设备：可选先安装APK，然后强制停止应用并通过 exec-in run-as tar 解包数据
+func RestoreApp(deviceID string, archivePath string, opts RestoreOptions, progress BackupProgress, outputCallback func(line string)) error {
+	emit := func(format string, a ...any) {
+		if outputCallback != nil {
+			outputCallback(fmt.Sprintf(format, a...))
+		}
+	}
+
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	meta, err := readBackupMetadata(&reader.Reader)
+	if err != nil {
+		return err
+	}
+	log.Printf("开始恢复应用: deviceID=%s, package=%s, archive=%s, opts=%+v", deviceID, meta.PackageName, archivePath, opts)
+
+	if opts.InstallAPK && len(meta.APKs) > 0 {
+		tempDir, err := os.MkdirTemp("", "yikong-restore-*")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tempDir)
+
+		var apkFiles []string
+		for _, name := range meta.APKs {
+			local := filepath.Join(tempDir, name)
+			if err := extractArchiveEntry(&reader.Reader, backupAPKDir+name, local); err != nil {
+				return err
+			}
+			apkFiles = append(apkFiles, local)
+		}
+
+		emit("正在安装APK: %s", strings.Join(meta.APKs, ", "))
+		installProgress := func(written, total int64) {
+			if progress != nil {
+				progress(BackupStageInstall, written)
+			}
+		}
+		if _, err := InstallFiles(deviceID, apkFiles, InstallOptions{Replace: true, AllowDowngrade: true}, false, installProgress, outputCallback); err != nil {
+			return fmt.Errorf("安装APK失败: %w", err)
+		}
+	}
+
+	if !meta.HasData {
+		emit("备份中不包含应用数据，恢复完成")
+		return nil
+	}
+
+	if err := CheckRunAs(deviceID, meta.PackageName); err != nil {
+		return err
+	}
+
+	emit("正在停止应用: %s", meta.PackageName)
+	if err := ForceStop(deviceID, meta.PackageName); err != nil {
+		return err
+	}
+
+	if opts.ClearData {
+		emit("正在清除现有数据...")
+		// ./* 不匹配以 . 开头的文件和目录，用 find 删除数据目录下的全部条目
+		clear := "run-as " + shellQuote(meta.PackageName) + " sh -c " + shellQuote("cd "+shellQuote(appDataDir(meta.PackageName))+" && find . -mindepth 1 -maxdepth 1 -exec rm -rf {} +")
+		if _, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", clear}, time.Minute); err != nil {
+			return fmt.Errorf("清除现有数据失败: %w", err)
+		}
+	}
+
+	data, err := reader.Open(backupDataEntry)
+	if err != nil {
+		return fmt.Errorf("备份文件中缺少数据: %w", err)
+	}
+	defer data.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	emit("正在写入应用数据 (%d 字节)...", meta.DataSize)
+	var source io.Reader = data
+	if progress != nil {
+		source = &progressReader{reader: data, total: meta.DataSize, onProgress: func(written, _ int64) {
+			progress(BackupStageRestore, written)
+		}}
+	}
+	command := "run-as " + shellQuote(meta.PackageName) + " tar -xf - -C " + shellQuote(appDataDir(meta.PackageName))
+	if output, err := ExecIn(ctx, deviceID, command, source); err != nil {
+		return fmt.Errorf("解包应用数据失败: %w", err)
+	} else if strings.TrimSpace(output) != "" {
+		emit("%s", strings.TrimSpace(output))
+	}
+
+	emit("恢复完成: %s", meta.PackageName)
+	return nil
+}
+
+func extractArchiveEntry(reader *zip.Reader, name string, dest string) error {
+	src, err := reader.Open(name)
+	if err != nil {
+		return fmt.Errorf("备份文件中缺少 %s: %w", name, err)
+	}
+	defer src.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, src)
+	return err
+}
@@ -0,0 +1,44 @@
+package adb
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+)
+
+func TestReadBackupMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata string
+		wantErr  bool
+	}{
+		{"有效", `{"format_version":1,"package_name":"com.example.app_1","apks":["base.apk","split_config.arm64_v8a.apk"],"has_data":true}`, false},
+		{"包名含 shell 元字符", `{"format_version":1,"package_name":"com.x;reboot"}`, true},
+		{"包名含空格", `{"format_version":1,"package_name":"com.x y"}`, true},
+		{"包名为空", `{"format_version":1,"package_name":""}`, true},
+		{"包名含路径", `{"format_version":1,"package_name":"../com.x"}`, true},
+		{"APK 名含路径", `{"format_version":1,"package_name":"com.x","apks":["../../evil.apk"]}`, true},
+		{"APK 名含反斜杠", `{"format_version":1,"package_name":"com.x","apks":["..\\evil.apk"]}`, true},
+		{"版本过高", `{"format_version":99,"package_name":"com.x"}`, true},
+		{"不是 JSON", `not json`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := zip.NewWriter(&buf)
+			f, err := w.Create(backupMetadataEntry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.Write([]byte(tt.metadata))
+			w.Close()
+			reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := readBackupMetadata(reader); (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package adb
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BatteryInfo dumpsys battery 的解析结果
+type BatteryInfo struct {
+	Level       int    `json:"level"`
+	Scale       int    `json:"scale"`
+	Status      int    `json:"status"` // BatteryManager.BATTERY_STATUS_*
+	Health      int    `json:"health"` // BatteryManager.BATTERY_HEALTH_*
+	Present     bool   `json:"present"`
+	Voltage     int    `json:"voltage_mv"`
+	Temperature int    `json:"temperature"` // 单位 0.1℃
+	Technology  string `json:"technology"`
+	ACPowered   bool   `json:"ac_powered"`
+	USBPowered  bool   `json:"usb_powered"`
+	Wireless    bool   `json:"wireless_powered"`
+	Simulated   bool   `json:"simulated"` // 已通过 dumpsys battery set/unplug 模拟，不再跟随真实电池
+}
+
+// 电池状态名称，对应 BatteryManager.BATTERY_STATUS_*
+var BatteryStatusNames = map[int]string{
+	1: "未知",
+	2: "充电中",
+	3: "放电中",
+	4: "未充电",
+	5: "已充满",
+}
+
+// 电池健康状态名称，对应 BatteryManager.BATTERY_HEALTH_*
+var BatteryHealthNames = map[int]string{
+	1: "未知",
+	2: "良好",
+	3: "过热",
+	4: "损坏",
+	5: "过压",
+	6: "未知故障",
+	7: "过冷",
+}
+
+// GetBatteryInfo 读取并解析 dumpsys battery
+func GetBatteryInfo(deviceID string) (*BatteryInfo, error) {
+	result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "dumpsys", "battery"}, 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	return parseBatteryInfo(result.Output), nil
+}
+
+func parseBatteryInfo(output string) *BatteryInfo {
+	info := &BatteryInfo{}
+	for _, line := range strings.Split(output, "\n") {
+		// 模拟状态下首行为 "Current Battery Service state: (UPDATES STOPPED -- use 'reset' to restart)"
+		if strings.Contains(line, "UPDATES STOPPED") {
+			info.Simulated = true
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		number, _ := strconv.Atoi(value)
+		switch key {
+		case "level":
+			info.Level = number
+		case "scale":
+			info.Scale = number
+		case "status":
+			info.Status = number
+		case "health":
+			info.Health = number
+		case "present":
+			info.Present = value == "true"
+		case "voltage":
+			info.Voltage = number
+		case "temperature":
+			info.Temperature = number
+		case "technology":
+			info.Technology = value
+		case "AC powered":
+			info.ACPowered = value == "true"
+		case "USB powered":
+			info.USBPowered = value == "true"
+		case "Wireless powered":
+			info.Wireless = value == "true"
+		}
+	}
+	return info
+}
+
+// Percent 电量百分比
+func (b *BatteryInfo) Percent() int {
+	if b.Scale <= 0 {
+		return b.Level
+	}
+	return b.Level * 100 / b.Scale
+}
+
+// 电池状态值，对应 BatteryManager.BATTERY_STATUS_*
+const (
+	BatteryStatusCharging    = 2
+	BatteryStatusDischarging = 3
+	BatteryStatusNotCharging = 4
+	BatteryStatusFull        = 5
+)
+
+// 模拟的充电来源
+const (
+	PowerSourceAC       = "ac"
+	PowerSourceUSB      = "usb"
+	PowerSourceWireless = "wireless"
+)
+
+// PowerSources 全部充电来源
+var PowerSources = []string{PowerSourceAC, PowerSourceUSB, PowerSourceWireless}
+
+// runDumpsysBattery 执行 dumpsys battery 子命令
+func runDumpsysBattery(deviceID string, args ...string) error {
+	log.Printf("模拟电池: deviceID=%s, %s", deviceID, strings.Join(args, " "))
+	return runShellCommand(deviceID, append([]string{"dumpsys", "battery"}, args...)...)
+}
+
+// SetBatteryLevel 模拟电量（0-100）
+func SetBatteryLevel(deviceID string, level int) error {
+	if level < 0 || level > 100 {
+		return fmt.Errorf("电量应在 0 到 100 之间: %d", level)
+	}
+	return runDumpsysBattery(deviceID, "set", "level", strconv.Itoa(level))
+}
+
+// SetBatteryStatus 模拟电池状态（BatteryManager.BATTERY_STATUS_*）
+func SetBatteryStatus(deviceID string, status int) error {
+	if _, ok := BatteryStatusNames[status]; !ok {
+		return fmt.Errorf("无效的电池状态: %d", status)
+	}
+	return runDumpsysBattery(deviceID, "set", "status", strconv.Itoa(status))
+}
+
+// SetPowerSource 模拟接通或断开某个充电来源
+func SetPowerSource(deviceID string, source string, connected bool) error {
+	if !slices.Contains(PowerSources, source) {
+		return fmt.Errorf("未知的充电来源: %s", source)
+	}
+	value := "0"
+	if connected {
+		value = "1"
+	}
+	return runDumpsysBattery(deviceID, "set", source, value)
+}
+
+// UnplugBattery 模拟拔出全部电源
+func UnplugBattery(deviceID string) error {
+	return runDumpsysBattery(deviceID, "unplug")
+}
+
+// ResetBattery 取消模拟，恢复跟随真实电池状态
+func ResetBattery(deviceID string) error {
+	return runDumpsysBattery(deviceID, "reset")
+}
+
+// BatteryDrain 耗电场景：拔出电源后从 From 开始每隔 Interval 降低 Step，直到 To
+type BatteryDrain struct {
+	From     int
+	To       int
+	Step     int
+	Interval time.Duration
+}
+
+// Validate 检查耗电场景参数
+func (d BatteryDrain) Validate() error {
+	if d.From < 0 || d.From > 100 || d.To < 0 || d.To > 100 {
+		return fmt.Errorf("电量应在 0 到 100 之间")
+	}
+	if d.From < d.To {
+		return fmt.Errorf("起始电量不能低于结束电量")
+	}
+	if d.Step <= 0 {
+		return fmt.Errorf("每步降低的电量应大于 0")
+	}
+	if d.Interval < 100*time.Millisecond {
+		return fmt.Errorf("间隔不能小于 100 毫秒")
+	}
+	return nil
+}
+
+// Levels 场景中依次模拟的电量，最后一步总是 To
+func (d BatteryDrain) Levels() []int {
+	var levels []int
+	for level := d.From; level > d.To; level -= d.Step {
+		levels = append(levels, level)
+	}
+	return append(levels, d.To)
+}
+
+// RunBatteryDrain 执行耗电场景，onLevel 在设置每个电量后回调。
+// 场景结束或取消后保持模拟状态，需要时调用 ResetBattery 恢复
+func RunBatteryDrain(ctx context.Context, deviceID string, d BatteryDrain, onLevel func(level int)) error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	if err := UnplugBattery(deviceID); err != nil {
+		return err
+	}
+	if err := SetBatteryStatus(deviceID, BatteryStatusDischarging); err != nil {
+		return err
+	}
+	for i, level := range d.Levels() {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(d.Interval):
+			}
+		}
+		if err := SetBatteryLevel(deviceID, level); err != nil {
+			return err
+		}
+		if onLevel != nil {
+			onLevel(level)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package adb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseBatteryInfo(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   BatteryInfo
+	}{
+		{
+			name: "真实电池",
+			output: "Current Battery Service state:\r\n" +
+				"  AC powered: false\r\n" +
+				"  USB powered: true\r\n" +
+				"  Wireless powered: false\r\n" +
+				"  Max charging current: 500000\r\n" +
+				"  status: 2\r\n" +
+				"  health: 2\r\n" +
+				"  present: true\r\n" +
+				"  level: 87\r\n" +
+				"  scale: 100\r\n" +
+				"  voltage: 4321\r\n" +
+				"  temperature: 285\r\n" +
+				"  technology: Li-ion\r\n",
+			want: BatteryInfo{Level: 87, Scale: 100, Status: 2, Health: 2, Present: true, Voltage: 4321,
+				Temperature: 285, Technology: "Li-ion", USBPowered: true},
+		},
+		{
+			name: "模拟状态",
+			output: "Current Battery Service state: (UPDATES STOPPED -- use 'reset' to restart)\n" +
+				"  AC powered: true\n" +
+				"  level: 15\n" +
+				"  scale: 100\n",
+			want: BatteryInfo{Level: 15, Scale: 100, ACPowered: true, Simulated: true},
+		},
+		{
+			name:   "空输出",
+			output: "",
+			want:   BatteryInfo{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseBatteryInfo(tt.output); !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatteryPercent(t *testing.T) {
+	tests := []struct {
+		level, scale, want int
+	}{
+		{87, 100, 87},
+		{50, 200, 25},
+		{42, 0, 42},
+	}
+	for _, tt := range tests {
+		info := BatteryInfo{Level: tt.level, Scale: tt.scale}
+		if got := info.Percent(); got != tt.want {
+			t.Errorf("Percent(%d/%d) = %d, want %d", tt.level, tt.scale, got, tt.want)
+		}
+	}
+}
+
+func TestBatteryDrain(t *testing.T) {
+	tests := []struct {
+		name    string
+		drain   BatteryDrain
+		want    []int
+		wantErr bool
+	}{
+		{"每步 1%", BatteryDrain{From: 5, To: 1, Step: 1, Interval: time.Second}, []int{5, 4, 3, 2, 1}, false},
+		{"步长不整除", BatteryDrain{From: 30, To: 1, Step: 10, Interval: time.Second}, []int{30, 20, 10, 1}, false},
+		{"起止相同", BatteryDrain{From: 20, To: 20, Step: 5, Interval: time.Second}, []int{20}, false},
+		{"起始低于结束", BatteryDrain{From: 10, To: 20, Step: 1, Interval: time.Second}, nil, true},
+		{"电量越界", BatteryDrain{From: 101, To: 1, Step: 1, Interval: time.Second}, nil, true},
+		{"步长为 0", BatteryDrain{From: 30, To: 1, Interval: time.Second}, nil, true},
+		{"间隔过短", BatteryDrain{From: 30, To: 1, Step: 1, Interval: time.Millisecond}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.drain.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(tt.drain.Levels(), tt.want) {
+				t.Errorf("Levels() = %v, want %v", tt.drain.Levels(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package adb
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// SQLite 数据库的附属文件后缀，拉取与推送时需要与主文件一起处理
+var databaseSidecarSuffixes = []string{"-wal", "-shm", "-journal"}
+
+// AppPrivateAccess 选择访问应用私有目录的身份：可调试应用使用 run-as，
+// 否则在已 root 的设备上使用 su
+func AppPrivateAccess(deviceID string, packageName string) (FileAccess, error) {
+	if CanRunAs(deviceID, packageName) {
+		return FileAccess{Mode: AccessRunAs, Package: packageName}, nil
+	}
+	if HasRoot(deviceID) {
+		return FileAccess{Mode: AccessSu}, nil
+	}
+	return FileAccess{}, CheckRunAs(deviceID, packageName)
+}
+
+// appDatabasesDir 应用数据库目录
+func appDatabasesDir(packageName string) string {
+	return path.Join(appDataDir(packageName), "databases")
+}
+
+// ListDatabases 列出应用 databases 目录下的数据库文件（不含 -wal/-shm/-journal）
+func ListDatabases(deviceID string, packageName string, access FileAccess) ([]string, error) {
+	entries, err := ListDirAs(deviceID, appDatabasesDir(packageName), access)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || isDatabaseSidecar(entry.Name) {
+			continue
+		}
+		names = append(names, entry.Name)
+	}
+	return names, nil
+}
+
+func isDatabaseSidecar(name string) bool {
+	for _, suffix := range databaseSidecarSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// PullDatabase 将应用数据库及其 -wal/-shm 文件拉取到本地目录 localDir，返回本地主文件路径。
+// 未检查点的事务还在 -wal 中，只拉取主文件会丢失最近的修改
+func PullDatabase(ctx context.Context, deviceID string, packageName string, name string, localDir string, access FileAccess) (string, error) {
+	log.Printf("拉取数据库: deviceID=%s, package=%s, name=%s", deviceID, packageName, name)
+	dir := appDatabasesDir(packageName)
+	entries, err := ListDirAs(deviceID, dir, access)
+	if err != nil {
+		return "", err
+	}
+	existing := make(map[string]bool)
+	for _, entry := range entries {
+		existing[entry.Name] = true
+	}
+	if !existing[name] {
+		return "", fmt.Errorf("数据库不存在: %s", path.Join(dir, name))
+	}
+
+	if err := os.MkdirAll(localDir, 0755); err != nil {
+		return "", err
+	}
+	// 清理上次拉取留下的附属文件，避免旧的 -wal 被应用到新拉取的主文件上
+	for _, suffix := range databaseSidecarSuffixes {
+		os.Remove(filepath.Join(localDir, name+suffix))
+	}
+
+	for _, file := range append([]string{name}, name+"-wal", name+"-shm") {
+		if !existing[file] {
+			continue
+		}
+		if err := PullAs(ctx, deviceID, path.Join(dir, file), localDir, access, nil); err != nil {
+			return "", fmt.Errorf("拉取 %s 失败: %w", file, err)
+		}
+	}
+	return filepath.Join(localDir, name), nil
+}
+
+// PushDatabase 将本地数据库文件写回应用的 databases 目录。
+// 写入前先强制停止应用并删除设备上的 -wal/-shm/-journal，
+// 本地文件应已完成检查点，不依赖附属文件
+func PushDatabase(ctx context.Context, deviceID string, packageName string, localPath string, access FileAccess) error {
+	log.Printf("写回数据库: deviceID=%s, package=%s, local=%s", deviceID, packageName, localPath)
+	if err := ForceStop(deviceID, packageName); err != nil {
+		return fmt.Errorf("强制停止应用失败: %w", err)
+	}
+
+	dir := appDatabasesDir(packageName)
+	name := filepath.Base(localPath)
+	var targets []string
+	for _, suffix := range databaseSidecarSuffixes {
+		targets = append(targets, shellQuote(path.Join(dir, name+suffix)))
+	}
+	if err := runFileCommand(deviceID, access.Wrap("rm -f "+strings.Join(targets, " "))); err != nil {
+		return fmt.Errorf("删除附属文件失败: %w", err)
+	}
+	return PushAs(ctx, deviceID, localPath, dir, access, nil)
+}
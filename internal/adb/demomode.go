@@ -0,0 +1,158 @@
+package adb
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// DemoModeAction System UI 演示模式的广播 action
+const DemoModeAction = "com.android.systemui.demo"
+
+// DemoMobileDataTypes 演示模式中移动网络可显示的类型，none 表示不显示类型图标
+var DemoMobileDataTypes = []string{"none", "3g", "4g", "4g+", "5g", "5g+", "lte", "lte+", "e", "h", "h+"}
+
+var demoClockPattern = regexp.MustCompile(`^([01]\d|2[0-3])[0-5]\d$`)
+
+// DemoState 演示模式下状态栏显示的内容，信号强度为 -1 表示隐藏对应图标
+type DemoState struct {
+	Clock             string // HHMM，如 1200
+	BatteryLevel      int
+	BatteryPlugged    bool
+	WifiLevel         int // 0-4
+	MobileLevel       int // 0-4
+	MobileDataType    string
+	HideNotifications bool
+	HideStatusIcons   bool // 隐藏音量、蓝牙、闹钟、定位等系统图标
+}
+
+// DemoPreset 命名的演示模式预设
+type DemoPreset struct {
+	Name  string
+	State DemoState
+}
+
+// DemoPresets 常用的状态栏预设
+var DemoPresets = []DemoPreset{
+	{Name: "应用商店截图", State: DemoState{Clock: "1200", BatteryLevel: 100, WifiLevel: 4, MobileLevel: 4, MobileDataType: "none", HideNotifications: true, HideStatusIcons: true}},
+	{Name: "仅 Wi-Fi（平板）", State: DemoState{Clock: "1200", BatteryLevel: 100, WifiLevel: 4, MobileLevel: -1, MobileDataType: "none", HideNotifications: true, HideStatusIcons: true}},
+	{Name: "移动网络 5G", State: DemoState{Clock: "0941", BatteryLevel: 100, WifiLevel: -1, MobileLevel: 4, MobileDataType: "5g", HideNotifications: true, HideStatusIcons: true}},
+	{Name: "充电中", State: DemoState{Clock: "1200", BatteryLevel: 80, BatteryPlugged: true, WifiLevel: 4, MobileLevel: 4, MobileDataType: "none", HideNotifications: true, HideStatusIcons: true}},
+	{Name: "低电量", State: DemoState{Clock: "2330", BatteryLevel: 15, WifiLevel: 2, MobileLevel: 1, MobileDataType: "lte", HideNotifications: true}},
+}
+
+// Validate 检查演示状态的取值
+func (s DemoState) Validate() error {
+	if !demoClockPattern.MatchString(s.Clock) {
+		return fmt.Errorf("时间应为 HHMM 格式，如 1200: %s", s.Clock)
+	}
+	if s.BatteryLevel < 0 || s.BatteryLevel > 100 {
+		return fmt.Errorf("电量应在 0 到 100 之间: %d", s.BatteryLevel)
+	}
+	if s.WifiLevel < -1 || s.WifiLevel > 4 || s.MobileLevel < -1 || s.MobileLevel > 4 {
+		return fmt.Errorf("信号强度应在 0 到 4 之间")
+	}
+	if !slices.Contains(DemoMobileDataTypes, s.MobileDataType) {
+		return fmt.Errorf("未知的移动网络类型: %s", s.MobileDataType)
+	}
+	return nil
+}
+
+// sendDemoCommand 发送一条演示模式广播，extras 为依次排列的键和值
+func sendDemoCommand(deviceID string, command string, extras ...string) error {
+	args := []string{"am", "broadcast", "-a", DemoModeAction, "-e", "command", command}
+	for i := 0; i+1 < len(extras); i += 2 {
+		args = append(args, "-e", extras[i], extras[i+1])
+	}
+	return runShellCommand(deviceID, args...)
+}
+
+// DemoModeAllowed 是否已允许演示模式（settings global sysui_demo_allowed）
+func DemoModeAllowed(deviceID string) (bool, error) {
+	value, exists, err := GetSetting(deviceID, SettingsGlobal, "sysui_demo_allowed")
+	if err != nil {
+		return false, err
+	}
+	return exists && value == "1", nil
+}
+
+// EnterDemoMode 允许并进入演示模式，此后状态栏不再显示真实状态
+func EnterDemoMode(deviceID string) error {
+	if err := PutSetting(deviceID, SettingsGlobal, "sysui_demo_allowed", "1"); err != nil {
+		return fmt.Errorf("允许演示模式失败: %w", err)
+	}
+	log.Printf("进入演示模式: deviceID=%s", deviceID)
+	return sendDemoCommand(deviceID, "enter")
+}
+
+// ApplyDemoState 进入演示模式并设置时间、电池、网络与通知图标
+func ApplyDemoState(deviceID string, s DemoState) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if err := EnterDemoMode(deviceID); err != nil {
+		return err
+	}
+	log.Printf("设置演示状态: deviceID=%s, %+v", deviceID, s)
+
+	showOrHide := func(level int) string {
+		if level < 0 {
+			return "hide"
+		}
+		return "show"
+	}
+	hidden := func(hide bool) string {
+		if hide {
+			return "hide"
+		}
+		return "show"
+	}
+	wifi := []string{"wifi", showOrHide(s.WifiLevel)}
+	if s.WifiLevel >= 0 {
+		wifi = append(wifi, "level", strconv.Itoa(s.WifiLevel), "fully", "true")
+	}
+	mobile := []string{"mobile", showOrHide(s.MobileLevel)}
+	if s.MobileLevel >= 0 {
+		mobile = append(mobile, "level", strconv.Itoa(s.MobileLevel), "datatype", s.MobileDataType)
+	}
+
+	commands := []struct {
+		command string
+		extras  []string
+	}{
+		{"clock", []string{"hhmm", s.Clock}},
+		{"battery", []string{"level", strconv.Itoa(s.BatteryLevel), "plugged", strconv.FormatBool(s.BatteryPlugged), "powersave", "false"}},
+		{"network", append([]string{"airplane", "hide", "nosim", "hide"}, wifi...)},
+		{"network", mobile},
+		{"notifications", []string{"visible", strconv.FormatBool(!s.HideNotifications)}},
+		{"status", []string{
+			"volume", hidden(s.HideStatusIcons),
+			"bluetooth", hidden(s.HideStatusIcons),
+			"location", hidden(s.HideStatusIcons),
+			"alarm", hidden(s.HideStatusIcons),
+			"sync", hidden(s.HideStatusIcons),
+			"mute", hidden(s.HideStatusIcons),
+			"speakerphone", hidden(s.HideStatusIcons),
+		}},
+	}
+	for _, c := range commands {
+		if err := sendDemoCommand(deviceID, c.command, c.extras...); err != nil {
+			return fmt.Errorf("设置 %s 失败: %w", c.command, err)
+		}
+	}
+	return nil
+}
+
+// ExitDemoMode 退出演示模式恢复真实状态栏，disallow 为 true 时同时关闭 sysui_demo_allowed
+func ExitDemoMode(deviceID string, disallow bool) error {
+	log.Printf("退出演示模式: deviceID=%s", deviceID)
+	if err := sendDemoCommand(deviceID, "exit"); err != nil {
+		return err
+	}
+	if disallow {
+		return PutSetting(deviceID, SettingsGlobal, "sysui_demo_allowed", "0")
+	}
+	return nil
+}
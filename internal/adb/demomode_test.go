@@ -0,0 +1,36 @@
+package adb
+
+import "testing"
+
+func TestDemoStateValidate(t *testing.T) {
+	valid := DemoState{Clock: "1200", BatteryLevel: 100, WifiLevel: 4, MobileLevel: -1, MobileDataType: "none"}
+	tests := []struct {
+		name    string
+		modify  func(s *DemoState)
+		wantErr bool
+	}{
+		{"有效", func(s *DemoState) {}, false},
+		{"午夜", func(s *DemoState) { s.Clock = "0000" }, false},
+		{"时间越界", func(s *DemoState) { s.Clock = "2400" }, true},
+		{"分钟越界", func(s *DemoState) { s.Clock = "1260" }, true},
+		{"时间带冒号", func(s *DemoState) { s.Clock = "12:00" }, true},
+		{"电量越界", func(s *DemoState) { s.BatteryLevel = 101 }, true},
+		{"信号越界", func(s *DemoState) { s.WifiLevel = 5 }, true},
+		{"信号小于 -1", func(s *DemoState) { s.MobileLevel = -2 }, true},
+		{"未知网络类型", func(s *DemoState) { s.MobileDataType = "6g" }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := valid
+			tt.modify(&s)
+			if err := s.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	for _, p := range DemoPresets {
+		if err := p.State.Validate(); err != nil {
+			t.Errorf("预设 %s: %v", p.Name, err)
+		}
+	}
+}
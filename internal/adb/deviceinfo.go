@@ -0,0 +1,212 @@
+package adb
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DeviceInfo 设备硬件与系统概览，不包含 IMEI 等需要特殊权限的标识
+type DeviceInfo struct {
+	Serial          string       `json:"serial"`
+	Model           string       `json:"model"`
+	Manufacturer    string       `json:"manufacturer"`
+	Brand           string       `json:"brand"`
+	Device          string       `json:"device"`
+	AndroidVersion  string       `json:"android_version"`
+	SDK             int          `json:"sdk"`
+	SecurityPatch   string       `json:"security_patch"`
+	Fingerprint     string       `json:"build_fingerprint"`
+	BuildID         string       `json:"build_id"`
+	BuildType       string       `json:"build_type"`
+	Kernel          string       `json:"kernel"`
+	ABIs            []string     `json:"abis"`
+	CPUCores        int          `json:"cpu_cores"`
+	CPUHardware     string       `json:"cpu_hardware"`
+	RAMTotal        int64        `json:"ram_total"`
+	RAMAvailable    int64        `json:"ram_available"`
+	StorageTotal    int64        `json:"storage_total"`
+	StorageFree     int64        `json:"storage_free"`
+	ScreenWidth     int          `json:"screen_width"`
+	ScreenHeight    int          `json:"screen_height"`
+	Density         int          `json:"density"`
+	DensityOverride int          `json:"density_override,omitempty"`
+	Battery         *BatteryInfo `json:"battery,omitempty"`
+	AndroidID       string       `json:"android_id"`
+	Rooted          bool         `json:"rooted"`
+	Uptime          string       `json:"uptime"`
+	CollectedAt     time.Time    `json:"collected_at"`
+}
+
+var (
+	memInfoPattern  = regexp.MustCompile(`(?m)^(MemTotal|MemAvailable):\s+(\d+) kB`)
+	cpuRangePattern = regexp.MustCompile(`^(\d+)(?:-(\d+))?$`)
+	hardwarePattern = regexp.MustCompile(`(?m)^Hardware\s*:\s*(.+)$`)
+)
+
+// GetDeviceInfo 从 getprop、/proc、wm 与 dumpsys 收集设备概览。
+// 各项互不依赖，并发获取；单项失败只记录日志，不影响其它项
+func GetDeviceInfo(deviceID string) (*DeviceInfo, error) {
+	props, err := GetProperties(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	info := &DeviceInfo{
+		Serial:         deviceID,
+		Model:          props["ro.product.model"],
+		Manufacturer:   props["ro.product.manufacturer"],
+		Brand:          props["ro.product.brand"],
+		Device:         props["ro.product.device"],
+		AndroidVersion: props["ro.build.version.release"],
+		SecurityPatch:  props["ro.build.version.security_patch"],
+		Fingerprint:    props["ro.build.fingerprint"],
+		BuildID:        props["ro.build.display.id"],
+		BuildType:      props["ro.build.type"],
+		CPUHardware:    props["ro.hardware"],
+		CollectedAt:    time.Now(),
+	}
+	if serial := props["ro.serialno"]; serial != "" {
+		info.Serial = serial
+	}
+	info.SDK, _ = strconv.Atoi(props["ro.build.version.sdk"])
+	for _, abi := range strings.Split(props["ro.product.cpu.abilist"], ",") {
+		if abi = strings.TrimSpace(abi); abi != "" {
+			info.ABIs = append(info.ABIs, abi)
+		}
+	}
+	if len(info.ABIs) == 0 && props["ro.product.cpu.abi"] != "" {
+		info.ABIs = []string{props["ro.product.cpu.abi"]}
+	}
+
+	shell := func(command string) string {
+		result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", command}, 10*time.Second)
+		if err != nil {
+			log.Printf("获取设备信息失败: deviceID=%s, command=%s, 错误: %v", deviceID, command, err)
+			return ""
+		}
+		return result.Output
+	}
+
+	var wg sync.WaitGroup
+	run := func(f func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f()
+		}()
+	}
+	run(func() {
+		for _, match := range memInfoPattern.FindAllStringSubmatch(shell("cat /proc/meminfo"), -1) {
+			kb, _ := strconv.ParseInt(match[2], 10, 64)
+			if match[1] == "MemTotal" {
+				info.RAMTotal = kb * 1024
+			} else {
+				info.RAMAvailable = kb * 1024
+			}
+		}
+	})
+	run(func() {
+		info.CPUCores = parseCPURange(strings.TrimSpace(shell("cat /sys/devices/system/cpu/possible")))
+		if match := hardwarePattern.FindStringSubmatch(shell("cat /proc/cpuinfo")); match != nil {
+			info.CPUHardware = strings.TrimSpace(match[1])
+		} else if platform := props["ro.board.platform"]; platform != "" && info.CPUHardware == "" {
+			info.CPUHardware = platform
+		}
+	})
+	run(func() {
+		info.StorageTotal, info.StorageFree = parseDataPartition(shell("df -k /data"))
+	})
+	run(func() {
+		if display, err := GetDisplayInfo(deviceID); err == nil {
+			info.ScreenWidth, info.ScreenHeight = display.Width, display.Height
+		}
+		if physical, override, err := GetDensity(deviceID); err == nil {
+			info.Density, info.DensityOverride = physical, override
+		}
+	})
+	run(func() {
+		if battery, err := GetBatteryInfo(deviceID); err == nil {
+			info.Battery = battery
+		}
+	})
+	run(func() {
+		info.Kernel = strings.TrimSpace(shell("uname -r"))
+		info.AndroidID = strings.TrimSpace(shell("settings get secure android_id"))
+		info.Uptime = formatUptime(shell("cat /proc/uptime"))
+	})
+	run(func() {
+		info.Rooted = HasRoot(deviceID)
+	})
+	wg.Wait()
+
+	log.Printf("设备信息: deviceID=%s, %s %s, Android %s (SDK %d)", deviceID, info.Manufacturer, info.Model, info.AndroidVersion, info.SDK)
+	return info, nil
+}
+
+// parseCPURange 解析 /sys/devices/system/cpu/possible（如 0-7 或 0-3,4-7）得到核心数
+func parseCPURange(text string) int {
+	count := 0
+	for _, part := range strings.Split(text, ",") {
+		match := cpuRangePattern.FindStringSubmatch(strings.TrimSpace(part))
+		if match == nil {
+			continue
+		}
+		from, _ := strconv.Atoi(match[1])
+		to := from
+		if match[2] != "" {
+			to, _ = strconv.Atoi(match[2])
+		}
+		count += to - from + 1
+	}
+	return count
+}
+
+// parseDataPartition 解析 df -k 的最后一行，返回总容量与可用容量（字节）
+func parseDataPartition(output string) (int64, int64) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 2 {
+		return 0, 0
+	}
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 4 {
+		return 0, 0
+	}
+	total, _ := strconv.ParseInt(fields[1], 10, 64)
+	free, _ := strconv.ParseInt(fields[3], 10, 64)
+	return total * 1024, free * 1024
+}
+
+// formatUptime 将 /proc/uptime 的秒数格式化为“3天 4小时 5分钟”
+func formatUptime(output string) string {
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return ""
+	}
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return ""
+	}
+	d := time.Duration(seconds) * time.Second
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	if days > 0 {
+		return fmt.Sprintf("%d天 %d小时 %d分钟", days, hours, minutes)
+	}
+	return fmt.Sprintf("%d小时 %d分钟", hours, minutes)
+}
+
+// ExportDeviceInfo 将设备信息导出为 JSON 文件
+func ExportDeviceInfo(path string, info *DeviceInfo) error {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
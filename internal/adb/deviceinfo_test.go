@@ -0,0 +1,47 @@
+package adb
+
+import "testing"
+
+func TestParseCPURange(t *testing.T) {
+	tests := map[string]int{
+		"0-7\n":   8,
+		"0-3,4-7": 8,
+		"0":       1,
+		"0-3,6":   5,
+		"":        0,
+		"garbage": 0,
+	}
+	for text, want := range tests {
+		if got := parseCPURange(text); got != want {
+			t.Errorf("parseCPURange(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestParseDataPartition(t *testing.T) {
+	output := "Filesystem       1K-blocks     Used Available Use% Mounted on\n" +
+		"/dev/block/dm-45 115249236 40123456  75125780  35% /data\n"
+	total, free := parseDataPartition(output)
+	if total != 115249236*1024 || free != 75125780*1024 {
+		t.Errorf("got %d, %d", total, free)
+	}
+	for _, bad := range []string{"", "Filesystem 1K-blocks\n", "header\n/dev/x 1\n"} {
+		if total, free := parseDataPartition(bad); total != 0 || free != 0 {
+			t.Errorf("parseDataPartition(%q) = %d, %d", bad, total, free)
+		}
+	}
+}
+
+func TestFormatUptime(t *testing.T) {
+	tests := map[string]string{
+		"350000.12 1234567.89\n": "4天 1小时 13分钟",
+		"3725.5 100":             "1小时 2分钟",
+		"":                       "",
+		"abc":                    "",
+	}
+	for output, want := range tests {
+		if got := formatUptime(output); got != want {
+			t.Errorf("formatUptime(%q) = %q, want %q", output, got, want)
+		}
+	}
+}
@@ -0,0 +1,377 @@
+package adb
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 同步方向
+const (
+	SyncPush          = "push" // 本地 → 设备
+	SyncPull          = "pull" // 设备 → 本地
+	SyncBidirectional = "both" // 双向，较新的一侧覆盖另一侧
+)
+
+// 文件比较方式
+const (
+	CompareSizeMtime = "size_mtime"
+	CompareMD5       = "md5"
+	CompareSHA256    = "sha256"
+)
+
+// 同步动作
+const (
+	ActionPush         = "push"
+	ActionPull         = "pull"
+	ActionDeleteRemote = "delete_remote"
+	ActionDeleteLocal  = "delete_local"
+)
+
+// mtimeTolerance 修改时间比较的容差，FAT 类文件系统只有2秒精度
+const mtimeTolerance = 2 * time.Second
+
+// 设备端单次哈希的文件数，避免命令行过长
+const hashBatchSize = 50
+
+// DirSyncOptions 目录同步选项
+type DirSyncOptions struct {
+	Direction    string // SyncPush / SyncPull / SyncBidirectional
+	Compare      string // CompareSizeMtime / CompareMD5 / CompareSHA256
+	DeleteExtras bool   // 删除目标端多余的文件（双向同步时忽略）
+}
+
+// SyncAction 同步计划中的一项操作，RelPath 使用 / 分隔
+type SyncAction struct {
+	Kind    string
+	RelPath string
+	Size    int64
+	Reason  string
+}
+
+// SyncPlan 同步计划（dry-run 结果）
+type SyncPlan struct {
+	DeviceID   string
+	LocalRoot  string
+	RemoteRoot string
+	Actions    []SyncAction
+	Unchanged  int
+}
+
+// syncFileInfo 比较用的文件信息
+type syncFileInfo struct {
+	size    int64
+	modTime time.Time
+}
+
+// Summary 计划的统计描述
+func (p *SyncPlan) Summary() string {
+	counts := make(map[string]int)
+	var bytes int64
+	for _, a := range p.Actions {
+		counts[a.Kind]++
+		if a.Kind == ActionPush || a.Kind == ActionPull {
+			bytes += a.Size
+		}
+	}
+	return fmt.Sprintf("推送 %d，拉取 %d，删除设备文件 %d，删除本地文件 %d，未变化 %d，需传输 %d 字节",
+		counts[ActionPush], counts[ActionPull], counts[ActionDeleteRemote], counts[ActionDeleteLocal], p.Unchanged, bytes)
+}
+
+// PlanDirSync 比较本地目录与设备目录，生成同步计划但不做任何修改
+func PlanDirSync(ctx context.Context, deviceID string, localRoot string, remoteRoot string, opts DirSyncOptions) (*SyncPlan, error) {
+	log.Printf("生成目录同步计划: deviceID=%s, local=%s, remote=%s, opts=%+v", deviceID, localRoot, remoteRoot, opts)
+
+	local, err := walkLocalTree(localRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := OpenSync(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	remote := make(map[string]syncFileInfo)
+	if _, statErr := s.Stat(remoteRoot); statErr == nil {
+		err = walkRemoteTree(s, remoteRoot, "", remote)
+	} else if errors.Is(statErr, fs.ErrNotExist) {
+		err = checkMissingRemoteRoot(remoteRoot, opts.Direction)
+	} else {
+		err = statErr
+	}
+	s.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	// 只有大小相同的文件才需要计算哈希
+	var hashLocal, hashRemote map[string]string
+	if opts.Compare == CompareMD5 || opts.Compare == CompareSHA256 {
+		var candidates []string
+		for rel, l := range local {
+			if r, ok := remote[rel]; ok && r.size == l.size {
+				candidates = append(candidates, rel)
+			}
+		}
+		sort.Strings(candidates)
+		if hashLocal, err = hashLocalFiles(localRoot, candidates, opts.Compare); err != nil {
+			return nil, err
+		}
+		if hashRemote, err = hashRemoteFiles(deviceID, remoteRoot, candidates, opts.Compare); err != nil {
+			return nil, err
+		}
+	}
+
+	plan := &SyncPlan{DeviceID: deviceID, LocalRoot: localRoot, RemoteRoot: remoteRoot}
+	planSyncActions(plan, local, remote, hashLocal, hashRemote, opts)
+	log.Printf("目录同步计划: %s", plan.Summary())
+	return plan, nil
+}
+
+// checkMissingRemoteRoot 设备目录不存在时，只有推送可以继续（推送时会创建该目录）。
+// 拉取或双向同步时若当作空目录处理，开启删除多余文件后会删光本地目录
+func checkMissingRemoteRoot(remoteRoot string, direction string) error {
+	if direction == SyncPush {
+		return nil
+	}
+	return fmt.Errorf("设备目录不存在: %s: %w", remoteRoot, fs.ErrNotExist)
+}
+
+// planSyncActions 根据两侧的文件信息（及可选的哈希）填充同步计划
+func planSyncActions(plan *SyncPlan, local, remote map[string]syncFileInfo, hashLocal, hashRemote map[string]string, opts DirSyncOptions) {
+	for rel, l := range local {
+		r, ok := remote[rel]
+		if !ok {
+			switch opts.Direction {
+			case SyncPush, SyncBidirectional:
+				plan.Actions = append(plan.Actions, SyncAction{Kind: ActionPush, RelPath: rel, Size: l.size, Reason: "设备上不存在"})
+			case SyncPull:
+				if opts.DeleteExtras {
+					plan.Actions = append(plan.Actions, SyncAction{Kind: ActionDeleteLocal, RelPath: rel, Size: l.size, Reason: "设备上不存在"})
+				}
+			}
+			continue
+		}
+
+		reason := ""
+		switch {
+		case l.size != r.size:
+			reason = fmt.Sprintf("大小不同 (本地 %d / 设备 %d)", l.size, r.size)
+		case hashLocal != nil:
+			if hashLocal[rel] != hashRemote[rel] {
+				reason = "内容哈希不同"
+			}
+		case absDuration(l.modTime.Sub(r.modTime)) > mtimeTolerance:
+			reason = "修改时间不同"
+		}
+		if reason == "" {
+			plan.Unchanged++
+			continue
+		}
+
+		kind := ActionPush
+		switch opts.Direction {
+		case SyncPull:
+			kind = ActionPull
+		case SyncBidirectional:
+			if r.modTime.After(l.modTime) {
+				kind = ActionPull
+			}
+		}
+		size := l.size
+		if kind == ActionPull {
+			size = r.size
+		}
+		plan.Actions = append(plan.Actions, SyncAction{Kind: kind, RelPath: rel, Size: size, Reason: reason})
+	}
+
+	for rel, r := range remote {
+		if _, ok := local[rel]; ok {
+			continue
+		}
+		switch opts.Direction {
+		case SyncPull, SyncBidirectional:
+			plan.Actions = append(plan.Actions, SyncAction{Kind: ActionPull, RelPath: rel, Size: r.size, Reason: "本地不存在"})
+		case SyncPush:
+			if opts.DeleteExtras {
+				plan.Actions = append(plan.Actions, SyncAction{Kind: ActionDeleteRemote, RelPath: rel, Size: r.size, Reason: "本地不存在"})
+			}
+		}
+	}
+
+	sort.Slice(plan.Actions, func(i, j int) bool {
+		return plan.Actions[i].RelPath < plan.Actions[j].RelPath
+	})
+}
+
+// ExecuteDirSync 按计划执行同步，onAction 在每项操作完成后回调（err 为该项的错误）。
+// 单项失败不会中止后续操作，全部完成后返回失败数量的汇总错误
+func ExecuteDirSync(ctx context.Context, plan *SyncPlan, progress SyncProgress, onAction func(action SyncAction, err error)) error {
+	log.Printf("执行目录同步: deviceID=%s, 操作数=%d", plan.DeviceID, len(plan.Actions))
+	s, err := OpenSync(ctx, plan.DeviceID)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	failed := 0
+	for _, action := range plan.Actions {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		localPath := filepath.Join(plan.LocalRoot, filepath.FromSlash(action.RelPath))
+		remotePath := path.Join(plan.RemoteRoot, action.RelPath)
+
+		var err error
+		switch action.Kind {
+		case ActionPush:
+			err = s.PushFile(localPath, remotePath, progress)
+		case ActionPull:
+			if err = os.MkdirAll(filepath.Dir(localPath), 0755); err == nil {
+				err = s.PullFile(remotePath, localPath, progress)
+			}
+		case ActionDeleteRemote:
+			err = RemovePath(plan.DeviceID, remotePath)
+		case ActionDeleteLocal:
+			err = os.Remove(localPath)
+		}
+		if err != nil {
+			failed++
+			log.Printf("同步操作失败: %s %s, 错误: %v", action.Kind, action.RelPath, err)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+		if onAction != nil {
+			onAction(action, err)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d 项操作失败", failed)
+	}
+	return nil
+}
+
+// walkLocalTree 收集本地目录下所有普通文件，键为 / 分隔的相对路径
+func walkLocalTree(root string) (map[string]syncFileInfo, error) {
+	files := make(map[string]syncFileInfo)
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = syncFileInfo{size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	return files, err
+}
+
+// walkRemoteTree 通过 sync LIST 递归收集设备目录下所有普通文件
+func walkRemoteTree(s *SyncConn, root string, rel string, files map[string]syncFileInfo) error {
+	entries, err := s.List(path.Join(root, rel))
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		child := path.Join(rel, entry.Name)
+		switch {
+		case entry.IsDir():
+			if err := walkRemoteTree(s, root, child, files); err != nil {
+				return err
+			}
+		case entry.Mode.IsRegular():
+			files[child] = syncFileInfo{size: entry.Size, modTime: entry.ModTime}
+		}
+	}
+	return nil
+}
+
+func newHash(algorithm string) hash.Hash {
+	if algorithm == CompareSHA256 {
+		return sha256.New()
+	}
+	return md5.New()
+}
+
+// hashLocalFiles 计算本地文件哈希
+func hashLocalFiles(root string, rels []string, algorithm string) (map[string]string, error) {
+	sums := make(map[string]string, len(rels))
+	for _, rel := range rels {
+		file, err := os.Open(filepath.Join(root, filepath.FromSlash(rel)))
+		if err != nil {
+			return nil, err
+		}
+		h := newHash(algorithm)
+		_, err = io.Copy(h, file)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+		sums[rel] = hex.EncodeToString(h.Sum(nil))
+	}
+	return sums, nil
+}
+
+// hashRemoteFiles 在设备上批量执行 md5sum/sha256sum，输出格式为 "<hash>  <path>"
+func hashRemoteFiles(deviceID string, root string, rels []string, algorithm string) (map[string]string, error) {
+	tool := "md5sum"
+	if algorithm == CompareSHA256 {
+		tool = "sha256sum"
+	}
+
+	sums := make(map[string]string, len(rels))
+	for start := 0; start < len(rels); start += hashBatchSize {
+		end := min(start+hashBatchSize, len(rels))
+		var command strings.Builder
+		command.WriteString("cd " + shellQuote(root) + " && " + tool)
+		for _, rel := range rels[start:end] {
+			command.WriteString(" " + shellQuote("./"+rel))
+		}
+
+		result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", command.String()}, 5*time.Minute)
+		if err != nil {
+			if result != nil && strings.TrimSpace(result.ErrorOutput) != "" {
+				return nil, fmt.Errorf("%s 执行失败: %s", tool, strings.TrimSpace(result.ErrorOutput))
+			}
+			return nil, err
+		}
+		for _, line := range strings.Split(result.Output, "\n") {
+			sum, file, ok := strings.Cut(strings.TrimSpace(line), "  ")
+			if !ok {
+				continue
+			}
+			sums[strings.TrimPrefix(file, "./")] = sum
+		}
+	}
+	return sums, nil
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,144 @@
+package adb
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPlanSyncActions(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	older := base.Add(-time.Hour)
+	file := func(size int64, mod time.Time) syncFileInfo { return syncFileInfo{size: size, modTime: mod} }
+
+	local := map[string]syncFileInfo{
+		"same.txt":       file(10, base),
+		"jitter.txt":     file(10, base.Add(time.Second)), // 在 mtime 容差内
+		"size.txt":       file(10, base),
+		"local_new.txt":  file(5, older),
+		"dir/mtime.txt":  file(7, base),
+		"dir/hashed.bin": file(3, base),
+	}
+	remote := map[string]syncFileInfo{
+		"same.txt":       file(10, base),
+		"jitter.txt":     file(10, base),
+		"size.txt":       file(20, older),
+		"remote_new.txt": file(8, base),
+		"dir/mtime.txt":  file(7, older),
+		"dir/hashed.bin": file(3, base),
+	}
+
+	tests := []struct {
+		name          string
+		opts          DirSyncOptions
+		hashL, hashR  map[string]string
+		want          []SyncAction
+		wantUnchanged int
+	}{
+		{
+			name: "推送",
+			opts: DirSyncOptions{Direction: SyncPush, Compare: CompareSizeMtime},
+			want: []SyncAction{
+				{Kind: ActionPush, RelPath: "dir/mtime.txt", Size: 7, Reason: "修改时间不同"},
+				{Kind: ActionPush, RelPath: "local_new.txt", Size: 5, Reason: "设备上不存在"},
+				{Kind: ActionPush, RelPath: "size.txt", Size: 10, Reason: "大小不同 (本地 10 / 设备 20)"},
+			},
+			wantUnchanged: 3,
+		},
+		{
+			name: "推送并删除设备上多余的文件",
+			opts: DirSyncOptions{Direction: SyncPush, Compare: CompareSizeMtime, DeleteExtras: true},
+			want: []SyncAction{
+				{Kind: ActionPush, RelPath: "dir/mtime.txt", Size: 7, Reason: "修改时间不同"},
+				{Kind: ActionPush, RelPath: "local_new.txt", Size: 5, Reason: "设备上不存在"},
+				{Kind: ActionDeleteRemote, RelPath: "remote_new.txt", Size: 8, Reason: "本地不存在"},
+				{Kind: ActionPush, RelPath: "size.txt", Size: 10, Reason: "大小不同 (本地 10 / 设备 20)"},
+			},
+			wantUnchanged: 3,
+		},
+		{
+			name: "拉取并删除本地多余的文件",
+			opts: DirSyncOptions{Direction: SyncPull, Compare: CompareSizeMtime, DeleteExtras: true},
+			want: []SyncAction{
+				{Kind: ActionPull, RelPath: "dir/mtime.txt", Size: 7, Reason: "修改时间不同"},
+				{Kind: ActionDeleteLocal, RelPath: "local_new.txt", Size: 5, Reason: "设备上不存在"},
+				{Kind: ActionPull, RelPath: "remote_new.txt", Size: 8, Reason: "本地不存在"},
+				{Kind: ActionPull, RelPath: "size.txt", Size: 20, Reason: "大小不同 (本地 10 / 设备 20)"},
+			},
+			wantUnchanged: 3,
+		},
+		{
+			name: "双向同步以较新的一侧为准，不删除文件",
+			opts: DirSyncOptions{Direction: SyncBidirectional, Compare: CompareSizeMtime, DeleteExtras: true},
+			want: []SyncAction{
+				{Kind: ActionPush, RelPath: "dir/mtime.txt", Size: 7, Reason: "修改时间不同"},
+				{Kind: ActionPush, RelPath: "local_new.txt", Size: 5, Reason: "设备上不存在"},
+				{Kind: ActionPull, RelPath: "remote_new.txt", Size: 8, Reason: "本地不存在"},
+				{Kind: ActionPush, RelPath: "size.txt", Size: 10, Reason: "大小不同 (本地 10 / 设备 20)"},
+			},
+			wantUnchanged: 3,
+		},
+		{
+			name:  "哈希比较忽略修改时间",
+			opts:  DirSyncOptions{Direction: SyncPush, Compare: CompareMD5},
+			hashL: map[string]string{"same.txt": "a", "jitter.txt": "b", "dir/mtime.txt": "c", "dir/hashed.bin": "d"},
+			hashR: map[string]string{"same.txt": "a", "jitter.txt": "b", "dir/mtime.txt": "c", "dir/hashed.bin": "x"},
+			want: []SyncAction{
+				{Kind: ActionPush, RelPath: "dir/hashed.bin", Size: 3, Reason: "内容哈希不同"},
+				{Kind: ActionPush, RelPath: "local_new.txt", Size: 5, Reason: "设备上不存在"},
+				{Kind: ActionPush, RelPath: "size.txt", Size: 10, Reason: "大小不同 (本地 10 / 设备 20)"},
+			},
+			wantUnchanged: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := &SyncPlan{}
+			planSyncActions(plan, local, remote, tt.hashL, tt.hashR, tt.opts)
+			if !reflect.DeepEqual(plan.Actions, tt.want) {
+				t.Errorf("actions:\ngot  %+v\nwant %+v", plan.Actions, tt.want)
+			}
+			if plan.Unchanged != tt.wantUnchanged {
+				t.Errorf("unchanged = %d, want %d", plan.Unchanged, tt.wantUnchanged)
+			}
+		})
+	}
+}
+
+func TestPlanSyncActionsEmptyRemote(t *testing.T) {
+	local := map[string]syncFileInfo{"a": {size: 1}, "b/c": {size: 2}}
+	plan := &SyncPlan{}
+	planSyncActions(plan, local, map[string]syncFileInfo{}, nil, nil, DirSyncOptions{Direction: SyncPush, DeleteExtras: true})
+	for _, a := range plan.Actions {
+		if a.Kind != ActionPush {
+			t.Errorf("设备目录为空时推送不应产生 %s: %+v", a.Kind, a)
+		}
+	}
+	if len(plan.Actions) != 2 {
+		t.Errorf("got %d actions, want 2", len(plan.Actions))
+	}
+}
+
+func TestCheckMissingRemoteRoot(t *testing.T) {
+	tests := []struct {
+		direction string
+		wantErr   bool
+	}{
+		{SyncPush, false},
+		{SyncPull, true},
+		{SyncBidirectional, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.direction, func(t *testing.T) {
+			err := checkMissingRemoteRoot("/sdcard/typo", tt.direction)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("错误应包装 fs.ErrNotExist: %v", err)
+			}
+		})
+	}
+}
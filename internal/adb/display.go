@@ -0,0 +1,214 @@
+package adb
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetDensity 通过 wm density 获取物理密度与覆盖值（未覆盖时为 0）
+func GetDensity(deviceID string) (int, int, error) {
+	result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "wm", "density"}, 10*time.Second)
+	if err != nil {
+		return 0, 0, err
+	}
+	physical, override := parseWMDensities(result.Output)
+	if physical == 0 {
+		return 0, 0, fmt.Errorf("无法解析屏幕密度: %s", strings.TrimSpace(result.Output))
+	}
+	return physical, override, nil
+}
+
+// parseWMDensities 解析 wm density 输出中的物理密度与覆盖值
+func parseWMDensities(output string) (int, int) {
+	var physical, override int
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		switch key {
+		case "Physical density":
+			physical = n
+		case "Override density":
+			override = n
+		}
+	}
+	return physical, override
+}
+
+// DisplayPreset 常见机型的分辨率与密度
+type DisplayPreset struct {
+	Name    string
+	Width   int
+	Height  int
+	Density int
+	Tablet  bool
+}
+
+// String 预设在列表中的显示名称
+func (p DisplayPreset) String() string {
+	return fmt.Sprintf("%s（%dx%d, %d dpi）", p.Name, p.Width, p.Height, p.Density)
+}
+
+// DisplayPresets 常见手机与平板的屏幕参数（竖屏自然方向）
+var DisplayPresets = []DisplayPreset{
+	{Name: "小屏手机 HD", Width: 720, Height: 1280, Density: 320},
+	{Name: "标准手机 FHD", Width: 1080, Height: 1920, Density: 420},
+	{Name: "全面屏手机 FHD+", Width: 1080, Height: 2400, Density: 420},
+	{Name: "大屏手机 QHD+", Width: 1440, Height: 3120, Density: 560},
+	{Name: "7 寸平板", Width: 800, Height: 1280, Density: 213, Tablet: true},
+	{Name: "8 寸平板", Width: 1200, Height: 1920, Density: 320, Tablet: true},
+	{Name: "10 寸平板", Width: 1600, Height: 2560, Density: 320, Tablet: true},
+	{Name: "折叠屏（展开）", Width: 1840, Height: 2208, Density: 420, Tablet: true},
+}
+
+// FontScales 系统设置中提供的字体缩放档位
+var FontScales = []float64{0.85, 1.0, 1.15, 1.3, 1.5, 1.8, 2.0}
+
+// RotationNames 旋转方向 0-3 的显示名称
+var RotationNames = []string{"0°（竖屏）", "90°（横屏）", "180°（反向竖屏）", "270°（反向横屏）"}
+
+// DisplayState 屏幕尺寸、密度、旋转与字体缩放的当前状态，覆盖值为 0 表示未覆盖
+type DisplayState struct {
+	PhysicalWidth   int
+	PhysicalHeight  int
+	OverrideWidth   int
+	OverrideHeight  int
+	PhysicalDensity int
+	OverrideDensity int
+	AutoRotate      bool    // settings system accelerometer_rotation
+	UserRotation    int     // settings system user_rotation，关闭自动旋转时生效
+	Rotation        int     // 当前实际旋转方向
+	FontScale       float64 // settings system font_scale
+}
+
+// GetDisplayState 读取 wm size、wm density 以及旋转和字体缩放设置
+func GetDisplayState(deviceID string) (*DisplayState, error) {
+	result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "wm", "size"}, 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	state := &DisplayState{FontScale: 1}
+	state.PhysicalWidth, state.PhysicalHeight, state.OverrideWidth, state.OverrideHeight = parseWMSizes(result.Output)
+	if state.PhysicalWidth == 0 {
+		return nil, fmt.Errorf("无法解析屏幕尺寸: %s", strings.TrimSpace(result.Output))
+	}
+	if state.PhysicalDensity, state.OverrideDensity, err = GetDensity(deviceID); err != nil {
+		return nil, err
+	}
+
+	// 设置项不存在时使用系统默认值：自动旋转开启、字体缩放 1.0
+	if value, exists, err := GetSetting(deviceID, SettingsSystem, "accelerometer_rotation"); err == nil {
+		state.AutoRotate = !exists || value != "0"
+	}
+	if value, exists, err := GetSetting(deviceID, SettingsSystem, "user_rotation"); err == nil && exists {
+		state.UserRotation, _ = strconv.Atoi(value)
+		state.UserRotation %= 4
+	}
+	if value, exists, err := GetSetting(deviceID, SettingsSystem, "font_scale"); err == nil && exists {
+		if scale, err := strconv.ParseFloat(value, 64); err == nil && scale > 0 {
+			state.FontScale = scale
+		}
+	}
+	state.Rotation = getDisplayRotation(deviceID)
+	return state, nil
+}
+
+// parseWMSizes 解析 wm size 输出中的物理尺寸与覆盖尺寸
+func parseWMSizes(output string) (physicalWidth, physicalHeight, overrideWidth, overrideHeight int) {
+	for _, match := range wmSizePattern.FindAllStringSubmatch(output, -1) {
+		width, _ := strconv.Atoi(match[2])
+		height, _ := strconv.Atoi(match[3])
+		if match[1] == "Override" {
+			overrideWidth, overrideHeight = width, height
+		} else {
+			physicalWidth, physicalHeight = width, height
+		}
+	}
+	return
+}
+
+// SetDisplaySize 覆盖屏幕分辨率（wm size WxH）
+func SetDisplaySize(deviceID string, width, height int) error {
+	if width < 240 || height < 240 || width > 8192 || height > 8192 {
+		return fmt.Errorf("分辨率应在 240 到 8192 之间: %dx%d", width, height)
+	}
+	log.Printf("设置分辨率: deviceID=%s, %dx%d", deviceID, width, height)
+	return runShellCommand(deviceID, "wm", "size", fmt.Sprintf("%dx%d", width, height))
+}
+
+// ResetDisplaySize 恢复物理分辨率（wm size reset）
+func ResetDisplaySize(deviceID string) error {
+	log.Printf("恢复分辨率: deviceID=%s", deviceID)
+	return runShellCommand(deviceID, "wm", "size", "reset")
+}
+
+// SetDensity 覆盖屏幕密度（wm density N）
+func SetDensity(deviceID string, density int) error {
+	if density < 72 || density > 1000 {
+		return fmt.Errorf("密度应在 72 到 1000 之间: %d", density)
+	}
+	log.Printf("设置密度: deviceID=%s, %d", deviceID, density)
+	return runShellCommand(deviceID, "wm", "density", strconv.Itoa(density))
+}
+
+// ResetDensity 恢复物理密度（wm density reset）
+func ResetDensity(deviceID string) error {
+	log.Printf("恢复密度: deviceID=%s", deviceID)
+	return runShellCommand(deviceID, "wm", "density", "reset")
+}
+
+// SetAutoRotate 开启或关闭自动旋转（关闭即锁定当前方向）
+func SetAutoRotate(deviceID string, enabled bool) error {
+	value := "0"
+	if enabled {
+		value = "1"
+	}
+	return PutSetting(deviceID, SettingsSystem, "accelerometer_rotation", value)
+}
+
+// SetRotation 关闭自动旋转并强制旋转到指定方向（0-3）。
+// 应用声明了固定方向时，系统仍会按应用的要求显示
+func SetRotation(deviceID string, rotation int) error {
+	if rotation < 0 || rotation > 3 {
+		return fmt.Errorf("无效的旋转方向: %d", rotation)
+	}
+	if err := SetAutoRotate(deviceID, false); err != nil {
+		return err
+	}
+	return PutSetting(deviceID, SettingsSystem, "user_rotation", strconv.Itoa(rotation))
+}
+
+// SetFontScale 修改字体缩放（settings system font_scale）
+func SetFontScale(deviceID string, scale float64) error {
+	if scale < 0.5 || scale > 3 {
+		return fmt.Errorf("字体缩放应在 0.5 到 3 之间: %g", scale)
+	}
+	return PutSetting(deviceID, SettingsSystem, "font_scale", strconv.FormatFloat(scale, 'f', -1, 64))
+}
+
+// ResetDisplay 恢复分辨率、密度、字体缩放，并重新开启自动旋转
+func ResetDisplay(deviceID string) error {
+	var errs []error
+	if err := ResetDisplaySize(deviceID); err != nil {
+		errs = append(errs, fmt.Errorf("恢复分辨率失败: %w", err))
+	}
+	if err := ResetDensity(deviceID); err != nil {
+		errs = append(errs, fmt.Errorf("恢复密度失败: %w", err))
+	}
+	if err := SetFontScale(deviceID, 1); err != nil {
+		errs = append(errs, fmt.Errorf("恢复字体缩放失败: %w", err))
+	}
+	if err := SetAutoRotate(deviceID, true); err != nil {
+		errs = append(errs, fmt.Errorf("开启自动旋转失败: %w", err))
+	}
+	return errors.Join(errs...)
+}
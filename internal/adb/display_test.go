@@ -0,0 +1,38 @@
+package adb
+
+import "testing"
+
+func TestParseWMSizes(t *testing.T) {
+	tests := []struct {
+		output         string
+		pw, ph, ow, oh int
+	}{
+		{"Physical size: 1080x2400\n", 1080, 2400, 0, 0},
+		{"Physical size: 1080x2400\r\nOverride size: 720x1600\r\n", 1080, 2400, 720, 1600},
+		{"", 0, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		pw, ph, ow, oh := parseWMSizes(tt.output)
+		if pw != tt.pw || ph != tt.ph || ow != tt.ow || oh != tt.oh {
+			t.Errorf("parseWMSizes(%q) = %d %d %d %d", tt.output, pw, ph, ow, oh)
+		}
+	}
+}
+
+func TestParseWMDensities(t *testing.T) {
+	tests := []struct {
+		output             string
+		physical, override int
+	}{
+		{"Physical density: 420\n", 420, 0},
+		{"Physical density: 420\r\nOverride density: 320\r\n", 420, 320},
+		{"Physical density: abc\n", 0, 0},
+		{"", 0, 0},
+	}
+	for _, tt := range tests {
+		physical, override := parseWMDensities(tt.output)
+		if physical != tt.physical || override != tt.override {
+			t.Errorf("parseWMDensities(%q) = %d, %d", tt.output, physical, override)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package adb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lsLinePattern 匹配 toybox `ls -la` 的一行，如
+// "drwxrwx--x 4 root sdcard_rw 3488 2024-01-01 12:00 Android"
+// 字符/块设备的大小列为 "主设备号, 次设备号"
+var lsLinePattern = regexp.MustCompile(`^([-dlcbps][-rwxsStT]{9})\S*\s+\d+\s+\S+\s+\S+\s+(?:\d+,\s*)?(\d+)\s+(\d{4}-\d{2}-\d{2} \d{2}:\d{2})\s+(.+)$`)
+
+// ListDir 列出设备目录，优先使用 sync LIST，失败时回退到解析 `ls -la` 输出。
+// 返回的条目目录在前、按名称排序；能确定目录不存在时返回的错误包装 fs.ErrNotExist
+func ListDir(deviceID string, dir string) ([]FileEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var entries []FileEntry
+	s, err := OpenSync(ctx, deviceID)
+	if err == nil {
+		entries, err = s.List(dir)
+		s.Close()
+	}
+	// 只有 STAT v2 能区分不存在与无权限；v1 的 mode 为 0 时交给 ls 给出具体原因
+	if errors.Is(err, fs.ErrNotExist) && s.statV2 {
+		return nil, fmt.Errorf("目录不存在: %w", err)
+	}
+	if err != nil {
+		log.Printf("sync LIST 失败，回退到 ls -la: dir=%s, 错误: %v", dir, err)
+		entries, err = listDirWithLs(deviceID, "ls -la "+shellQuote(dir))
+		if err != nil {
+			return nil, err
+		}
+	}
+	SortFileEntries(entries)
+	return entries, nil
+}
+
+// listDirWithLs 执行 ls 命令并解析输出，command 可带 run-as/su 前缀
+func listDirWithLs(deviceID string, command string) ([]FileEntry, error) {
+	result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", command}, 30*time.Second)
+	if err != nil {
+		if result != nil && strings.TrimSpace(result.Output+result.ErrorOutput) != "" {
+			return nil, errors.New(strings.TrimSpace(result.Output + result.ErrorOutput))
+		}
+		return nil, err
+	}
+	// 不支持 shell v2 的设备上 adb 不返回退出码，出错时只能从输出判断
+	if msg := lsErrorMessage(result.Output + result.ErrorOutput); msg != "" {
+		return nil, errors.New(msg)
+	}
+	return parseLsLa(result.Output), nil
+}
+
+// lsErrorMessage 输出中没有任何条目（ls -a 至少会列出 .）时返回 ls 的错误信息
+func lsErrorMessage(output string) string {
+	var message string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if lsLinePattern.MatchString(line) {
+			return ""
+		}
+		if message == "" && strings.HasPrefix(line, "ls: ") {
+			message = strings.TrimPrefix(line, "ls: ")
+		}
+	}
+	return message
+}
+
+// parseLsLa 解析 `ls -la` 输出，跳过 total 行以及 . 和 ..
+func parseLsLa(output string) []FileEntry {
+	var entries []FileEntry
+	for _, line := range strings.Split(output, "\n") {
+		match := lsLinePattern.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if match == nil {
+			continue
+		}
+		name := match[4]
+		mode := parseModeString(match[1])
+		if mode&fs.ModeSymlink != 0 {
+			// "name -> target"
+			if idx := strings.Index(name, " -> "); idx >= 0 {
+				name = name[:idx]
+			}
+		}
+		if name == "." || name == ".." {
+			continue
+		}
+		size, _ := strconv.ParseInt(match[2], 10, 64)
+		modTime, _ := time.ParseInLocation("2006-01-02 15:04", match[3], time.Local)
+		entries = append(entries, FileEntry{Name: name, Size: size, Mode: mode, ModTime: modTime})
+	}
+	return entries
+}
+
+// parseModeString 将 "drwxr-x--x" 形式的权限字符串转换为 fs.FileMode
+func parseModeString(s string) fs.FileMode {
+	var mode fs.FileMode
+	switch s[0] {
+	case 'd':
+		mode |= fs.ModeDir
+	case 'l':
+		mode |= fs.ModeSymlink
+	case 'c':
+		mode |= fs.ModeDevice | fs.ModeCharDevice
+	case 'b':
+		mode |= fs.ModeDevice
+	case 'p':
+		mode |= fs.ModeNamedPipe
+	case 's':
+		mode |= fs.ModeSocket
+	}
+	for i, c := range s[1:10] {
+		// s/t 表示同时具有执行位，S/T 表示没有
+		if c != '-' && c != 'S' && c != 'T' {
+			mode |= 1 << uint(8-i)
+		}
+	}
+	return mode
+}
+
+// SortFileEntries 目录在前，其余按名称排序（不区分大小写）
+func SortFileEntries(entries []FileEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].IsDir() != entries[j].IsDir() {
+			return entries[i].IsDir()
+		}
+		return strings.ToLower(entries[i].Name) < strings.ToLower(entries[j].Name)
+	})
+}
+
+// MakeDir 在设备上创建目录（含父目录）
+func MakeDir(deviceID string, dir string) error {
+	return runFileCommand(deviceID, "mkdir -p "+shellQuote(dir))
+}
+
+// RenamePath 重命名或移动设备上的文件/目录
+func RenamePath(deviceID string, from string, to string) error {
+	return runFileCommand(deviceID, "mv "+shellQuote(from)+" "+shellQuote(to))
+}
+
+// RemovePath 删除设备上的文件或目录（递归）
+func RemovePath(deviceID string, target string) error {
+	if target == "" || path.Clean(target) == "/" {
+		return fmt.Errorf("拒绝删除根目录")
+	}
+	return runFileCommand(deviceID, "rm -rf "+shellQuote(target))
+}
+
+// runFileCommand 执行文件操作命令，命令成功时无输出，有输出即视为错误信息
+func runFileCommand(deviceID string, command string) error {
+	log.Printf("文件操作: deviceID=%s, command=%s", deviceID, command)
+	result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", command}, 30*time.Second)
+	output := ""
+	if result != nil {
+		output = strings.TrimSpace(result.Output + result.ErrorOutput)
+	}
+	if output != "" {
+		return errors.New(output)
+	}
+	return err
+}
+
+// errPreviewLimit 预览读取达到上限，用于提前结束 RECV
+var errPreviewLimit = errors.New("preview limit reached")
+
+// limitWriter 最多接收 limit 字节，超出后返回 errPreviewLimit
+type limitWriter struct {
+	data  []byte
+	limit int
+}
+
+func (w *limitWriter) Write(p []byte) (int, error) {
+	remaining := w.limit - len(w.data)
+	if len(p) > remaining {
+		w.data = append(w.data, p[:remaining]...)
+		return remaining, errPreviewLimit
+	}
+	w.data = append(w.data, p...)
+	return len(p), nil
+}
+
+// ReadFileHead 读取设备文件的前 limit 字节，用于预览
+func ReadFileHead(deviceID string, remotePath string, limit int) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	s, err := OpenSync(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	// 提前结束时连接处于传输中途，不能复用，直接关闭
+	defer s.Close()
+
+	w := &limitWriter{limit: limit}
+	if err := s.Receive(remotePath, w, -1, nil); err != nil && !errors.Is(err, errPreviewLimit) {
+		return nil, err
+	}
+	return w.data, nil
+}
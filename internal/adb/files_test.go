@@ -0,0 +1,57 @@
+package adb
+
+import (
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func TestParseLsLa(t *testing.T) {
+	output := "total 48\r\n" +
+		"drwxrwx--x  4 root   sdcard_rw 3488 2024-01-01 12:00 .\r\n" +
+		"drwxr-xr-x 20 root   root      4096 2024-01-01 12:00 ..\r\n" +
+		"drwxrwx--x  2 u0_a12 sdcard_rw 3488 2024-03-05 08:30 Android\r\n" +
+		"-rw-rw----  1 u0_a12 sdcard_rw 1234 2024-03-05 08:31 my file.txt\r\n" +
+		"lrwxrwxrwx  1 root   root        21 2024-03-05 08:32 sdcard -> /storage/self/primary\r\n" +
+		"crw-rw-rw-  1 root   root      1,   3 2024-03-05 08:33 null\r\n" +
+		"-rwsr-sr-T  1 root   shell        0 2024-03-05 08:34 special\r\n" +
+		"ls: ./secret: Permission denied\r\n"
+
+	want := []FileEntry{
+		{Name: "Android", Size: 3488, Mode: fs.ModeDir | 0o771},
+		{Name: "my file.txt", Size: 1234, Mode: 0o660},
+		{Name: "sdcard", Size: 21, Mode: fs.ModeSymlink | 0o777},
+		{Name: "null", Size: 3, Mode: fs.ModeDevice | fs.ModeCharDevice | 0o666},
+		{Name: "special", Size: 0, Mode: 0o754},
+	}
+	got := parseLsLa(output)
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i].Name != want[i].Name || got[i].Size != want[i].Size || got[i].Mode != want[i].Mode {
+			t.Errorf("entry %d = {%q %d %v}, want {%q %d %v}",
+				i, got[i].Name, got[i].Size, got[i].Mode, want[i].Name, want[i].Size, want[i].Mode)
+		}
+	}
+	wantTime := time.Date(2024, 3, 5, 8, 30, 0, 0, time.Local)
+	if !got[0].ModTime.Equal(wantTime) {
+		t.Errorf("ModTime = %v, want %v", got[0].ModTime, wantTime)
+	}
+}
+
+func TestLsErrorMessage(t *testing.T) {
+	tests := map[string]string{
+		"ls: /data: Permission denied\r\n":              "/data: Permission denied",
+		"ls: /sdcard/typo: No such file or directory\n": "/sdcard/typo: No such file or directory",
+		"total 8\ndrwxrwx--x 2 root sdcard_rw 3488 2024-01-01 12:00 .\ndrwxr-xr-x 20 root root 4096 2024-01-01 12:00 ..\n": "",
+		// 部分条目无法读取时仍然返回已列出的条目
+		"ls: ./secret: Permission denied\ndrwxrwx--x 2 root sdcard_rw 3488 2024-01-01 12:00 .\n": "",
+		"": "",
+	}
+	for output, want := range tests {
+		if got := lsErrorMessage(output); got != want {
+			t.Errorf("lsErrorMessage(%q) = %q, want %q", output, got, want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package adb
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InputEvent getevent 输出的一条内核输入事件。
+// 使用 -l 时 Type/Code 为名称（EV_ABS、ABS_MT_POSITION_X），否则为四位十六进制数
+type InputEvent struct {
+	Time   time.Duration // 内核时间戳
+	Device string        // /dev/input/eventN
+	Type   string
+	Code   string
+	Value  int32
+}
+
+// AbsInfo 绝对坐标轴的取值范围
+type AbsInfo struct {
+	Min int
+	Max int
+}
+
+// InputDevice getevent -lp 列出的输入设备
+type InputDevice struct {
+	Path string
+	Name string
+	Axes map[string]AbsInfo // 坐标轴名称 → 范围
+}
+
+// IsTouchscreen 是否为触摸屏（有多点或单点的 X/Y 坐标轴）
+func (d InputDevice) IsTouchscreen() bool {
+	_, mtX := d.Axes["ABS_MT_POSITION_X"]
+	_, mtY := d.Axes["ABS_MT_POSITION_Y"]
+	_, x := d.Axes["ABS_X"]
+	_, y := d.Axes["ABS_Y"]
+	return (mtX && mtY) || (x && y)
+}
+
+var (
+	geteventLinePattern = regexp.MustCompile(`^\[\s*(\d+)\.(\d+)\]\s+(\S+):\s+(\S+)\s+(\S+)\s+(\S+)`)
+	addDevicePattern    = regexp.MustCompile(`^add device \d+:\s*(\S+)`)
+	deviceNamePattern   = regexp.MustCompile(`^\s*name:\s*"(.*)"`)
+	absAxisPattern      = regexp.MustCompile(`(ABS_\w+|[0-9a-f]{4})\s*: value -?\d+, min (-?\d+), max (-?\d+)`)
+	geteventValueLabels = map[string]int32{"UP": 0, "DOWN": 1, "REPEAT": 2}
+)
+
+// ParseGeteventLine 解析 getevent -t 或 -lt 的一行输出
+func ParseGeteventLine(line string) (InputEvent, bool) {
+	match := geteventLinePattern.FindStringSubmatch(strings.TrimSpace(line))
+	if match == nil {
+		return InputEvent{}, false
+	}
+	seconds, _ := strconv.ParseInt(match[1], 10, 64)
+	micros, _ := strconv.ParseInt((match[2] + "000000")[:6], 10, 64)
+
+	event := InputEvent{
+		Time:   time.Duration(seconds)*time.Second + time.Duration(micros)*time.Microsecond,
+		Device: match[3],
+		Type:   match[4],
+		Code:   match[5],
+	}
+	if value, ok := geteventValueLabels[match[6]]; ok {
+		event.Value = value
+	} else {
+		value, err := strconv.ParseUint(match[6], 16, 32)
+		if err != nil {
+			return InputEvent{}, false
+		}
+		// 按 32 位有符号数解释，ffffffff 即 -1（抬起手指时的 tracking id）
+		event.Value = int32(uint32(value))
+	}
+	return event, true
+}
+
+// GetInputDevices 通过 getevent -lp 列出输入设备及其坐标轴范围
+func GetInputDevices(deviceID string) ([]InputDevice, error) {
+	result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "getevent", "-lp"}, 15*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	return parseInputDevices(result.Output), nil
+}
+
+func parseInputDevices(output string) []InputDevice {
+	var devices []InputDevice
+	var current *InputDevice
+	for _, line := range strings.Split(output, "\n") {
+		if match := addDevicePattern.FindStringSubmatch(line); match != nil {
+			devices = append(devices, InputDevice{Path: match[1], Axes: make(map[string]AbsInfo)})
+			current = &devices[len(devices)-1]
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if match := deviceNamePattern.FindStringSubmatch(line); match != nil {
+			current.Name = match[1]
+			continue
+		}
+		if match := absAxisPattern.FindStringSubmatch(line); match != nil {
+			minValue, _ := strconv.Atoi(match[2])
+			maxValue, _ := strconv.Atoi(match[3])
+			current.Axes[match[1]] = AbsInfo{Min: minValue, Max: maxValue}
+		}
+	}
+	return devices
+}
+
+// FindTouchscreen 返回第一个触摸屏设备
+func FindTouchscreen(devices []InputDevice) (InputDevice, error) {
+	for _, d := range devices {
+		if d.IsTouchscreen() {
+			return d, nil
+		}
+	}
+	return InputDevice{}, fmt.Errorf("未找到触摸屏输入设备")
+}
+
+// StreamGetevent 运行 getevent 并逐条回调解析后的事件，直到 ctx 取消。
+// labels 为 true 时使用 -lt（名称），否则使用 -t（数值，可直接用于 sendevent）
+func StreamGetevent(ctx context.Context, deviceID string, labels bool, onEvent func(InputEvent)) error {
+	flag := "-t"
+	if labels {
+		flag = "-lt"
+	}
+	log.Printf("开始捕获输入事件: deviceID=%s, getevent %s", deviceID, flag)
+	cmd := exec.CommandContext(ctx, "adb", "-s", deviceID, "shell", "getevent", flag)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		if event, ok := ParseGeteventLine(scanner.Text()); ok {
+			onEvent(event)
+		}
+	}
+	err = cmd.Wait()
+	if ctx.Err() != nil {
+		// 主动停止时 adb 进程被结束，不视为错误
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,83 @@
+package adb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseGeteventLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want InputEvent
+		ok   bool
+	}{
+		{
+			line: "[   12345.678901] /dev/input/event2: EV_ABS       ABS_MT_POSITION_X    0000021c",
+			want: InputEvent{Time: 12345*time.Second + 678901*time.Microsecond, Device: "/dev/input/event2", Type: "EV_ABS", Code: "ABS_MT_POSITION_X", Value: 0x21c},
+			ok:   true,
+		},
+		{
+			line: "[   12345.678901] /dev/input/event2: EV_ABS       ABS_MT_TRACKING_ID   ffffffff",
+			want: InputEvent{Time: 12345*time.Second + 678901*time.Microsecond, Device: "/dev/input/event2", Type: "EV_ABS", Code: "ABS_MT_TRACKING_ID", Value: -1},
+			ok:   true,
+		},
+		{
+			line: "[     100.5] /dev/input/event0: EV_KEY       KEY_POWER            DOWN\r",
+			want: InputEvent{Time: 100*time.Second + 500*time.Millisecond, Device: "/dev/input/event0", Type: "EV_KEY", Code: "KEY_POWER", Value: 1},
+			ok:   true,
+		},
+		{
+			line: "[       1.000000] /dev/input/event1: 0003 0035 000001f4",
+			want: InputEvent{Time: time.Second, Device: "/dev/input/event1", Type: "0003", Code: "0035", Value: 500},
+			ok:   true,
+		},
+		{line: "add device 1: /dev/input/event2"},
+		{line: "[   1.0] /dev/input/event1: EV_KEY KEY_POWER NOTHEX"},
+		{line: ""},
+	}
+	for _, tt := range tests {
+		got, ok := ParseGeteventLine(tt.line)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseGeteventLine(%q) = %+v, %v; want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseInputDevices(t *testing.T) {
+	output := `add device 1: /dev/input/event3
+  name:     "gpio-keys"
+  events:
+    KEY (0001): KEY_VOLUMEDOWN        KEY_VOLUMEUP          KEY_POWER
+add device 2: /dev/input/event2
+  name:     "fts_ts"
+  events:
+    KEY (0001): BTN_TOUCH
+    ABS (0003): ABS_MT_SLOT           : value 0, min 0, max 9, fuzz 0, flat 0, resolution 0
+                ABS_MT_POSITION_X     : value 0, min 0, max 1079, fuzz 0, flat 0, resolution 0
+                ABS_MT_POSITION_Y     : value 0, min 0, max 2399, fuzz 0, flat 0, resolution 0
+                ABS_MT_TRACKING_ID    : value 0, min -1, max 65535, fuzz 0, flat 0, resolution 0
+  input props:
+    INPUT_PROP_DIRECT
+`
+	want := []InputDevice{
+		{Path: "/dev/input/event3", Name: "gpio-keys", Axes: map[string]AbsInfo{}},
+		{Path: "/dev/input/event2", Name: "fts_ts", Axes: map[string]AbsInfo{
+			"ABS_MT_SLOT":        {Min: 0, Max: 9},
+			"ABS_MT_POSITION_X":  {Min: 0, Max: 1079},
+			"ABS_MT_POSITION_Y":  {Min: 0, Max: 2399},
+			"ABS_MT_TRACKING_ID": {Min: -1, Max: 65535},
+		}},
+	}
+	got := parseInputDevices(output)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got  %+v\nwant %+v", got, want)
+	}
+	touch, err := FindTouchscreen(got)
+	if err != nil || touch.Path != "/dev/input/event2" {
+		t.Errorf("FindTouchscreen = %+v, %v", touch, err)
+	}
+	if _, err := FindTouchscreen(got[:1]); err == nil {
+		t.Error("没有触摸屏时应返回错误")
+	}
+}
@@ -0,0 +1,225 @@
+package adb
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"yikong/internal/constants"
+)
+
+// DisplayInfo 屏幕的逻辑尺寸（自然方向，已应用 wm size 覆盖值）与当前旋转方向
+type DisplayInfo struct {
+	Width    int
+	Height   int
+	Rotation int // 0-3，对应 0°/90°/180°/270°
+}
+
+var (
+	wmSizePattern         = regexp.MustCompile(`(Physical|Override) size:\s*(\d+)x(\d+)`)
+	surfaceOrientPattern  = regexp.MustCompile(`SurfaceOrientation:\s*(\d)`)
+	windowRotationPattern = regexp.MustCompile(`mCurrentRotation=(?:ROTATION_)?(\d+)`)
+)
+
+// GetDisplayInfo 通过 wm size 与 dumpsys 获取屏幕尺寸和旋转方向
+func GetDisplayInfo(deviceID string) (*DisplayInfo, error) {
+	result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "wm", "size"}, 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	// 有 Override size 时以覆盖值为准，input 坐标使用的是覆盖后的逻辑尺寸
+	width, height, overrideWidth, overrideHeight := parseWMSizes(result.Output)
+	if overrideWidth > 0 {
+		width, height = overrideWidth, overrideHeight
+	}
+	info := &DisplayInfo{Width: width, Height: height}
+	if info.Width == 0 || info.Height == 0 {
+		return nil, fmt.Errorf("无法解析屏幕尺寸: %s", strings.TrimSpace(result.Output))
+	}
+	info.Rotation = getDisplayRotation(deviceID)
+	log.Printf("屏幕信息: deviceID=%s, %dx%d, rotation=%d", deviceID, info.Width, info.Height, info.Rotation)
+	return info, nil
+}
+
+// getDisplayRotation 读取当前旋转方向，获取失败时视为未旋转
+func getDisplayRotation(deviceID string) int {
+	result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "dumpsys input | grep -m 1 SurfaceOrientation"}, 10*time.Second)
+	if err == nil {
+		if match := surfaceOrientPattern.FindStringSubmatch(result.Output); match != nil {
+			rotation, _ := strconv.Atoi(match[1])
+			return rotation % 4
+		}
+	}
+	// 新版本的 dumpsys input 不再输出 SurfaceOrientation
+	result, err = ExecuteADBCommandWithDevice(deviceID, []string{"shell", "dumpsys window displays | grep -m 1 mCurrentRotation"}, 10*time.Second)
+	if err == nil {
+		if match := windowRotationPattern.FindStringSubmatch(result.Output); match != nil {
+			value, _ := strconv.Atoi(match[1])
+			// 部分版本输出角度（ROTATION_90），部分输出序号
+			if value >= 90 {
+				value /= 90
+			}
+			return value % 4
+		}
+	}
+	return 0
+}
+
+// CurrentSize 当前方向下的逻辑尺寸
+func (d DisplayInfo) CurrentSize() (int, int) {
+	if d.Rotation%2 == 1 {
+		return d.Height, d.Width
+	}
+	return d.Width, d.Height
+}
+
+// MapFramePoint 将截屏画面（当前方向，物理分辨率）上的坐标换算为 input 使用的逻辑坐标
+func (d DisplayInfo) MapFramePoint(x, y float32, frameWidth, frameHeight int) (int, int) {
+	width, height := d.CurrentSize()
+	px := int(x * float32(width) / float32(frameWidth))
+	py := int(y * float32(height) / float32(frameHeight))
+	return min(max(px, 0), width-1), min(max(py, 0), height-1)
+}
+
+// MapNaturalPoint 将自然方向上的坐标（触摸屏上报的坐标系）换算为当前方向下的 input 坐标
+func (d DisplayInfo) MapNaturalPoint(x, y int) (int, int) {
+	switch d.Rotation {
+	case 1:
+		return y, d.Width - 1 - x
+	case 2:
+		return d.Width - 1 - x, d.Height - 1 - y
+	case 3:
+		return d.Height - 1 - y, x
+	}
+	return x, y
+}
+
+// runInput 执行 input 命令
+func runInput(deviceID string, args ...string) error {
+	return runShellCommand(deviceID, append([]string{"input"}, args...)...)
+}
+
+// Tap 点击屏幕坐标
+func Tap(deviceID string, x, y int) error {
+	return runInput(deviceID, "tap", strconv.Itoa(x), strconv.Itoa(y))
+}
+
+// Swipe 从 (x1,y1) 滑动到 (x2,y2)，duration 为滑动时长
+func Swipe(deviceID string, x1, y1, x2, y2 int, duration time.Duration) error {
+	return runInput(deviceID, "swipe",
+		strconv.Itoa(x1), strconv.Itoa(y1), strconv.Itoa(x2), strconv.Itoa(y2),
+		strconv.Itoa(int(duration.Milliseconds())))
+}
+
+// KeyEvent 发送按键事件
+func KeyEvent(deviceID string, keyCode int) error {
+	return runInput(deviceID, "keyevent", strconv.Itoa(keyCode))
+}
+
+// LongPressKey 长按按键（如长按电源键打开电源菜单）
+func LongPressKey(deviceID string, keyCode int) error {
+	return runInput(deviceID, "keyevent", "--longpress", strconv.Itoa(keyCode))
+}
+
+// InputText 输入文本。input text 中空格需写作 %s，且只支持 ASCII 字符
+func InputText(deviceID string, text string) error {
+	for _, r := range text {
+		if r < 0x20 || r > 0x7e {
+			return fmt.Errorf("input text 只支持可打印的 ASCII 字符: %q", r)
+		}
+	}
+	return runInput(deviceID, "text", shellQuote(strings.ReplaceAll(text, " ", "%s")))
+}
+
+// KeyPress 按键序列中的一次按键
+type KeyPress struct {
+	KeyCode   int
+	LongPress bool
+}
+
+// ParseKeyCode 解析按键：支持数值（4）、完整名称（KEYCODE_BACK）或省略前缀的名称（back），不区分大小写
+func ParseKeyCode(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if code, err := strconv.Atoi(s); err == nil {
+		if code < 0 {
+			return 0, fmt.Errorf("无效的按键码: %s", s)
+		}
+		return code, nil
+	}
+	name := strings.ToUpper(s)
+	if !strings.HasPrefix(name, "KEYCODE_") {
+		name = "KEYCODE_" + name
+	}
+	for code, n := range constants.KeyCodeMap {
+		if n == name {
+			return code, nil
+		}
+	}
+	return 0, fmt.Errorf("未知的按键: %s", s)
+}
+
+// ParseKeySequence 解析以逗号或空白分隔的按键序列，按键前加 long: 表示长按，
+// 例如 "HOME, DPAD_DOWN, long:POWER, 66"
+func ParseKeySequence(text string) ([]KeyPress, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == '，' || r == ' ' || r == '\t' || r == '\n'
+	})
+	var keys []KeyPress
+	for _, field := range fields {
+		press := KeyPress{}
+		if rest, ok := strings.CutPrefix(strings.ToLower(field), "long:"); ok {
+			press.LongPress = true
+			field = rest
+		}
+		code, err := ParseKeyCode(field)
+		if err != nil {
+			return nil, err
+		}
+		press.KeyCode = code
+		keys = append(keys, press)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("按键序列为空")
+	}
+	return keys, nil
+}
+
+// SendKeySequence 依次发送按键，相邻按键之间等待 interval；onKey 在发送每个按键前回调
+func SendKeySequence(ctx context.Context, deviceID string, keys []KeyPress, interval time.Duration, onKey func(index int)) error {
+	for i, key := range keys {
+		if i > 0 && interval > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(interval):
+			}
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if onKey != nil {
+			onKey(i)
+		}
+		var err error
+		if key.LongPress {
+			err = LongPressKey(deviceID, key.KeyCode)
+		} else {
+			err = KeyEvent(deviceID, key.KeyCode)
+		}
+		if err != nil {
+			return fmt.Errorf("发送 %s 失败: %w", KeyCodeName(key.KeyCode), err)
+		}
+	}
+	return nil
+}
+
+// KeyCodeName 按键码对应的名称，未知时返回数值
+func KeyCodeName(code int) string {
+	if name, ok := constants.KeyCodeMap[code]; ok {
+		return name
+	}
+	return strconv.Itoa(code)
+}
@@ -0,0 +1,449 @@
+package adb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"yikong/internal/apk"
+)
+
+// 安装相关命令的超时时间，大型应用安装可能耗时较长
+const installTimeout = 10 * time.Minute
+
+// InstallOptions 安装参数
+type InstallOptions struct {
+	Replace          bool // -r 覆盖安装，保留数据
+	AllowDowngrade   bool // -d 允许降级
+	GrantPermissions bool // -g 授予清单中的全部运行时权限
+	AllowTest        bool // -t 允许安装 testOnly 应用
+}
+
+// Args 转换为 adb install / pm install-create 的参数
+func (o InstallOptions) Args() []string {
+	var args []string
+	if o.Replace {
+		args = append(args, "-r")
+	}
+	if o.AllowDowngrade {
+		args = append(args, "-d")
+	}
+	if o.GrantPermissions {
+		args = append(args, "-g")
+	}
+	if o.AllowTest {
+		args = append(args, "-t")
+	}
+	return args
+}
+
+// GetDeviceSpec 读取设备的ABI、屏幕密度、语言和SDK版本，用于挑选拆分包
+func GetDeviceSpec(deviceID string) (apk.DeviceSpec, error) {
+	props, err := GetProperties(deviceID)
+	if err != nil {
+		return apk.DeviceSpec{}, err
+	}
+
+	spec := apk.DeviceSpec{}
+	if abilist := props["ro.product.cpu.abilist"]; abilist != "" {
+		spec.ABIs = strings.Split(abilist, ",")
+	} else {
+		for _, key := range []string{"ro.product.cpu.abi", "ro.product.cpu.abi2"} {
+			if abi := props[key]; abi != "" {
+				spec.ABIs = append(spec.ABIs, abi)
+			}
+		}
+	}
+
+	spec.SDK, _ = strconv.Atoi(props["ro.build.version.sdk"])
+	spec.Density, _ = strconv.Atoi(props["ro.sf.lcd_density"])
+	if result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "wm", "density"}, 10*time.Second); err == nil {
+		if density := parseWMDensity(result.Output); density > 0 {
+			spec.Density = density
+		}
+	}
+
+	for _, key := range []string{"persist.sys.locale", "ro.product.locale"} {
+		if locale := props[key]; locale != "" {
+			spec.Locales = append(spec.Locales, locale)
+			break
+		}
+	}
+	if len(spec.Locales) == 0 {
+		if lang := props["persist.sys.language"]; lang != "" {
+			spec.Locales = append(spec.Locales, lang+"-"+props["persist.sys.country"])
+		}
+	}
+
+	log.Printf("设备配置: deviceID=%s, ABI=%v, 密度=%d, 语言=%v, SDK=%d", deviceID, spec.ABIs, spec.Density, spec.Locales, spec.SDK)
+	return spec, nil
+}
+
+// parseWMDensity 解析 wm density 输出，优先使用覆盖值
+func parseWMDensity(output string) int {
+	physical, override := parseWMDensities(output)
+	if override > 0 {
+		return override
+	}
+	return physical
+}
+
+// InstallAPK 使用 adb install 安装单个APK
+func InstallAPK(deviceID string, apkPath string, opts InstallOptions, outputCallback func(line string)) (*CommandResult, error) {
+	args := append([]string{"install"}, opts.Args()...)
+	args = append(args, apkPath)
+	log.Printf("安装APK: deviceID=%s, args=%v", deviceID, args)
+	return ExecuteADBCommandWithDeviceStream(deviceID, args, installTimeout, outputCallback)
+}
+
+// InstallMultiple 使用 adb install-multiple 一次安装多个拆分APK
+func InstallMultiple(deviceID string, apkPaths []string, opts InstallOptions, outputCallback func(line string)) (*CommandResult, error) {
+	args := append([]string{"install-multiple"}, opts.Args()...)
+	args = append(args, apkPaths...)
+	log.Printf("安装拆分APK: deviceID=%s, args=%v", deviceID, args)
+	return ExecuteADBCommandWithDeviceStream(deviceID, args, installTimeout, outputCallback)
+}
+
+var sessionIDPattern = regexp.MustCompile(`\[(\d+)\]`)
+
+// InstallSession 通过 pm install-create / install-write / install-commit 会话安装多个APK
+// 适用于 adb 版本较旧、不支持 install-multiple 的环境
+func InstallSession(deviceID string, apkPaths []string, opts InstallOptions, outputCallback func(line string)) (*CommandResult, error) {
+	emit := func(format string, a ...any) {
+		if outputCallback != nil {
+			outputCallback(fmt.Sprintf(format, a...))
+		}
+	}
+
+	var totalSize int64
+	for _, p := range apkPaths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		totalSize += info.Size()
+	}
+
+	createArgs := append([]string{"shell", "pm", "install-create"}, opts.Args()...)
+	createArgs = append(createArgs, "-S", strconv.FormatInt(totalSize, 10))
+	result, err := ExecuteADBCommandWithDevice(deviceID, createArgs, 30*time.Second)
+	if err != nil {
+		return result, err
+	}
+	match := sessionIDPattern.FindStringSubmatch(result.Output)
+	if match == nil {
+		return result, fmt.Errorf("创建安装会话失败: %s", strings.TrimSpace(result.Output))
+	}
+	session := match[1]
+	emit("已创建安装会话: %s", session)
+
+	abandon := func() {
+		ExecuteADBCommandWithDevice(deviceID, []string{"shell", "pm", "install-abandon", session}, 30*time.Second)
+		emit("已放弃安装会话: %s", session)
+	}
+
+	var remoteFiles []string
+	defer func() {
+		if len(remoteFiles) > 0 {
+			rmArgs := []string{"shell", "rm", "-f"}
+			for _, f := range remoteFiles {
+				rmArgs = append(rmArgs, shellQuote(f))
+			}
+			ExecuteADBCommandWithDevice(deviceID, rmArgs, 30*time.Second)
+		}
+	}()
+
+	for i, p := range apkPaths {
+		info, _ := os.Stat(p)
+		name := sessionSplitName(i, p)
+		remote := "/data/local/tmp/yikong_" + session + "_" + name
+		emit("正在推送 %s (%d 字节)", name, info.Size())
+
+		if result, err := ExecuteADBCommandWithDevice(deviceID, []string{"push", p, remote}, installTimeout); err != nil {
+			abandon()
+			return result, err
+		}
+		remoteFiles = append(remoteFiles, remote)
+
+		writeArgs := []string{"shell", "pm", "install-write", "-S", strconv.FormatInt(info.Size(), 10), session, name, remote}
+		result, err := ExecuteADBCommandWithDevice(deviceID, writeArgs, installTimeout)
+		if err != nil || !strings.Contains(result.Output, "Success") {
+			abandon()
+			if err == nil {
+				err = fmt.Errorf("写入安装会话失败: %s", strings.TrimSpace(result.Output))
+			}
+			return result, err
+		}
+		emit("已写入: %s", name)
+	}
+
+	result, err = ExecuteADBCommandWithDeviceStream(deviceID, []string{"shell", "pm", "install-commit", session}, installTimeout, outputCallback)
+	if err == nil && !strings.Contains(result.Output, "Success") {
+		result.Success = false
+		err = fmt.Errorf("提交安装会话失败: %s", strings.TrimSpace(result.Output))
+	}
+	return result, err
+}
+
+// sessionSplitName 安装会话中拆分APK的名称，同时用作 /data/local/tmp 下的临时文件名。
+// 名称会拼接进设备端 shell 命令，只保留 [A-Za-z0-9._-]，其余字符替换为 _
+func sessionSplitName(index int, localPath string) string {
+	base := path.Base(strings.ReplaceAll(localPath, "\\", "/"))
+	safe := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-' {
+			return r
+		}
+		return '_'
+	}, base)
+	return fmt.Sprintf("%d_%s", index, safe)
+}
+
+// streamInstallWriteArgs 流式写入安装会话的 adb 参数。
+// adbd 通过 sh -c 执行 exec-in 命令，name 必须来自 sessionSplitName
+func streamInstallWriteArgs(deviceID string, size int64, session string, name string) []string {
+	return []string{"-s", deviceID, "exec-in", "cmd", "package", "install-write",
+		"-S", strconv.FormatInt(size, 10), session, name, "-"}
+}
+
+// PushExpansions 将XAPK中的OBB扩展文件推送到外部存储
+func PushExpansions(deviceID string, expansions []apk.Expansion, outputCallback func(line string)) error {
+	for _, e := range expansions {
+		remote := "/sdcard/" + e.DevicePath
+		if outputCallback != nil {
+			outputCallback("正在推送OBB: " + remote)
+		}
+		if _, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "mkdir", "-p", shellQuote(path.Dir(remote))}, 30*time.Second); err != nil {
+			return err
+		}
+		if _, err := ExecuteADBCommandWithDevice(deviceID, []string{"push", e.File, remote}, installTimeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InstallProgress 安装进度回调，written 为已传输的字节数，total 为总字节数
+type InstallProgress func(written, total int64)
+
+// progressReader 在读取时统计字节数并回调进度
+type progressReader struct {
+	reader     io.Reader
+	offset     int64 // 之前文件已传输的字节数
+	read       int64
+	total      int64
+	onProgress InstallProgress
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.read += int64(n)
+		if r.onProgress != nil {
+			r.onProgress(r.offset+r.read, r.total)
+		}
+	}
+	return n, err
+}
+
+// errStreamUnsupported 设备不支持 cmd package 安装会话（Android 7.0 以下）
+var errStreamUnsupported = errors.New("设备不支持流式安装")
+
+// StreamInstall 通过 exec-in 将APK直接流式写入安装会话（cmd package install-write -），
+// 不在设备上落盘临时文件，并能按字节回调传输进度。需要 Android 7.0 及以上
+func StreamInstall(deviceID string, apkPaths []string, opts InstallOptions, progress InstallProgress, outputCallback func(line string)) (*CommandResult, error) {
+	emit := func(format string, a ...any) {
+		if outputCallback != nil {
+			outputCallback(fmt.Sprintf(format, a...))
+		}
+	}
+	startTime := time.Now()
+
+	sizes := make([]int64, len(apkPaths))
+	var totalSize int64
+	for i, p := range apkPaths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		sizes[i] = info.Size()
+		totalSize += info.Size()
+	}
+
+	createArgs := append([]string{"shell", "cmd", "package", "install-create"}, opts.Args()...)
+	createArgs = append(createArgs, "-S", strconv.FormatInt(totalSize, 10))
+	result, err := ExecuteADBCommandWithDevice(deviceID, createArgs, 30*time.Second)
+	if err != nil {
+		return result, fmt.Errorf("%w: %v", errStreamUnsupported, err)
+	}
+	match := sessionIDPattern.FindStringSubmatch(result.Output)
+	if match == nil {
+		return result, fmt.Errorf("%w: %s", errStreamUnsupported, strings.TrimSpace(result.Output+result.ErrorOutput))
+	}
+	session := match[1]
+	emit("已创建安装会话: %s", session)
+
+	abandon := func() {
+		ExecuteADBCommandWithDevice(deviceID, []string{"shell", "cmd", "package", "install-abandon", session}, 30*time.Second)
+		emit("已放弃安装会话: %s", session)
+	}
+
+	var written int64
+	for i, p := range apkPaths {
+		name := sessionSplitName(i, p)
+		emit("正在传输 %s (%d 字节)", name, sizes[i])
+
+		file, err := os.Open(p)
+		if err != nil {
+			abandon()
+			return nil, err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), installTimeout)
+		cmd := exec.CommandContext(ctx, "adb", streamInstallWriteArgs(deviceID, sizes[i], session, name)...)
+		cmd.Stdin = &progressReader{reader: file, offset: written, total: totalSize, onProgress: progress}
+		output, err := cmd.CombinedOutput()
+		cancel()
+		file.Close()
+
+		if err != nil || !strings.Contains(string(output), "Success") {
+			abandon()
+			if err == nil {
+				err = fmt.Errorf("写入安装会话失败: %s", strings.TrimSpace(string(output)))
+			}
+			return &CommandResult{Output: string(output), ExitCode: -1, Duration: time.Since(startTime)}, err
+		}
+		written += sizes[i]
+	}
+
+	emit("正在提交安装会话...")
+	result, err = ExecuteADBCommandWithDeviceStream(deviceID, []string{"shell", "cmd", "package", "install-commit", session}, installTimeout, outputCallback)
+	if result != nil {
+		result.Duration = time.Since(startTime)
+	}
+	if err == nil && !strings.Contains(result.Output, "Success") {
+		result.Success = false
+		err = fmt.Errorf("提交安装会话失败: %s", strings.TrimSpace(result.Output))
+	}
+	return result, err
+}
+
+// installPaths 按参数选择安装方式：
+// 提供进度回调时使用流式安装；否则单个APK用 adb install，多个用 install-multiple 或 pm 会话
+func installPaths(deviceID string, paths []string, opts InstallOptions, useSession bool, progress InstallProgress, outputCallback func(line string)) (*CommandResult, error) {
+	if progress != nil && !useSession {
+		result, err := StreamInstall(deviceID, paths, opts, progress, outputCallback)
+		if !errors.Is(err, errStreamUnsupported) {
+			return result, err
+		}
+		log.Printf("流式安装不可用，回退到普通安装: deviceID=%s, 错误: %v", deviceID, err)
+		if outputCallback != nil {
+			outputCallback("设备不支持流式安装，改用普通安装（无进度）")
+		}
+	}
+
+	switch {
+	case len(paths) == 1:
+		return InstallAPK(deviceID, paths[0], opts, outputCallback)
+	case useSession:
+		return InstallSession(deviceID, paths, opts, outputCallback)
+	default:
+		return InstallMultiple(deviceID, paths, opts, outputCallback)
+	}
+}
+
+// InstallBundle 按设备配置挑选拆分包并安装，XAPK的OBB文件会在安装成功后推送
+// useSession 为 true 时使用 pm 安装会话，否则使用 adb install-multiple 或流式安装
+func InstallBundle(deviceID string, bundle *apk.Bundle, opts InstallOptions, useSession bool, progress InstallProgress, outputCallback func(line string)) (*CommandResult, error) {
+	spec, err := GetDeviceSpec(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	splits, err := bundle.SelectSplits(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(splits))
+	for _, split := range splits {
+		if outputCallback != nil {
+			name := split.Name
+			if name == "" {
+				name = "base"
+			}
+			outputCallback("选择拆分包: " + name)
+		}
+		paths = append(paths, split.File)
+	}
+
+	result, err := installPaths(deviceID, paths, opts, useSession, progress, outputCallback)
+	if err != nil {
+		return result, err
+	}
+
+	if len(bundle.Expansions) > 0 {
+		if err := PushExpansions(deviceID, bundle.Expansions, outputCallback); err != nil {
+			return result, fmt.Errorf("应用已安装，但推送OBB失败: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// InstallFiles 根据文件类型选择安装方式：
+// 单个 .apk 直接安装；多个 .apk 视为同一应用的拆分包；.apks/.xapk 自动挑选拆分包
+// progress 不为空时使用流式安装并回调传输进度
+func InstallFiles(deviceID string, files []string, opts InstallOptions, useSession bool, progress InstallProgress, outputCallback func(line string)) (*CommandResult, error) {
+	if len(files) == 0 {
+		return nil, fmt.Errorf("没有要安装的文件")
+	}
+
+	if len(files) == 1 {
+		bundle, err := apk.OpenBundle(files[0])
+		if err != nil {
+			return nil, err
+		}
+		defer bundle.Close()
+
+		if bundle.Format == apk.FormatAPK {
+			return installPaths(deviceID, files, opts, useSession, progress, outputCallback)
+		}
+		return InstallBundle(deviceID, bundle, opts, useSession, progress, outputCallback)
+	}
+
+	for _, f := range files {
+		if !strings.EqualFold(path.Ext(f), ".apk") {
+			return nil, fmt.Errorf(".apks/.xapk 文件需要单独安装: %s", f)
+		}
+	}
+	return installPaths(deviceID, files, opts, useSession, progress, outputCallback)
+}
+
+// Uninstall 卸载应用，keepData 为 true 时保留数据和缓存目录
+func Uninstall(deviceID string, packageName string, keepData bool) (*CommandResult, error) {
+	args := []string{"uninstall"}
+	if keepData {
+		args = append(args, "-k")
+	}
+	args = append(args, packageName)
+	log.Printf("卸载应用: deviceID=%s, args=%v", deviceID, args)
+	result, err := ExecuteADBCommandWithDevice(deviceID, args, 60*time.Second)
+	if err == nil && !strings.Contains(result.Output, "Success") {
+		result.Success = false
+		err = fmt.Errorf("卸载失败: %s", strings.TrimSpace(result.Output+result.ErrorOutput))
+	}
+	return result, err
+}
+
+// TrimCaches 请求系统清理应用缓存以释放存储空间
+func TrimCaches(deviceID string) error {
+	_, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "pm", "trim-caches", "999G"}, 2*time.Minute)
+	return err
+}
@@ -0,0 +1,113 @@
+package adb
+
+import (
+	"regexp"
+	"strings"
+)
+
+// 安装失败后的一键补救方式
+const (
+	RemedyNone               = ""
+	RemedyReplace            = "replace"             // 加 -r 覆盖安装
+	RemedyDowngrade          = "downgrade"           // 加 -d 允许降级
+	RemedyAllowTest          = "allow_test"          // 加 -t 允许测试包
+	RemedyUninstallReinstall = "uninstall_reinstall" // 先卸载再安装（会丢失数据）
+	RemedyTrimCaches         = "trim_caches"         // 清理应用缓存后重试
+)
+
+// InstallFailure 安装失败的错误码及说明
+type InstallFailure struct {
+	Code        string // 如 INSTALL_FAILED_VERSION_DOWNGRADE
+	Message     string // 系统返回的原始信息
+	Explanation string // 面向用户的说明
+	Remedy      string // 建议的补救方式
+}
+
+// RemedyLabel 补救方式对应的按钮文字
+func RemedyLabel(remedy string) string {
+	switch remedy {
+	case RemedyReplace:
+		return "覆盖安装 (-r)"
+	case RemedyDowngrade:
+		return "降级安装 (-d)"
+	case RemedyAllowTest:
+		return "允许测试包 (-t)"
+	case RemedyUninstallReinstall:
+		return "卸载后重新安装"
+	case RemedyTrimCaches:
+		return "清理缓存后重试"
+	default:
+		return ""
+	}
+}
+
+// ApplyRemedy 返回应用补救方式后的安装参数
+func (o InstallOptions) ApplyRemedy(remedy string) InstallOptions {
+	switch remedy {
+	case RemedyReplace:
+		o.Replace = true
+	case RemedyDowngrade:
+		o.Replace = true
+		o.AllowDowngrade = true
+	case RemedyAllowTest:
+		o.AllowTest = true
+	}
+	return o
+}
+
+type failureInfo struct {
+	explanation string
+	remedy      string
+}
+
+// installFailureTable 常见 INSTALL_FAILED_* 错误码的说明与补救方式
+var installFailureTable = map[string]failureInfo{
+	"INSTALL_FAILED_ALREADY_EXISTS":                  {"设备上已安装该应用，需要覆盖安装", RemedyReplace},
+	"INSTALL_FAILED_VERSION_DOWNGRADE":               {"APK的versionCode低于已安装版本，系统默认禁止降级", RemedyDowngrade},
+	"INSTALL_FAILED_UPDATE_INCOMPATIBLE":             {"APK签名与已安装版本不一致（例如debug包覆盖release包），只能卸载后重新安装", RemedyUninstallReinstall},
+	"INSTALL_PARSE_FAILED_INCONSISTENT_CERTIFICATES": {"APK签名与已安装版本不一致，只能卸载后重新安装", RemedyUninstallReinstall},
+	"INSTALL_FAILED_SHARED_USER_INCOMPATIBLE":        {"sharedUserId 相同的应用签名不一致，只能卸载后重新安装", RemedyUninstallReinstall},
+	"INSTALL_FAILED_INSUFFICIENT_STORAGE":            {"设备存储空间不足", RemedyTrimCaches},
+	"INSTALL_FAILED_OLDER_SDK":                       {"设备系统版本低于APK要求的 minSdkVersion", RemedyNone},
+	"INSTALL_FAILED_NEWER_SDK":                       {"设备系统版本高于APK允许的 maxSdkVersion", RemedyNone},
+	"INSTALL_FAILED_DEPRECATED_SDK_VERSION":          {"APK的 targetSdkVersion 过低，新版Android拒绝安装（可用 --bypass-low-target-sdk-block 绕过）", RemedyNone},
+	"INSTALL_FAILED_NO_MATCHING_ABIS":                {"APK中的原生库不支持设备的CPU架构", RemedyNone},
+	"INSTALL_FAILED_TEST_ONLY":                       {"APK声明了 testOnly，需要允许测试包安装", RemedyAllowTest},
+	"INSTALL_FAILED_INVALID_APK":                     {"APK文件无效或已损坏", RemedyNone},
+	"INSTALL_PARSE_FAILED_NO_CERTIFICATES":           {"APK未签名或签名不完整", RemedyNone},
+	"INSTALL_PARSE_FAILED_NOT_APK":                   {"文件不是有效的APK", RemedyNone},
+	"INSTALL_PARSE_FAILED_MANIFEST_MALFORMED":        {"APK清单文件格式错误", RemedyNone},
+	"INSTALL_FAILED_DUPLICATE_PERMISSION":            {"APK声明的自定义权限已被其他应用定义", RemedyNone},
+	"INSTALL_FAILED_CONFLICTING_PROVIDER":            {"APK的ContentProvider authority与已安装应用冲突", RemedyNone},
+	"INSTALL_FAILED_MISSING_SHARED_LIBRARY":          {"设备缺少APK依赖的共享库", RemedyNone},
+	"INSTALL_FAILED_MISSING_SPLIT":                   {"缺少必需的拆分APK，请使用完整的安装包", RemedyNone},
+	"INSTALL_FAILED_USER_RESTRICTED":                 {"设备禁止通过USB安装应用，或用户在设备上拒绝了安装（部分厂商需要在开发者选项中开启“USB安装”）", RemedyNone},
+	"INSTALL_FAILED_VERIFICATION_FAILURE":            {"安装包校验失败，可能被系统安全策略拦截", RemedyNone},
+	"INSTALL_FAILED_ABORTED":                         {"安装被中止（可能在设备上取消了安装确认）", RemedyNone},
+	"INSTALL_FAILED_DEXOPT":                          {"dex优化失败，通常是存储空间不足或APK损坏", RemedyTrimCaches},
+	"INSTALL_FAILED_INVALID_URI":                     {"APK路径无效", RemedyNone},
+	"INSTALL_FAILED_INTERNAL_ERROR":                  {"系统内部错误，请查看logcat中PackageManager的输出", RemedyNone},
+	"INSTALL_FAILED_SESSION_INVALID":                 {"安装会话无效或已过期", RemedyNone},
+}
+
+var installFailurePattern = regexp.MustCompile(`((?:INSTALL_FAILED|INSTALL_PARSE_FAILED)_[A-Z_]+)(?::?\s*([^\]\r\n]*))?`)
+
+// ParseInstallFailure 从安装输出中识别失败原因，未识别到错误码时返回nil
+func ParseInstallFailure(output string) *InstallFailure {
+	match := installFailurePattern.FindStringSubmatch(output)
+	if match == nil {
+		return nil
+	}
+
+	failure := &InstallFailure{
+		Code:    match[1],
+		Message: strings.TrimSpace(match[2]),
+	}
+	if info, ok := installFailureTable[failure.Code]; ok {
+		failure.Explanation = info.explanation
+		failure.Remedy = info.remedy
+	} else {
+		failure.Explanation = "未知的安装错误"
+	}
+	return failure
+}
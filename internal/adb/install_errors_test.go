@@ -0,0 +1,61 @@
+package adb
+
+import "testing"
+
+func TestParseInstallFailure(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     string
+		wantCode   string
+		wantMsg    string
+		wantRemedy string
+	}{
+		{
+			name:       "adb install 输出",
+			output:     "Performing Streamed Install\nadb: failed to install app.apk: Failure [INSTALL_FAILED_VERSION_DOWNGRADE: Downgrade detected: Update version code 1 is older than current 2]\n",
+			wantCode:   "INSTALL_FAILED_VERSION_DOWNGRADE",
+			wantMsg:    "Downgrade detected: Update version code 1 is older than current 2",
+			wantRemedy: RemedyDowngrade,
+		},
+		{
+			name:       "没有附加信息",
+			output:     "Failure [INSTALL_FAILED_ALREADY_EXISTS]",
+			wantCode:   "INSTALL_FAILED_ALREADY_EXISTS",
+			wantRemedy: RemedyReplace,
+		},
+		{
+			name:       "解析错误",
+			output:     "Failure [INSTALL_PARSE_FAILED_INCONSISTENT_CERTIFICATES: signatures do not match]\r\n",
+			wantCode:   "INSTALL_PARSE_FAILED_INCONSISTENT_CERTIFICATES",
+			wantMsg:    "signatures do not match",
+			wantRemedy: RemedyUninstallReinstall,
+		},
+		{
+			name:       "未知错误码",
+			output:     "Failure [INSTALL_FAILED_SOMETHING_NEW: x]",
+			wantCode:   "INSTALL_FAILED_SOMETHING_NEW",
+			wantMsg:    "x",
+			wantRemedy: RemedyNone,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := ParseInstallFailure(tt.output)
+			if f == nil {
+				t.Fatal("ParseInstallFailure returned nil")
+			}
+			if f.Code != tt.wantCode || f.Message != tt.wantMsg || f.Remedy != tt.wantRemedy {
+				t.Errorf("got {%s %q %q}, want {%s %q %q}", f.Code, f.Message, f.Remedy, tt.wantCode, tt.wantMsg, tt.wantRemedy)
+			}
+			if f.Explanation == "" {
+				t.Error("Explanation 为空")
+			}
+		})
+	}
+
+	for _, output := range []string{"", "Success", "Performing Streamed Install\nSuccess\n"} {
+		if f := ParseInstallFailure(output); f != nil {
+			t.Errorf("ParseInstallFailure(%q) = %+v, want nil", output, f)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package adb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSessionSplitName(t *testing.T) {
+	tests := []struct {
+		index int
+		path  string
+		want  string
+	}{
+		{0, "/tmp/x/base.apk", "0_base.apk"},
+		{1, `C:\Users\me\split_config.arm64_v8a.apk`, "1_split_config.arm64_v8a.apk"},
+		{2, "/tmp/my app;rm -rf $(id).apk", "2_my_app_rm_-rf___id_.apk"},
+		{3, "/tmp/中文.apk", "3___.apk"},
+	}
+	for _, tt := range tests {
+		if got := sessionSplitName(tt.index, tt.path); got != tt.want {
+			t.Errorf("sessionSplitName(%d, %q) = %q, want %q", tt.index, tt.path, got, tt.want)
+		}
+	}
+}
+
+// StreamInstall 的 install-write 经 exec-in 由设备上的 sh -c 执行，拆分名中不能出现 shell 元字符
+func TestStreamInstallWriteArgs(t *testing.T) {
+	files := []string{"/tmp/my app.apk", "/tmp/x;rm -rf $(id).apk", `C:\apks\base.apk`}
+	for i, file := range files {
+		args := streamInstallWriteArgs("emulator-5554", 1024, "123", sessionSplitName(i, file))
+		want := []string{"-s", "emulator-5554", "exec-in", "cmd", "package", "install-write", "-S", "1024", "123", "", "-"}
+		want[9] = sessionSplitName(i, file)
+		if !reflect.DeepEqual(args, want) {
+			t.Errorf("args = %q, want %q", args, want)
+		}
+		for _, arg := range args[2:] {
+			if strings.ContainsAny(arg, " ;$()`'\"|&<>\\") {
+				t.Errorf("参数 %q 含有 shell 元字符", arg)
+			}
+		}
+	}
+}
@@ -0,0 +1,431 @@
+package adb
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"yikong/internal/constants"
+)
+
+// MacroExtension 宏文件扩展名
+const MacroExtension = ".macro"
+
+// 宏步骤类型
+const (
+	MacroTap   = "tap"
+	MacroSwipe = "swipe"
+	MacroText  = "text"
+	MacroKey   = "key"
+)
+
+// MacroStep 宏中的一步操作，Delay 为距上一步的等待时间
+type MacroStep struct {
+	Delay    time.Duration
+	Action   string
+	X, Y     int
+	X2, Y2   int
+	Duration time.Duration // 滑动时长
+	Text     string
+	KeyCode  int
+}
+
+// Macro 录制的输入宏。Width/Height 为录制时设备当前方向下的屏幕尺寸，
+// 回放到分辨率不同的设备时按比例换算坐标
+type Macro struct {
+	Width  int
+	Height int
+	Steps  []MacroStep
+}
+
+// Duration 宏执行一遍的总时长（不含命令本身的耗时）
+func (m *Macro) Duration() time.Duration {
+	var total time.Duration
+	for _, step := range m.Steps {
+		total += step.Delay + step.Duration
+	}
+	return total
+}
+
+// FormatMacro 将宏格式化为便于手工编辑的文本，每行一条指令：
+//
+//	screen 1080x2400
+//	wait 500
+//	tap 540 1200
+//	swipe 100 800 100 200 300
+//	text hello world
+//	key 4
+//
+// 以 # 开头的行为注释
+func FormatMacro(m *Macro) string {
+	var sb strings.Builder
+	sb.WriteString("# yikong 输入宏\n")
+	if m.Width > 0 && m.Height > 0 {
+		fmt.Fprintf(&sb, "screen %dx%d\n", m.Width, m.Height)
+	}
+	for _, step := range m.Steps {
+		if step.Delay > 0 {
+			fmt.Fprintf(&sb, "wait %d\n", step.Delay.Milliseconds())
+		}
+		switch step.Action {
+		case MacroTap:
+			fmt.Fprintf(&sb, "tap %d %d\n", step.X, step.Y)
+		case MacroSwipe:
+			fmt.Fprintf(&sb, "swipe %d %d %d %d %d\n", step.X, step.Y, step.X2, step.Y2, step.Duration.Milliseconds())
+		case MacroText:
+			fmt.Fprintf(&sb, "text %s\n", step.Text)
+		case MacroKey:
+			if name, ok := constants.KeyCodeMap[step.KeyCode]; ok {
+				fmt.Fprintf(&sb, "key %d # %s\n", step.KeyCode, name)
+			} else {
+				fmt.Fprintf(&sb, "key %d\n", step.KeyCode)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// ParseMacro 解析 FormatMacro 生成（或手工编辑）的宏文本
+func ParseMacro(text string) (*Macro, error) {
+	m := &Macro{}
+	var pending time.Duration
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		action, rest, _ := strings.Cut(line, " ")
+		if action == MacroText {
+			// 文本原样保留（包括其中的 # 与空格）
+			m.Steps = append(m.Steps, MacroStep{Delay: pending, Action: MacroText, Text: rest})
+			pending = 0
+			continue
+		}
+		if i := strings.Index(rest, "#"); i >= 0 {
+			rest = rest[:i]
+		}
+		fields := strings.Fields(rest)
+
+		if action == "screen" {
+			if len(fields) != 1 {
+				return nil, fmt.Errorf("第 %d 行: screen 需要 宽x高", lineNo)
+			}
+			w, h, ok := strings.Cut(fields[0], "x")
+			width, err1 := strconv.Atoi(w)
+			height, err2 := strconv.Atoi(h)
+			if !ok || err1 != nil || err2 != nil || width <= 0 || height <= 0 {
+				return nil, fmt.Errorf("第 %d 行: 无效的屏幕尺寸 %q", lineNo, fields[0])
+			}
+			m.Width, m.Height = width, height
+			continue
+		}
+
+		nums := make([]int, len(fields))
+		for i, f := range fields {
+			n, err := strconv.Atoi(f)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("第 %d 行: 无效的数值 %q", lineNo, f)
+			}
+			nums[i] = n
+		}
+		expect := map[string]int{"wait": 1, MacroTap: 2, MacroSwipe: 5, MacroKey: 1}
+		count, ok := expect[action]
+		if !ok {
+			return nil, fmt.Errorf("第 %d 行: 未知指令 %q", lineNo, action)
+		}
+		if len(nums) != count {
+			return nil, fmt.Errorf("第 %d 行: %s 需要 %d 个参数", lineNo, action, count)
+		}
+
+		step := MacroStep{Delay: pending, Action: action}
+		switch action {
+		case "wait":
+			pending += time.Duration(nums[0]) * time.Millisecond
+			continue
+		case MacroTap:
+			step.X, step.Y = nums[0], nums[1]
+		case MacroSwipe:
+			step.X, step.Y, step.X2, step.Y2 = nums[0], nums[1], nums[2], nums[3]
+			step.Duration = time.Duration(nums[4]) * time.Millisecond
+		case MacroKey:
+			step.KeyCode = nums[0]
+		}
+		m.Steps = append(m.Steps, step)
+		pending = 0
+	}
+	if len(m.Steps) == 0 {
+		return nil, fmt.Errorf("宏中没有任何操作")
+	}
+	return m, nil
+}
+
+// LoadMacro 从文件读取宏
+func LoadMacro(path string) (*Macro, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMacro(string(data))
+}
+
+// SaveMacro 将宏保存为文本文件
+func SaveMacro(path string, m *Macro) error {
+	return os.WriteFile(path, []byte(FormatMacro(m)), 0644)
+}
+
+// RunMacroStep 在设备上执行一步操作（不处理等待时间）
+func RunMacroStep(deviceID string, step MacroStep) error {
+	switch step.Action {
+	case MacroTap:
+		return Tap(deviceID, step.X, step.Y)
+	case MacroSwipe:
+		return Swipe(deviceID, step.X, step.Y, step.X2, step.Y2, step.Duration)
+	case MacroText:
+		return InputText(deviceID, step.Text)
+	case MacroKey:
+		return KeyEvent(deviceID, step.KeyCode)
+	}
+	return fmt.Errorf("未知的宏操作: %s", step.Action)
+}
+
+// PlayMacro 在设备上回放宏。speed 为速度倍数（2 表示两倍速），loops 为循环次数，
+// 小于等于 0 时一直循环直到 ctx 取消；onStep 在执行每一步前回调（loop 从 1 开始）
+func PlayMacro(ctx context.Context, deviceID string, m *Macro, speed float64, loops int, onStep func(loop, index int)) error {
+	if speed <= 0 {
+		speed = 1
+	}
+	scaleX, scaleY := 1.0, 1.0
+	if m.Width > 0 && m.Height > 0 {
+		display, err := GetDisplayInfo(deviceID)
+		if err != nil {
+			return err
+		}
+		width, height := display.CurrentSize()
+		scaleX = float64(width) / float64(m.Width)
+		scaleY = float64(height) / float64(m.Height)
+	}
+	scalePoint := func(x, y int) (int, int) {
+		return int(math.Round(float64(x) * scaleX)), int(math.Round(float64(y) * scaleY))
+	}
+	log.Printf("回放宏: deviceID=%s, %d 步, 速度 %.2gx, 循环 %d 次", deviceID, len(m.Steps), speed, loops)
+
+	for loop := 1; loops <= 0 || loop <= loops; loop++ {
+		for i, step := range m.Steps {
+			if step.Delay > 0 {
+				timer := time.NewTimer(time.Duration(float64(step.Delay) / speed))
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				case <-timer.C:
+				}
+			} else if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if onStep != nil {
+				onStep(loop, i)
+			}
+
+			step.X, step.Y = scalePoint(step.X, step.Y)
+			step.X2, step.Y2 = scalePoint(step.X2, step.Y2)
+			if step.Duration > 0 {
+				step.Duration = max(time.Duration(float64(step.Duration)/speed), 10*time.Millisecond)
+			}
+			if err := RunMacroStep(deviceID, step); err != nil {
+				return fmt.Errorf("第 %d 步 (%s) 执行失败: %w", i+1, step.Action, err)
+			}
+		}
+	}
+	return nil
+}
+
+// MacroRecorder 收集录制的宏步骤，根据记录时间自动计算步骤间的等待时间
+type MacroRecorder struct {
+	mu     sync.Mutex
+	macro  Macro
+	last   time.Time
+	onStep func(step MacroStep)
+}
+
+// NewMacroRecorder 创建录制器；width/height 为录制设备当前方向下的屏幕尺寸，
+// onStep 在每记录一步后回调（可为 nil）
+func NewMacroRecorder(width, height int, onStep func(step MacroStep)) *MacroRecorder {
+	return &MacroRecorder{macro: Macro{Width: width, Height: height}, onStep: onStep}
+}
+
+// Record 记录刚刚结束的一步（滑动的开始时刻按其时长向前推算）
+func (r *MacroRecorder) Record(step MacroStep) {
+	r.RecordAt(step, time.Now().Add(-step.Duration))
+}
+
+// RecordAt 记录在 at 时刻开始的一步，第一步不等待。
+// 回放时 input swipe 会阻塞整个滑动时长，因此下一步的等待从滑动结束时算起
+func (r *MacroRecorder) RecordAt(step MacroStep, at time.Time) {
+	r.mu.Lock()
+	if !r.last.IsZero() {
+		step.Delay = max(at.Sub(r.last), 0)
+	} else {
+		step.Delay = 0
+	}
+	r.last = at.Add(step.Duration)
+	r.macro.Steps = append(r.macro.Steps, step)
+	onStep := r.onStep
+	r.mu.Unlock()
+
+	if onStep != nil {
+		onStep(step)
+	}
+}
+
+// Macro 返回已录制内容的副本
+func (r *MacroRecorder) Macro() *Macro {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := r.macro
+	m.Steps = append([]MacroStep(nil), r.macro.Steps...)
+	return &m
+}
+
+// linuxKeyCodes getevent 中的按键名称与 Android keycode 的对应关系（常见实体按键）
+var linuxKeyCodes = map[string]int{
+	"KEY_BACK":       constants.KeyCodeBack,
+	"KEY_HOME":       constants.KeyCodeHome,
+	"KEY_HOMEPAGE":   constants.KeyCodeHome,
+	"KEY_APPSELECT":  constants.KeyCodeAppSwitch,
+	"KEY_MENU":       constants.KeyCodeMenu,
+	"KEY_POWER":      constants.KeyCodePower,
+	"KEY_VOLUMEUP":   constants.KeyCodeVolumeUp,
+	"KEY_VOLUMEDOWN": constants.KeyCodeVolumeDown,
+	"KEY_CAMERA":     constants.KeyCodeCamera,
+	"KEY_SEARCH":     constants.KeyCodeSearch,
+}
+
+// 按下后移动距离小于屏幕宽度的该比例、且时长较短时识别为点击
+const (
+	tapSlopRatio   = 0.02
+	tapMaxDuration = 400 * time.Millisecond
+)
+
+// touchTracker 将触摸屏的原始事件识别为点击与滑动（只跟踪第一根手指）
+type touchTracker struct {
+	axisX, axisY AbsInfo
+	display      *DisplayInfo
+
+	slot     int
+	active   map[int]bool // 按下中的触点（多点协议 B 的 slot）
+	x, y     int          // 第一根手指的原始坐标
+	btnTouch bool         // 单点协议的 BTN_TOUCH 状态
+
+	down           bool
+	downAt         time.Duration
+	startX, startY int
+}
+
+// toScreen 原始坐标换算为当前方向下的 input 坐标
+func (t *touchTracker) toScreen(x, y int) (int, int) {
+	nx := (x - t.axisX.Min) * t.display.Width / max(t.axisX.Max-t.axisX.Min+1, 1)
+	ny := (y - t.axisY.Min) * t.display.Height / max(t.axisY.Max-t.axisY.Min+1, 1)
+	return t.display.MapNaturalPoint(nx, ny)
+}
+
+// handle 处理一条事件，识别出完整的点击/滑动时返回步骤与按下时刻
+func (t *touchTracker) handle(event InputEvent) (MacroStep, time.Duration, bool) {
+	switch event.Code {
+	case "ABS_MT_SLOT":
+		t.slot = int(event.Value)
+	case "ABS_MT_TRACKING_ID":
+		if event.Value < 0 {
+			delete(t.active, t.slot)
+		} else {
+			t.active[t.slot] = true
+		}
+	case "ABS_MT_POSITION_X", "ABS_X":
+		if t.slot == 0 || event.Code == "ABS_X" {
+			t.x = int(event.Value)
+		}
+	case "ABS_MT_POSITION_Y", "ABS_Y":
+		if t.slot == 0 || event.Code == "ABS_Y" {
+			t.y = int(event.Value)
+		}
+	case "BTN_TOUCH":
+		t.btnTouch = event.Value != 0
+	case "SYN_REPORT":
+		touching := len(t.active) > 0 || t.btnTouch
+		if touching && !t.down {
+			t.down = true
+			t.downAt = event.Time
+			t.startX, t.startY = t.x, t.y
+		} else if !touching && t.down {
+			t.down = false
+			x1, y1 := t.toScreen(t.startX, t.startY)
+			x2, y2 := t.toScreen(t.x, t.y)
+			duration := event.Time - t.downAt
+			slop := float64(t.display.Width) * tapSlopRatio
+			if math.Hypot(float64(x2-x1), float64(y2-y1)) < slop && duration < tapMaxDuration {
+				return MacroStep{Action: MacroTap, X: x1, Y: y1}, t.downAt, true
+			}
+			// 原地长按同样以 swipe 表示
+			return MacroStep{Action: MacroSwipe, X: x1, Y: y1, X2: x2, Y2: y2, Duration: max(duration, 10*time.Millisecond)}, t.downAt, true
+		}
+	}
+	return MacroStep{}, 0, false
+}
+
+// RecordDeviceInput 通过 getevent -lt 录制设备上真实的触摸与实体按键操作，直到 ctx 取消。
+// 返回的录制器在录制过程中即可读取；屏幕尺寸取录制开始时的方向
+func RecordDeviceInput(ctx context.Context, deviceID string, onStep func(step MacroStep)) (*MacroRecorder, <-chan error, error) {
+	display, err := GetDisplayInfo(deviceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	devices, err := GetInputDevices(deviceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	touchscreen, err := FindTouchscreen(devices)
+	if err != nil {
+		return nil, nil, err
+	}
+	tracker := &touchTracker{
+		axisX:   touchscreen.Axes["ABS_MT_POSITION_X"],
+		axisY:   touchscreen.Axes["ABS_MT_POSITION_Y"],
+		display: display,
+		active:  make(map[int]bool),
+	}
+	if _, ok := touchscreen.Axes["ABS_MT_POSITION_X"]; !ok {
+		tracker.axisX, tracker.axisY = touchscreen.Axes["ABS_X"], touchscreen.Axes["ABS_Y"]
+	}
+	log.Printf("录制设备输入: deviceID=%s, 触摸屏 %s (%s)", deviceID, touchscreen.Path, touchscreen.Name)
+
+	width, height := display.CurrentSize()
+	recorder := NewMacroRecorder(width, height, onStep)
+	// 以内核时间戳计算步骤间隔，不受 adb 输出缓冲的影响
+	base := time.Now()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- StreamGetevent(ctx, deviceID, true, func(event InputEvent) {
+			if event.Type == "EV_KEY" && event.Value == 1 {
+				if keyCode, ok := linuxKeyCodes[event.Code]; ok {
+					recorder.RecordAt(MacroStep{Action: MacroKey, KeyCode: keyCode}, base.Add(event.Time))
+					return
+				}
+			}
+			if event.Device != touchscreen.Path {
+				return
+			}
+			if step, at, ok := tracker.handle(event); ok {
+				recorder.RecordAt(step, base.Add(at))
+			}
+		})
+	}()
+	return recorder, done, nil
+}
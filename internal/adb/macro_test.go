@@ -0,0 +1,64 @@
+package adb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseMacro(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    *Macro
+		wantErr bool
+	}{
+		{
+			name: "全部指令",
+			text: "# 注释\nscreen 1080x2400\nwait 500\ntap 540 1200\nwait 100\nwait 200\n" +
+				"swipe 100 800 100 200 300\ntext hello # world\nkey 4 # KEYCODE_BACK\n",
+			want: &Macro{Width: 1080, Height: 2400, Steps: []MacroStep{
+				{Delay: 500 * time.Millisecond, Action: MacroTap, X: 540, Y: 1200},
+				{Delay: 300 * time.Millisecond, Action: MacroSwipe, X: 100, Y: 800, X2: 100, Y2: 200, Duration: 300 * time.Millisecond},
+				{Action: MacroText, Text: "hello # world"},
+				{Action: MacroKey, KeyCode: 4},
+			}},
+		},
+		{name: "没有操作", text: "# 空\nscreen 1080x2400\nwait 100\n", wantErr: true},
+		{name: "未知指令", text: "click 1 2\n", wantErr: true},
+		{name: "参数个数不对", text: "tap 1\n", wantErr: true},
+		{name: "负数", text: "tap -1 2\n", wantErr: true},
+		{name: "无效屏幕尺寸", text: "screen 1080*2400\ntap 1 2\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMacro(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMacroRoundTrip(t *testing.T) {
+	m := &Macro{Width: 720, Height: 1280, Steps: []MacroStep{
+		{Action: MacroTap, X: 1, Y: 2},
+		{Delay: 1500 * time.Millisecond, Action: MacroSwipe, X: 10, Y: 20, X2: 30, Y2: 40, Duration: 250 * time.Millisecond},
+		{Delay: time.Second, Action: MacroText, Text: "a b#c"},
+		{Action: MacroKey, KeyCode: 3},
+		{Action: MacroKey, KeyCode: 9999},
+	}}
+	got, err := ParseMacro(FormatMacro(m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("round trip mismatch\ngot  %+v\nwant %+v\ntext:\n%s", got, m, FormatMacro(m))
+	}
+	if d := m.Duration(); d != 2750*time.Millisecond {
+		t.Errorf("Duration() = %v, want 2.75s", d)
+	}
+}
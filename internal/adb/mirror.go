@@ -0,0 +1,87 @@
+package adb
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+)
+
+// screencap 原始输出的像素格式（android PixelFormat）
+const (
+	pixelFormatRGBA8888 = 1
+	pixelFormatRGBX8888 = 2
+	pixelFormatBGRA8888 = 5
+)
+
+// CaptureRawFrame 通过 exec-out screencap（不加 -p）获取一帧未压缩的屏幕图像。
+// 省去设备端 PNG 编码，是目前不依赖视频解码器时延迟最低的取帧方式；
+// screenrecord --output-format=h264 的码流需要 H.264 解码器，纯 Go 环境下暂不支持
+func CaptureRawFrame(ctx context.Context, deviceID string) (*image.RGBA, error) {
+	var buf bytes.Buffer
+	if err := ExecOut(ctx, deviceID, "screencap", &buf); err != nil {
+		return nil, err
+	}
+	return parseRawScreencap(buf.Bytes())
+}
+
+// parseRawScreencap 解析 screencap 的原始输出：宽、高、格式各 4 字节（小端），
+// Android 9 起多 4 字节的色彩空间，之后是逐行的像素数据
+func parseRawScreencap(data []byte) (*image.RGBA, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("screencap 输出过短: %d 字节", len(data))
+	}
+	width := int(binary.LittleEndian.Uint32(data[0:4]))
+	height := int(binary.LittleEndian.Uint32(data[4:8]))
+	format := binary.LittleEndian.Uint32(data[8:12])
+	if width <= 0 || height <= 0 || width > 16384 || height > 16384 {
+		return nil, fmt.Errorf("screencap 尺寸无效: %dx%d", width, height)
+	}
+
+	pixelBytes := width * height * 4
+	headerSize := len(data) - pixelBytes
+	if headerSize != 12 && headerSize != 16 {
+		return nil, fmt.Errorf("screencap 数据长度不符: %dx%d 格式 %d，共 %d 字节", width, height, format, len(data))
+	}
+	pixels := data[headerSize:]
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	switch format {
+	case pixelFormatRGBA8888, pixelFormatRGBX8888:
+		copy(img.Pix, pixels)
+		if format == pixelFormatRGBX8888 {
+			for i := 3; i < len(img.Pix); i += 4 {
+				img.Pix[i] = 0xff
+			}
+		}
+	case pixelFormatBGRA8888:
+		for i := 0; i < len(pixels); i += 4 {
+			img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = pixels[i+2], pixels[i+1], pixels[i], pixels[i+3]
+		}
+	default:
+		return nil, fmt.Errorf("不支持的像素格式: %d", format)
+	}
+	return img, nil
+}
+
+// ScaleImage 以最近邻方式缩放图像，用于降低界面上传纹理的开销；scale 为 1 时原样返回
+func ScaleImage(src *image.RGBA, scale float64) *image.RGBA {
+	if scale >= 1 || scale <= 0 {
+		return src
+	}
+	bounds := src.Bounds()
+	width := max(int(float64(bounds.Dx())*scale), 1)
+	height := max(int(float64(bounds.Dy())*scale), 1)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		sy := y * bounds.Dy() / height
+		srcRow := src.Pix[sy*src.Stride:]
+		dstRow := dst.Pix[y*dst.Stride:]
+		for x := 0; x < width; x++ {
+			sx := x * bounds.Dx() / width * 4
+			copy(dstRow[x*4:x*4+4], srcRow[sx:sx+4])
+		}
+	}
+	return dst
+}
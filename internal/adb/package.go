@@ -0,0 +1,123 @@
+package adb
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InstalledPackage 设备上已安装应用的版本信息
+type InstalledPackage struct {
+	PackageName string
+	VersionCode int64
+	VersionName string
+	MinSDK      int
+	TargetSDK   int
+}
+
+// GetInstalledPackage 通过 dumpsys package 获取设备上某个应用的版本信息
+// 应用未安装时返回 (nil, nil)
+func GetInstalledPackage(deviceID string, packageName string) (*InstalledPackage, error) {
+	log.Printf("查询已安装应用: deviceID=%s, package=%s", deviceID, packageName)
+	result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "dumpsys", "package", packageName}, 15*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := parseDumpsysPackageVersion(result.Output, packageName)
+	if pkg == nil {
+		log.Printf("设备上未安装该应用: deviceID=%s, package=%s", deviceID, packageName)
+	}
+	return pkg, nil
+}
+
+// parseDumpsysPackageVersion 从 dumpsys package 输出中解析 "Package [xxx]" 段的版本字段
+func parseDumpsysPackageVersion(output string, packageName string) *InstalledPackage {
+	header := "Package [" + packageName + "]"
+	start := strings.Index(output, header)
+	if start < 0 {
+		return nil
+	}
+	section := output[start+len(header):]
+	// 只看当前包的段落，遇到下一个包即停止
+	if next := strings.Index(section, "Package ["); next >= 0 {
+		section = section[:next]
+	}
+
+	pkg := &InstalledPackage{PackageName: packageName}
+	for _, field := range strings.Fields(section) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "versionCode":
+			if pkg.VersionCode == 0 {
+				pkg.VersionCode, _ = strconv.ParseInt(value, 10, 64)
+			}
+		case "versionName":
+			if pkg.VersionName == "" {
+				pkg.VersionName = value
+			}
+		case "minSdk":
+			if pkg.MinSDK == 0 {
+				pkg.MinSDK, _ = strconv.Atoi(value)
+			}
+		case "targetSdk":
+			if pkg.TargetSDK == 0 {
+				pkg.TargetSDK, _ = strconv.Atoi(value)
+			}
+		}
+	}
+	return pkg
+}
+
+// CompareVersionCode 比较待安装版本与已安装版本，返回可读的说明
+func CompareVersionCode(apkVersionCode int64, installed *InstalledPackage) string {
+	if installed == nil {
+		return "设备上未安装该应用，将进行全新安装"
+	}
+	switch {
+	case apkVersionCode > installed.VersionCode:
+		return fmt.Sprintf("升级: 已安装 %s (%d) → APK %d", installed.VersionName, installed.VersionCode, apkVersionCode)
+	case apkVersionCode < installed.VersionCode:
+		return fmt.Sprintf("降级: 已安装 %s (%d) 高于 APK %d，安装需要 -d 参数", installed.VersionName, installed.VersionCode, apkVersionCode)
+	default:
+		return fmt.Sprintf("版本相同: 已安装 %s (%d)，安装将覆盖（需要 -r 参数）", installed.VersionName, installed.VersionCode)
+	}
+}
+
+// ListPackages 列出设备上的应用包名，thirdPartyOnly 为 true 时只列出第三方应用
+func ListPackages(deviceID string, thirdPartyOnly bool) ([]string, error) {
+	args := []string{"shell", "pm", "list", "packages"}
+	if thirdPartyOnly {
+		args = append(args, "-3")
+	}
+	result, err := ExecuteADBCommandWithDevice(deviceID, args, 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	var packages []string
+	for _, line := range strings.Split(result.Output, "\n") {
+		line = strings.TrimSpace(line)
+		if name, ok := strings.CutPrefix(line, "package:"); ok && name != "" {
+			packages = append(packages, name)
+		}
+	}
+	sort.Strings(packages)
+	return packages, nil
+}
+
+// ClearAppData 清除应用数据（pm clear），应用会回到首次安装后的状态
+func ClearAppData(deviceID string, packageName string) error {
+	return runShellCommand(deviceID, "pm", "clear", packageName)
+}
+
+// ForceStop 强制停止应用
+func ForceStop(deviceID string, packageName string) error {
+	return runShellCommand(deviceID, "am", "force-stop", packageName)
+}
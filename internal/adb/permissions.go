@@ -0,0 +1,253 @@
+package adb
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AppOpModes AppOps 可设置的模式
+var AppOpModes = []string{"allow", "ignore", "deny", "default", "foreground"}
+
+// Permission 应用的一个权限及其授予状态
+type Permission struct {
+	Name    string
+	Runtime bool // 运行时（危险）权限，可通过 pm grant/revoke 切换
+	Granted bool
+	Flags   []string // 如 USER_SET、USER_FIXED、POLICY_FIXED
+}
+
+// AppOp 应用的一个 AppOps 项
+type AppOp struct {
+	Name   string
+	Mode   string
+	Detail string // 模式之后的附加信息，如最近访问时间
+}
+
+// GetPackagePermissions 解析 dumpsys package 中的 requested/install/runtime 权限
+func GetPackagePermissions(deviceID string, packageName string) ([]Permission, error) {
+	log.Printf("获取应用权限: deviceID=%s, package=%s", deviceID, packageName)
+	result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "dumpsys", "package", packageName}, 15*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	perms := parseDumpsysPermissions(result.Output, packageName)
+	if perms == nil {
+		return nil, fmt.Errorf("设备上未安装应用: %s", packageName)
+	}
+	return perms, nil
+}
+
+// parseDumpsysPermissions 只解析 "Package [pkg]" 段落，返回按名称排序的权限列表
+func parseDumpsysPermissions(output string, packageName string) []Permission {
+	header := "Package [" + packageName + "]"
+	start := strings.Index(output, header)
+	if start < 0 {
+		return nil
+	}
+	section := output[start+len(header):]
+	if next := strings.Index(section, "\n  Package ["); next >= 0 {
+		section = section[:next]
+	}
+
+	perms := make(map[string]*Permission)
+	get := func(name string) *Permission {
+		if p, ok := perms[name]; ok {
+			return p
+		}
+		p := &Permission{Name: name}
+		perms[name] = p
+		return p
+	}
+
+	const (
+		modeNone = iota
+		modeRequested
+		modeInstall
+		modeRuntime
+	)
+	mode := modeNone
+	modeIndent := 0
+
+	scanner := bufio.NewScanner(strings.NewReader(section))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		raw := strings.TrimRight(scanner.Text(), "\r")
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+		indent := len(raw) - len(strings.TrimLeft(raw, " "))
+
+		switch line {
+		case "requested permissions:":
+			mode, modeIndent = modeRequested, indent
+			continue
+		case "install permissions:":
+			mode, modeIndent = modeInstall, indent
+			continue
+		case "runtime permissions:":
+			mode, modeIndent = modeRuntime, indent
+			continue
+		}
+
+		// 缩进回退到段落标题层级即表示该段结束
+		if mode != modeNone && indent <= modeIndent {
+			mode = modeNone
+		}
+
+		switch mode {
+		case modeRequested:
+			// 可能带有 ": restricted=true" 等后缀
+			name := line
+			if end := strings.IndexAny(line, ",:"); end >= 0 {
+				name = line[:end]
+			}
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			get(name)
+		case modeInstall, modeRuntime:
+			name, rest, ok := strings.Cut(line, ":")
+			if name = strings.TrimSpace(name); !ok || name == "" {
+				continue
+			}
+			p := get(name)
+			p.Runtime = mode == modeRuntime
+			p.Granted = strings.Contains(rest, "granted=true")
+			p.Flags = parsePermissionFlags(rest)
+		}
+	}
+
+	names := make([]string, 0, len(perms))
+	for name := range perms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := make([]Permission, 0, len(names))
+	for _, name := range names {
+		result = append(result, *perms[name])
+	}
+	return result
+}
+
+// parsePermissionFlags 解析 "flags=[ USER_SET|USER_FIXED ]"
+func parsePermissionFlags(text string) []string {
+	idx := strings.Index(text, "flags=[")
+	if idx < 0 {
+		return nil
+	}
+	rest := text[idx+len("flags=["):]
+	end := strings.Index(rest, "]")
+	if end < 0 {
+		return nil
+	}
+	var flags []string
+	for _, flag := range strings.Split(rest[:end], "|") {
+		if flag = strings.TrimSpace(flag); flag != "" {
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
+
+// GrantPermission 授予运行时权限
+func GrantPermission(deviceID string, packageName string, permission string) error {
+	return runShellCommand(deviceID, "pm", "grant", packageName, permission)
+}
+
+// RevokePermission 撤销运行时权限
+func RevokePermission(deviceID string, packageName string, permission string) error {
+	return runShellCommand(deviceID, "pm", "revoke", packageName, permission)
+}
+
+// ResetRuntimePermissions 撤销应用的全部运行时权限并清除用户选择标志，
+// 使应用回到首次启动时的权限请求流程
+func ResetRuntimePermissions(deviceID string, packageName string, permissions []Permission) error {
+	var failed []string
+	for _, p := range permissions {
+		if !p.Runtime {
+			continue
+		}
+		if err := RevokePermission(deviceID, packageName, p.Name); err != nil {
+			failed = append(failed, p.Name)
+			continue
+		}
+		// Android 10 以下没有 clear-permission-flags，忽略错误
+		runShellCommand(deviceID, "pm", "clear-permission-flags", packageName, p.Name, "user-set", "user-fixed")
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("以下权限撤销失败: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// GetAppOps 获取应用的 AppOps 状态（appops get）
+func GetAppOps(deviceID string, packageName string) ([]AppOp, error) {
+	result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "appops", "get", packageName}, 15*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	return parseAppOps(result.Output), nil
+}
+
+// parseAppOps 解析 "CAMERA: allow; time=+1h ago" 或 "Uid mode: CAMERA: ignore" 格式
+func parseAppOps(output string) []AppOp {
+	seen := make(map[string]int)
+	var ops []AppOp
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimPrefix(line, "Uid mode: ")
+		name, rest, ok := strings.Cut(line, ":")
+		if !ok || name == "" || strings.ContainsAny(name, " =") {
+			continue
+		}
+		rest = strings.TrimSpace(rest)
+		mode, detail, _ := strings.Cut(rest, ";")
+		mode = strings.TrimSpace(mode)
+		if mode == "" || strings.ContainsAny(mode, " =") {
+			continue
+		}
+
+		op := AppOp{Name: name, Mode: mode, Detail: strings.TrimSpace(detail)}
+		if idx, ok := seen[name]; ok {
+			// Uid 模式与包模式同时存在时保留包模式
+			ops[idx] = op
+			continue
+		}
+		seen[name] = len(ops)
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+// SetAppOpMode 设置 AppOps 模式，mode 取值见 AppOpModes
+func SetAppOpMode(deviceID string, packageName string, op string, mode string) error {
+	return runShellCommand(deviceID, "appops", "set", packageName, op, mode)
+}
+
+// ResetAppOps 将应用的全部 AppOps 恢复为默认值
+func ResetAppOps(deviceID string, packageName string) error {
+	return runShellCommand(deviceID, "appops", "reset", packageName)
+}
+
+// runShellCommand 执行 shell 命令；pm/appops 出错时退出码常为0，输出中包含错误信息时同样视为失败
+func runShellCommand(deviceID string, args ...string) error {
+	result, err := ExecuteADBCommandWithDevice(deviceID, append([]string{"shell"}, args...), 15*time.Second)
+	if err != nil {
+		if result != nil && strings.TrimSpace(result.ErrorOutput) != "" {
+			return fmt.Errorf("%s", strings.TrimSpace(result.ErrorOutput))
+		}
+		return err
+	}
+	output := strings.TrimSpace(result.Output + result.ErrorOutput)
+	if strings.HasPrefix(output, "Error") || strings.HasPrefix(output, "Failed") || strings.HasPrefix(output, "Failure") ||
+		strings.Contains(output, "Exception") || strings.Contains(output, "Unknown command") {
+		return fmt.Errorf("%s", output)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package adb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDumpsysPermissions(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []Permission
+	}{
+		{
+			name: "requested/install/runtime",
+			output: `Packages:
+  Package [com.example] (1234abcd):
+    userId=10123
+    requested permissions:
+      android.permission.INTERNET
+      android.permission.CAMERA
+      android.permission.POST_NOTIFICATIONS: restricted=true
+    install permissions:
+      android.permission.INTERNET: granted=true
+    User 0: ceDataInode=1 installed=true
+      runtime permissions:
+        android.permission.CAMERA: granted=false, flags=[ USER_SET|USER_FIXED ]
+        android.permission.POST_NOTIFICATIONS: granted=true, flags=[ ]
+  Package [com.other] (5678):
+    requested permissions:
+      android.permission.READ_CONTACTS
+`,
+			want: []Permission{
+				{Name: "android.permission.CAMERA", Runtime: true, Flags: []string{"USER_SET", "USER_FIXED"}},
+				{Name: "android.permission.INTERNET", Granted: true},
+				{Name: "android.permission.POST_NOTIFICATIONS", Runtime: true, Granted: true},
+			},
+		},
+		{
+			name: "只有分隔符的行不会崩溃",
+			output: `  Package [com.example] (1):
+    requested permissions:
+      ,
+      :
+      : restricted=true
+      android.permission.INTERNET
+    install permissions:
+      : granted=true
+`,
+			want: []Permission{{Name: "android.permission.INTERNET"}},
+		},
+		{
+			name:   "没有权限",
+			output: "  Package [com.example] (1):\n    userId=10123\n",
+			want:   []Permission{},
+		},
+		{
+			name:   "未安装",
+			output: "  Package [com.other] (1):\n",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseDumpsysPermissions(tt.output, "com.example")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
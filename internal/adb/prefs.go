@@ -0,0 +1,268 @@
+package adb
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SharedPreferences 值类型，与 XML 中的元素名一致
+const (
+	PrefString  = "string"
+	PrefInt     = "int"
+	PrefLong    = "long"
+	PrefFloat   = "float"
+	PrefBoolean = "boolean"
+	PrefSet     = "set"
+)
+
+// PrefTypes 可编辑的值类型
+var PrefTypes = []string{PrefString, PrefInt, PrefLong, PrefFloat, PrefBoolean, PrefSet}
+
+// PrefEntry SharedPreferences 中的一个键值。Set 类型的值保存在 Values 中；
+// 不支持编辑的元素（如 <null name="..." />）的原始 XML 保存在 Raw 中，写回时原样输出
+type PrefEntry struct {
+	Name   string
+	Type   string
+	Value  string
+	Values []string
+	Raw    string
+}
+
+// Opaque 是否为只能原样保留的元素
+func (e PrefEntry) Opaque() bool {
+	return e.Raw != ""
+}
+
+// appSharedPrefsDir 应用 SharedPreferences 目录
+func appSharedPrefsDir(packageName string) string {
+	return path.Join(appDataDir(packageName), "shared_prefs")
+}
+
+// ListSharedPrefs 列出应用的 SharedPreferences 文件（*.xml）
+func ListSharedPrefs(deviceID string, packageName string, access FileAccess) ([]string, error) {
+	entries, err := ListDirAs(deviceID, appSharedPrefsDir(packageName), access)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name, ".xml") {
+			names = append(names, entry.Name)
+		}
+	}
+	return names, nil
+}
+
+// ReadSharedPrefs 读取并解析应用的 SharedPreferences 文件
+func ReadSharedPrefs(deviceID string, packageName string, name string, access FileAccess) ([]PrefEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var buf bytes.Buffer
+	remotePath := path.Join(appSharedPrefsDir(packageName), name)
+	if err := ExecOut(ctx, deviceID, access.Wrap("cat "+shellQuote(remotePath)), &buf); err != nil {
+		return nil, err
+	}
+	return ParseSharedPrefs(buf.Bytes())
+}
+
+// WriteSharedPrefs 将键值写回应用的 SharedPreferences 文件。
+// 先强制停止应用，避免其在退出时用内存中的旧值覆盖文件；
+// 内容先写入临时文件再 mv 替换，并删除 .bak（应用启动时会优先用 .bak 恢复）
+func WriteSharedPrefs(ctx context.Context, deviceID string, packageName string, name string, entries []PrefEntry, access FileAccess) error {
+	log.Printf("写回SharedPreferences: deviceID=%s, package=%s, name=%s, entries=%d", deviceID, packageName, name, len(entries))
+	data, err := EncodeSharedPrefs(entries)
+	if err != nil {
+		return err
+	}
+	if err := ForceStop(deviceID, packageName); err != nil {
+		return fmt.Errorf("强制停止应用失败: %w", err)
+	}
+
+	dir := appSharedPrefsDir(packageName)
+	target := shellQuote(path.Join(dir, name))
+	tmp := shellQuote(path.Join(dir, "."+name+".tmp"))
+	command := "cat > " + tmp + " && chmod 660 " + tmp
+	if access.Mode == AccessSu {
+		// root 创建的文件需要改回应用的属主和 SELinux 标签
+		command += " && chown $(stat -c %u:%g " + shellQuote(dir) + ") " + tmp +
+			" && (restorecon " + tmp + " 2>/dev/null; true)"
+	}
+	command += " && mv -f " + tmp + " " + target + " && rm -f " + shellQuote(path.Join(dir, name+".bak"))
+
+	output, err := ExecIn(ctx, deviceID, access.Wrap(command), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if output = strings.TrimSpace(output); output != "" {
+		log.Printf("写回SharedPreferences输出: %s", output)
+	}
+	return nil
+}
+
+// ParseSharedPrefs 解析 SharedPreferences 的 XML 内容
+func ParseSharedPrefs(data []byte) ([]PrefEntry, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var entries []PrefEntry
+	inMap := false
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析XML失败: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if !inMap {
+			if start.Name.Local != "map" {
+				return nil, fmt.Errorf("不是 SharedPreferences 文件: 根元素为 <%s>", start.Name.Local)
+			}
+			inMap = true
+			continue
+		}
+
+		entry := PrefEntry{Name: xmlAttr(start, "name"), Type: start.Name.Local}
+		switch entry.Type {
+		case PrefString:
+			var text string
+			if err := decoder.DecodeElement(&text, &start); err != nil {
+				return nil, err
+			}
+			entry.Value = text
+		case PrefSet:
+			var set struct {
+				Strings []string `xml:"string"`
+			}
+			if err := decoder.DecodeElement(&set, &start); err != nil {
+				return nil, err
+			}
+			entry.Values = set.Strings
+		case PrefInt, PrefLong, PrefFloat, PrefBoolean:
+			entry.Value = xmlAttr(start, "value")
+			if err := decoder.Skip(); err != nil {
+				return nil, err
+			}
+		default:
+			// 其他类型（如 null）无法编辑，保留原始 XML 以便原样写回
+			if err := decoder.Skip(); err != nil {
+				return nil, err
+			}
+			entry.Raw = string(data[offset:decoder.InputOffset()])
+			log.Printf("保留不支持编辑的SharedPreferences类型: <%s name=%q>", entry.Type, entry.Name)
+		}
+		entries = append(entries, entry)
+	}
+	if !inMap {
+		return nil, fmt.Errorf("不是 SharedPreferences 文件: 缺少 <map>")
+	}
+	return entries, nil
+}
+
+func xmlAttr(start xml.StartElement, name string) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// EncodeSharedPrefs 按 Android XmlUtils 的格式生成 SharedPreferences XML
+func EncodeSharedPrefs(entries []PrefEntry) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("<?xml version='1.0' encoding='utf-8' standalone='yes' ?>\n<map>\n")
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Opaque() {
+			if entry.Name != "" && seen[entry.Name] {
+				return nil, fmt.Errorf("键名重复: %s", entry.Name)
+			}
+			seen[entry.Name] = true
+			b.WriteString("    " + entry.Raw + "\n")
+			continue
+		}
+		if entry.Name == "" {
+			return nil, fmt.Errorf("键名不能为空")
+		}
+		if seen[entry.Name] {
+			return nil, fmt.Errorf("键名重复: %s", entry.Name)
+		}
+		seen[entry.Name] = true
+		if err := ValidatePrefValue(entry.Type, entry.Value); err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name, err)
+		}
+
+		name := xmlEscape(entry.Name)
+		switch entry.Type {
+		case PrefString:
+			fmt.Fprintf(&b, "    <string name=\"%s\">%s</string>\n", name, xmlEscape(entry.Value))
+		case PrefSet:
+			if len(entry.Values) == 0 {
+				fmt.Fprintf(&b, "    <set name=\"%s\" />\n", name)
+				continue
+			}
+			fmt.Fprintf(&b, "    <set name=\"%s\">\n", name)
+			for _, value := range entry.Values {
+				fmt.Fprintf(&b, "        <string>%s</string>\n", xmlEscape(value))
+			}
+			b.WriteString("    </set>\n")
+		default:
+			fmt.Fprintf(&b, "    <%s name=\"%s\" value=\"%s\" />\n", entry.Type, name, xmlEscape(entry.Value))
+		}
+	}
+	b.WriteString("</map>\n")
+	return b.Bytes(), nil
+}
+
+// ValidatePrefValue 检查值是否符合类型，避免写入应用无法解析的文件
+func ValidatePrefValue(prefType string, value string) error {
+	switch prefType {
+	case PrefString, PrefSet:
+		return nil
+	case PrefInt:
+		if _, err := strconv.ParseInt(value, 10, 32); err != nil {
+			return fmt.Errorf("不是有效的 int: %q", value)
+		}
+	case PrefLong:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("不是有效的 long: %q", value)
+		}
+	case PrefFloat:
+		f, err := strconv.ParseFloat(value, 32)
+		if err != nil {
+			return fmt.Errorf("不是有效的 float: %q", value)
+		}
+		// Go 还接受 inf、nan 等写法，Java 的 Float.parseFloat 只认这三种
+		if (math.IsInf(f, 0) || math.IsNaN(f)) && value != "NaN" && value != "Infinity" && value != "-Infinity" {
+			return fmt.Errorf("不是有效的 float: %q", value)
+		}
+	case PrefBoolean:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("boolean 只能是 true 或 false: %q", value)
+		}
+	default:
+		return fmt.Errorf("不支持的类型: %s", prefType)
+	}
+	return nil
+}
+
+// xmlEscape 转义 XML 文本与属性值
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
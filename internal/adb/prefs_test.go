@@ -0,0 +1,168 @@
+package adb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSharedPrefs(t *testing.T) {
+	tests := []struct {
+		name    string
+		xml     string
+		want    []PrefEntry
+		wantErr bool
+	}{
+		{
+			name: "全部类型",
+			xml: `<?xml version='1.0' encoding='utf-8' standalone='yes' ?>
+<map>
+    <string name="token">a &lt;b&gt; &amp; &quot;c&quot;</string>
+    <int name="count" value="42" />
+    <long name="ts" value="1700000000000" />
+    <float name="ratio" value="0.5" />
+    <boolean name="enabled" value="true" />
+    <set name="tags">
+        <string>x</string>
+        <string>y</string>
+    </set>
+    <set name="empty" />
+</map>`,
+			want: []PrefEntry{
+				{Name: "token", Type: PrefString, Value: `a <b> & "c"`},
+				{Name: "count", Type: PrefInt, Value: "42"},
+				{Name: "ts", Type: PrefLong, Value: "1700000000000"},
+				{Name: "ratio", Type: PrefFloat, Value: "0.5"},
+				{Name: "enabled", Type: PrefBoolean, Value: "true"},
+				{Name: "tags", Type: PrefSet, Values: []string{"x", "y"}},
+				{Name: "empty", Type: PrefSet},
+			},
+		},
+		{
+			name: "null 与未知元素原样保留",
+			xml:  "<map>\n    <null name=\"gone\" />\n    <custom name=\"c\"><v>1</v></custom>\n</map>",
+			want: []PrefEntry{
+				{Name: "gone", Type: "null", Raw: `<null name="gone" />`},
+				{Name: "c", Type: "custom", Raw: `<custom name="c"><v>1</v></custom>`},
+			},
+		},
+		{
+			name: "空 map",
+			xml:  "<map />",
+			want: nil,
+		},
+		{
+			name:    "根元素不是 map",
+			xml:     "<resources><string name=\"a\">b</string></resources>",
+			wantErr: true,
+		},
+		{
+			name:    "空内容",
+			xml:     "",
+			wantErr: true,
+		},
+		{
+			name:    "XML 不完整",
+			xml:     "<map><string name=\"a\">b</map>",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSharedPrefs([]byte(tt.xml))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v\nwant %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSharedPrefsRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []PrefEntry
+	}{
+		{"空", nil},
+		{"字符串转义", []PrefEntry{{Name: "a&b", Type: PrefString, Value: "<x>\n'y' \"z\" & \t"}}},
+		{"数值与布尔", []PrefEntry{
+			{Name: "i", Type: PrefInt, Value: "-2147483648"},
+			{Name: "l", Type: PrefLong, Value: "9223372036854775807"},
+			{Name: "f", Type: PrefFloat, Value: "NaN"},
+			{Name: "b", Type: PrefBoolean, Value: "false"},
+		}},
+		{"集合", []PrefEntry{
+			{Name: "s", Type: PrefSet, Values: []string{"a", "<b>", "c&d"}},
+			{Name: "e", Type: PrefSet},
+		}},
+		{"保留 null", []PrefEntry{
+			{Name: "before", Type: PrefString, Value: "1"},
+			{Name: "gone", Type: "null", Raw: `<null name="gone" />`},
+			{Name: "after", Type: PrefInt, Value: "2"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := EncodeSharedPrefs(tt.entries)
+			if err != nil {
+				t.Fatalf("EncodeSharedPrefs: %v", err)
+			}
+			got, err := ParseSharedPrefs(data)
+			if err != nil {
+				t.Fatalf("ParseSharedPrefs: %v\n%s", err, data)
+			}
+			if !reflect.DeepEqual(got, tt.entries) {
+				t.Errorf("round trip mismatch\ngot  %#v\nwant %#v\nxml:\n%s", got, tt.entries, data)
+			}
+		})
+	}
+}
+
+// 修改其它键后写回，不支持编辑的元素必须保留
+func TestSharedPrefsEditKeepsOpaque(t *testing.T) {
+	src := "<map>\n    <null name=\"n\" />\n    <int name=\"count\" value=\"1\" />\n</map>\n"
+	entries, err := ParseSharedPrefs([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range entries {
+		if entries[i].Name == "count" {
+			entries[i].Value = "2"
+		}
+	}
+	data, err := EncodeSharedPrefs(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	if !strings.Contains(out, `<null name="n" />`) {
+		t.Errorf("null 元素丢失:\n%s", out)
+	}
+	if !strings.Contains(out, `<int name="count" value="2" />`) {
+		t.Errorf("修改未写入:\n%s", out)
+	}
+}
+
+func TestEncodeSharedPrefsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []PrefEntry
+	}{
+		{"空键名", []PrefEntry{{Type: PrefString}}},
+		{"键名重复", []PrefEntry{{Name: "a", Type: PrefString}, {Name: "a", Type: PrefInt, Value: "1"}}},
+		{"与保留元素重名", []PrefEntry{{Name: "a", Type: "null", Raw: `<null name="a" />`}, {Name: "a", Type: PrefString}}},
+		{"int 越界", []PrefEntry{{Name: "i", Type: PrefInt, Value: "2147483648"}}},
+		{"float 写法", []PrefEntry{{Name: "f", Type: PrefFloat, Value: "inf"}}},
+		{"boolean", []PrefEntry{{Name: "b", Type: PrefBoolean, Value: "1"}}},
+		{"未知类型", []PrefEntry{{Name: "x", Type: "double", Value: "1"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := EncodeSharedPrefs(tt.entries); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
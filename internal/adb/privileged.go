@@ -0,0 +1,320 @@
+package adb
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 设备文件访问方式
+const (
+	AccessDirect = "direct" // 以 shell 用户直接访问
+	AccessRunAs  = "run-as" // 以可调试应用的身份访问其沙盒
+	AccessSu     = "su"     // 通过 su -c 以 root 身份访问
+)
+
+// appDataPathPattern 匹配应用私有目录 /data/data/<pkg> 或 /data/user/<n>/<pkg>
+var appDataPathPattern = regexp.MustCompile(`^/data/(?:data|user/\d+|user_de/\d+)/([A-Za-z0-9_.]+)(?:/|$)`)
+
+// packageNamePattern 合法的应用包名，字符集与 appDataPathPattern 一致
+var packageNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+(?:\.[A-Za-z0-9_]+)*$`)
+
+// FileAccess 访问设备文件时使用的身份，Package 仅在 run-as 时使用
+type FileAccess struct {
+	Mode    string `json:"mode,omitempty"`
+	Package string `json:"package,omitempty"`
+}
+
+// IsDirect 是否为直接访问（可以使用 sync 协议）
+func (a FileAccess) IsDirect() bool {
+	return a.Mode == "" || a.Mode == AccessDirect
+}
+
+// String 用于界面显示
+func (a FileAccess) String() string {
+	switch a.Mode {
+	case AccessRunAs:
+		return "run-as " + a.Package
+	case AccessSu:
+		return "su"
+	}
+	return "直接访问"
+}
+
+// Wrap 将 shell 命令包装为以该身份执行的命令
+func (a FileAccess) Wrap(command string) string {
+	switch a.Mode {
+	case AccessRunAs:
+		return "run-as " + a.Package + " sh -c " + shellQuote(command)
+	case AccessSu:
+		return "su -c " + shellQuote(command)
+	}
+	return command
+}
+
+// AppPackageFromPath 从应用私有目录路径中解析包名，不是应用目录时返回空
+func AppPackageFromPath(p string) string {
+	if match := appDataPathPattern.FindStringSubmatch(p); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// 探测结果缓存，避免每次列目录都执行一次 run-as/su
+var (
+	accessCacheMu sync.Mutex
+	runAsCache    = make(map[string]bool) // deviceID/pkg → 是否可 run-as
+	rootCache     = make(map[string]bool) // deviceID → 是否可 su
+)
+
+// CanRunAs 应用是否可通过 run-as 访问（结果按设备缓存）
+func CanRunAs(deviceID string, packageName string) bool {
+	key := deviceID + "/" + packageName
+	accessCacheMu.Lock()
+	ok, cached := runAsCache[key]
+	accessCacheMu.Unlock()
+	if cached {
+		return ok
+	}
+	ok = CheckRunAs(deviceID, packageName) == nil
+	accessCacheMu.Lock()
+	runAsCache[key] = ok
+	accessCacheMu.Unlock()
+	return ok
+}
+
+// HasRoot 设备上 su 是否可用（结果按设备缓存）
+func HasRoot(deviceID string) bool {
+	accessCacheMu.Lock()
+	ok, cached := rootCache[deviceID]
+	accessCacheMu.Unlock()
+	if cached {
+		return ok
+	}
+	result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "su -c id"}, 10*time.Second)
+	ok = err == nil && strings.Contains(result.Output, "uid=0")
+	accessCacheMu.Lock()
+	rootCache[deviceID] = ok
+	accessCacheMu.Unlock()
+	log.Printf("root检测: deviceID=%s, root=%v", deviceID, ok)
+	return ok
+}
+
+// DetectFileAccess 为路径自动选择访问方式：应用私有目录优先使用 run-as，
+// 应用不可调试时在已 root 的设备上使用 su，其余路径直接访问
+func DetectFileAccess(deviceID string, p string) FileAccess {
+	pkg := AppPackageFromPath(p)
+	if pkg == "" && !strings.HasPrefix(p, "/data/") {
+		return FileAccess{Mode: AccessDirect}
+	}
+	if pkg != "" && CanRunAs(deviceID, pkg) {
+		return FileAccess{Mode: AccessRunAs, Package: pkg}
+	}
+	if HasRoot(deviceID) {
+		return FileAccess{Mode: AccessSu}
+	}
+	return FileAccess{Mode: AccessDirect}
+}
+
+// ListDirAs 以指定身份列出设备目录
+func ListDirAs(deviceID string, dir string, access FileAccess) ([]FileEntry, error) {
+	if access.IsDirect() {
+		return ListDir(deviceID, dir)
+	}
+	entries, err := listDirWithLs(deviceID, access.Wrap("ls -la "+shellQuote(dir)))
+	if err != nil {
+		return nil, err
+	}
+	SortFileEntries(entries)
+	return entries, nil
+}
+
+// MakeDirAs 以指定身份创建目录
+func MakeDirAs(deviceID string, dir string, access FileAccess) error {
+	return runFileCommand(deviceID, access.Wrap("mkdir -p "+shellQuote(dir)))
+}
+
+// RenamePathAs 以指定身份重命名
+func RenamePathAs(deviceID string, from string, to string, access FileAccess) error {
+	return runFileCommand(deviceID, access.Wrap("mv "+shellQuote(from)+" "+shellQuote(to)))
+}
+
+// RemovePathAs 以指定身份删除
+func RemovePathAs(deviceID string, target string, access FileAccess) error {
+	if target == "" || path.Clean(target) == "/" {
+		return fmt.Errorf("拒绝删除根目录")
+	}
+	return runFileCommand(deviceID, access.Wrap("rm -rf "+shellQuote(target)))
+}
+
+// ReadFileHeadAs 以指定身份读取文件的前 limit 字节
+func ReadFileHeadAs(deviceID string, remotePath string, limit int, access FileAccess) ([]byte, error) {
+	if access.IsDirect() {
+		return ReadFileHead(deviceID, remotePath, limit)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	w := &limitWriter{limit: limit}
+	command := access.Wrap("head -c " + strconv.Itoa(limit) + " " + shellQuote(remotePath))
+	if err := ExecOut(ctx, deviceID, command, w); err != nil && !errors.Is(err, errPreviewLimit) {
+		return nil, err
+	}
+	return w.data, nil
+}
+
+// PullAs 以指定身份将设备文件或目录拉取到本地目录 localDir 中。
+// 通过 exec-out tar 打包传输，保留目录结构与修改时间
+func PullAs(ctx context.Context, deviceID string, remotePath string, localDir string, access FileAccess, progress func(written int64)) error {
+	log.Printf("特权拉取: deviceID=%s, remote=%s, local=%s, access=%s", deviceID, remotePath, localDir, access)
+	remotePath = path.Clean(remotePath)
+	command := access.Wrap("tar -cf - -C " + shellQuote(path.Dir(remotePath)) + " " + shellQuote(path.Base(remotePath)))
+
+	reader, writer := io.Pipe()
+	extractErr := make(chan error, 1)
+	go func() {
+		err := extractTar(reader, localDir)
+		// 解包失败时让 exec-out 的写入端尽快出错退出
+		reader.CloseWithError(err)
+		extractErr <- err
+	}()
+
+	counter := &countingWriter{writer: writer, onProgress: progress}
+	err := ExecOut(ctx, deviceID, command, counter)
+	writer.CloseWithError(err)
+	if xerr := <-extractErr; xerr != nil && err == nil {
+		err = xerr
+	}
+	if err == nil && counter.written < 512 {
+		return fmt.Errorf("拉取失败: 设备返回的数据无效")
+	}
+	return err
+}
+
+// PushAs 以指定身份将本地文件或目录推送到设备目录 remoteDir 中。
+// 通过 exec-in tar 解包；su 方式下解包后将属主改为目标目录的属主并恢复 SELinux 标签，
+// 避免应用无法读取 root 创建的文件
+func PushAs(ctx context.Context, deviceID string, localPath string, remoteDir string, access FileAccess, progress func(written int64)) error {
+	log.Printf("特权推送: deviceID=%s, local=%s, remote=%s, access=%s", deviceID, localPath, remoteDir, access)
+	name := filepath.Base(localPath)
+	command := "tar -xf - -C " + shellQuote(remoteDir)
+	if access.Mode == AccessSu {
+		target := shellQuote(path.Join(remoteDir, name))
+		command += " && chown -R $(stat -c %u:%g " + shellQuote(remoteDir) + ") " + target +
+			" && (restorecon -R " + target + " 2>/dev/null; true)"
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		counter := &countingWriter{writer: writer, onProgress: progress}
+		writer.CloseWithError(writeTar(counter, localPath))
+	}()
+
+	output, err := ExecIn(ctx, deviceID, access.Wrap(command), reader)
+	reader.Close()
+	if output = strings.TrimSpace(output); output != "" {
+		// tar 的警告（如无法设置时间）不影响结果，只记录
+		log.Printf("特权推送输出: %s", output)
+	}
+	return err
+}
+
+// writeTar 将本地文件或目录写为 tar 流，条目名以 localPath 的最后一级为根
+func writeTar(w io.Writer, localPath string) error {
+	tw := tar.NewWriter(w)
+	base := filepath.Dir(localPath)
+	err := filepath.Walk(localPath, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(base, p)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		// 设备上的属主由解包的用户决定，不写入本机的用户名
+		header.Uname, header.Gname = "", ""
+		header.Uid, header.Gid = 0, 0
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		file, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, file)
+		file.Close()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// extractTar 将 tar 流解包到 dest，拒绝指向 dest 之外的条目
+func extractTar(r io.Reader, dest string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dest, filepath.FromSlash(header.Name))
+		if rel, err := filepath.Rel(dest, target); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("tar 条目路径非法: %s", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(file, tr)
+			file.Close()
+			if err != nil {
+				return err
+			}
+			os.Chtimes(target, header.ModTime, header.ModTime)
+		default:
+			// 符号链接、设备文件等不在本地创建
+			continue
+		}
+	}
+}
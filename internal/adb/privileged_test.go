@@ -0,0 +1,39 @@
+package adb
+
+import "testing"
+
+func TestAppPackageFromPath(t *testing.T) {
+	tests := map[string]string{
+		"/data/data/com.example.app":                    "com.example.app",
+		"/data/data/com.example.app/shared_prefs/a.xml": "com.example.app",
+		"/data/user/0/com.example.app/files":            "com.example.app",
+		"/data/user_de/10/com.example_1":                "com.example_1",
+		"/data/data":                                    "",
+		"/data/local/tmp":                               "",
+		"/sdcard/Android/data/com.example.app":          "",
+		"/data/data/com.example;rm -rf /":               "",
+	}
+	for p, want := range tests {
+		if got := AppPackageFromPath(p); got != want {
+			t.Errorf("AppPackageFromPath(%q) = %q, want %q", p, got, want)
+		}
+	}
+}
+
+func TestFileAccessWrap(t *testing.T) {
+	command := "ls -la '/data/data/com.x/it'\\''s'"
+	tests := []struct {
+		access FileAccess
+		want   string
+	}{
+		{FileAccess{}, command},
+		{FileAccess{Mode: AccessDirect}, command},
+		{FileAccess{Mode: AccessRunAs, Package: "com.x"}, `run-as com.x sh -c 'ls -la '\''/data/data/com.x/it'\''\'\'''\''s'\'''`},
+		{FileAccess{Mode: AccessSu}, `su -c 'ls -la '\''/data/data/com.x/it'\''\'\'''\''s'\'''`},
+	}
+	for _, tt := range tests {
+		if got := tt.access.Wrap(command); got != tt.want {
+			t.Errorf("%v.Wrap() =\n%s\nwant\n%s", tt.access, got, tt.want)
+		}
+	}
+}
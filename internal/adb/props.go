@@ -0,0 +1,172 @@
+package adb
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// GetProperties 一次性读取设备上全部系统属性（adb shell getprop）
+func GetProperties(deviceID string) (map[string]string, error) {
+	result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "getprop"}, 15*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	props := parseGetprop(result.Output)
+	log.Printf("读取设备属性: deviceID=%s, 数量=%d", deviceID, len(props))
+	return props, nil
+}
+
+// parseGetprop 解析 "[key]: [value]" 格式的 getprop 输出，值可能跨多行
+func parseGetprop(output string) map[string]string {
+	props := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var key string
+	var value strings.Builder
+	inValue := false
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if inValue {
+			// 多行值的后续行
+			value.WriteString("\n")
+			if strings.HasSuffix(line, "]") {
+				value.WriteString(strings.TrimSuffix(line, "]"))
+				props[key] = value.String()
+				inValue = false
+			} else {
+				value.WriteString(line)
+			}
+			continue
+		}
+
+		if !strings.HasPrefix(line, "[") {
+			continue
+		}
+		end := strings.Index(line, "]: [")
+		if end < 0 {
+			continue
+		}
+		key = line[1:end]
+		rest := line[end+len("]: ["):]
+		if strings.HasSuffix(rest, "]") {
+			props[key] = strings.TrimSuffix(rest, "]")
+			continue
+		}
+		value.Reset()
+		value.WriteString(rest)
+		inValue = true
+	}
+	return props
+}
+
+// 属性值的最大长度（PROP_VALUE_MAX 为 92，含结尾的 \0），ro.* 属性不受此限制
+const propValueMax = 91
+
+var propNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.\-:@]+$`)
+
+// PropertyWarning 返回修改该属性前需要提示的风险，空字符串表示可以直接修改
+func PropertyWarning(key string) string {
+	switch {
+	case strings.HasPrefix(key, "ro."):
+		return "ro.* 为只读属性，启动后不能再修改（setprop 会失败）"
+	case strings.HasPrefix(key, "ctl."):
+		return "ctl.* 会直接启动或停止系统服务"
+	case strings.HasPrefix(key, "sys.powerctl"):
+		return "sys.powerctl 会让设备立即关机或重启"
+	case strings.HasPrefix(key, "persist."):
+		return "persist.* 会写入存储，重启后仍然生效"
+	}
+	return ""
+}
+
+// ValidateProperty 检查属性名与值是否合法
+func ValidateProperty(key, value string) error {
+	if !propNamePattern.MatchString(key) {
+		return fmt.Errorf("属性名只能包含字母、数字及 _ . - : @")
+	}
+	if strings.HasPrefix(key, "ro.") {
+		return fmt.Errorf("%s 是只读属性，不能修改", key)
+	}
+	if len(value) > propValueMax {
+		return fmt.Errorf("属性值过长（%d 字节），最多 %d 字节", len(value), propValueMax)
+	}
+	if strings.ContainsAny(value, "\n\x00") {
+		return fmt.Errorf("属性值不能包含换行")
+	}
+	return nil
+}
+
+// SetProperty 通过 setprop 修改属性，并读回确认是否生效。
+// 多数属性受 SELinux 限制，shell 用户只能修改 debug.*、persist.log.* 等少数前缀
+func SetProperty(deviceID, key, value string) error {
+	if err := ValidateProperty(key, value); err != nil {
+		return err
+	}
+	log.Printf("设置属性: deviceID=%s, %s=%s", deviceID, key, value)
+	result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "setprop", key, shellQuote(value)}, 10*time.Second)
+	if err != nil {
+		if result != nil && strings.TrimSpace(result.ErrorOutput) != "" {
+			return fmt.Errorf("%s", strings.TrimSpace(result.ErrorOutput))
+		}
+		return err
+	}
+	if msg := strings.TrimSpace(result.Output + result.ErrorOutput); msg != "" {
+		return fmt.Errorf("%s", msg)
+	}
+
+	result, err = ExecuteADBCommandWithDevice(deviceID, []string{"shell", "getprop", key}, 10*time.Second)
+	if err != nil {
+		return err
+	}
+	if actual := strings.TrimRight(result.Output, "\r\n"); actual != value {
+		return fmt.Errorf("设置未生效（当前值为 %q），可能需要 root 或受 SELinux 限制", actual)
+	}
+	return nil
+}
+
+// PropertyGroup 属性所属的分组：取前两段（ro.build、persist.sys），不足三段时取第一段
+func PropertyGroup(key string) string {
+	parts := strings.SplitN(key, ".", 3)
+	if len(parts) >= 3 {
+		return parts[0] + "." + parts[1]
+	}
+	return parts[0]
+}
+
+// PropertyDiff 两台设备间一个属性的差异，某一侧不存在时 Missing 标记对应位置
+type PropertyDiff struct {
+	Key          string
+	Left, Right  string
+	MissingLeft  bool
+	MissingRight bool
+}
+
+// DiffProperties 比较两组属性，返回值不同或只存在于一侧的属性（按名称排序）
+func DiffProperties(left, right map[string]string) []PropertyDiff {
+	keys := make(map[string]bool)
+	for k := range left {
+		keys[k] = true
+	}
+	for k := range right {
+		keys[k] = true
+	}
+	var diffs []PropertyDiff
+	for k := range keys {
+		l, okLeft := left[k]
+		r, okRight := right[k]
+		if okLeft && okRight && l == r {
+			continue
+		}
+		diffs = append(diffs, PropertyDiff{Key: k, Left: l, Right: r, MissingLeft: !okLeft, MissingRight: !okRight})
+	}
+	slices.SortFunc(diffs, func(a, b PropertyDiff) int { return strings.Compare(a.Key, b.Key) })
+	return diffs
+}
@@ -0,0 +1,79 @@
+package adb
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGetprop(t *testing.T) {
+	output := "[ro.build.version.sdk]: [34]\r\n" +
+		"[persist.sys.locale]: [zh-CN]\r\n" +
+		"[empty.prop]: []\r\n" +
+		"[multi.line]: [first\r\n" +
+		"second]\r\n" +
+		"garbage line\r\n" +
+		"[ro.product.model]: [Pixel 8]\r\n"
+	want := map[string]string{
+		"ro.build.version.sdk": "34",
+		"persist.sys.locale":   "zh-CN",
+		"empty.prop":           "",
+		"multi.line":           "first\nsecond",
+		"ro.product.model":     "Pixel 8",
+	}
+	if got := parseGetprop(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %v\nwant %v", got, want)
+	}
+}
+
+func TestDiffProperties(t *testing.T) {
+	left := map[string]string{"a": "1", "b": "2", "c": "3", "e": ""}
+	right := map[string]string{"a": "1", "b": "20", "d": "4", "e": ""}
+	want := []PropertyDiff{
+		{Key: "b", Left: "2", Right: "20"},
+		{Key: "c", Left: "3", MissingRight: true},
+		{Key: "d", Right: "4", MissingLeft: true},
+	}
+	if got := DiffProperties(left, right); !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+	if got := DiffProperties(left, left); got != nil {
+		t.Errorf("相同属性应无差异: %+v", got)
+	}
+}
+
+func TestPropertyGroup(t *testing.T) {
+	tests := map[string]string{
+		"ro.build.version.sdk": "ro.build",
+		"persist.sys.locale":   "persist.sys",
+		"debug.hwui":           "debug",
+		"dalvik":               "dalvik",
+	}
+	for key, want := range tests {
+		if got := PropertyGroup(key); got != want {
+			t.Errorf("PropertyGroup(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestValidateProperty(t *testing.T) {
+	tests := []struct {
+		key, value string
+		wantErr    bool
+	}{
+		{"debug.hwui.overdraw", "show", false},
+		{"persist.log.tag", "", false},
+		{"vendor.audio@1.0", "x", false},
+		{"ro.debuggable", "1", true},
+		{"bad key", "1", true},
+		{"debug.$(reboot)", "1", true},
+		{"debug.x", strings.Repeat("a", propValueMax), false},
+		{"debug.x", strings.Repeat("a", propValueMax+1), true},
+		{"debug.x", "a\nb", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateProperty(tt.key, tt.value); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateProperty(%q, %q) = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+		}
+	}
+}
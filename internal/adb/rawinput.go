@@ -0,0 +1,390 @@
+package adb
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RawInputExtension 原始输入事件录制文件的扩展名
+const RawInputExtension = ".getevent"
+
+// rawReplayScript 回放时推送到设备上的脚本路径
+const rawReplayScript = "/data/local/tmp/yikong_sendevent.sh"
+
+// 每条 sendevent 都要 fork/exec 一个进程，耗时通常为数毫秒，生成脚本时从等待时间中扣除。
+// 回放前会在设备上实测，测量失败时使用 defaultSendeventCost
+const (
+	defaultSendeventCost = 5 * time.Millisecond
+	sendeventProbeCount  = 20
+)
+
+// 事件类型与常用事件码（linux/input-event-codes.h），用于将 getevent -l 的名称换回数值
+var (
+	eventTypeCodes = map[string]uint16{
+		"EV_SYN": 0x00, "EV_KEY": 0x01, "EV_REL": 0x02, "EV_ABS": 0x03, "EV_MSC": 0x04,
+		"EV_SW": 0x05, "EV_LED": 0x11, "EV_SND": 0x12, "EV_REP": 0x14, "EV_FF": 0x15, "EV_PWR": 0x16,
+	}
+	eventCodes = map[string]map[string]uint16{
+		"EV_SYN": {"SYN_REPORT": 0, "SYN_CONFIG": 1, "SYN_MT_REPORT": 2, "SYN_DROPPED": 3},
+		"EV_KEY": {
+			"BTN_LEFT": 0x110, "BTN_RIGHT": 0x111, "BTN_MIDDLE": 0x112,
+			"BTN_TOOL_PEN": 0x140, "BTN_TOOL_RUBBER": 0x141, "BTN_TOOL_FINGER": 0x145,
+			"BTN_TOUCH": 0x14a, "BTN_STYLUS": 0x14b, "BTN_STYLUS2": 0x14c,
+			"BTN_TOOL_DOUBLETAP": 0x14d, "BTN_TOOL_TRIPLETAP": 0x14e, "BTN_TOOL_QUADTAP": 0x14f,
+			"KEY_HOME": 102, "KEY_MUTE": 113, "KEY_VOLUMEDOWN": 114, "KEY_VOLUMEUP": 115, "KEY_POWER": 116,
+			"KEY_MENU": 139, "KEY_WAKEUP": 143, "KEY_BACK": 158, "KEY_PLAYPAUSE": 164, "KEY_HOMEPAGE": 172,
+			"KEY_CAMERA": 212, "KEY_SEARCH": 217, "KEY_APPSELECT": 0x244, "KEY_ASSISTANT": 0x247,
+		},
+		"EV_REL": {"REL_X": 0x00, "REL_Y": 0x01, "REL_HWHEEL": 0x06, "REL_WHEEL": 0x08},
+		"EV_ABS": {
+			"ABS_X": 0x00, "ABS_Y": 0x01, "ABS_Z": 0x02, "ABS_PRESSURE": 0x18, "ABS_DISTANCE": 0x19,
+			"ABS_TILT_X": 0x1a, "ABS_TILT_Y": 0x1b,
+			"ABS_MT_SLOT": 0x2f, "ABS_MT_TOUCH_MAJOR": 0x30, "ABS_MT_TOUCH_MINOR": 0x31,
+			"ABS_MT_WIDTH_MAJOR": 0x32, "ABS_MT_WIDTH_MINOR": 0x33, "ABS_MT_ORIENTATION": 0x34,
+			"ABS_MT_POSITION_X": 0x35, "ABS_MT_POSITION_Y": 0x36, "ABS_MT_TOOL_TYPE": 0x37,
+			"ABS_MT_BLOB_ID": 0x38, "ABS_MT_TRACKING_ID": 0x39, "ABS_MT_PRESSURE": 0x3a,
+			"ABS_MT_DISTANCE": 0x3b, "ABS_MT_TOOL_X": 0x3c, "ABS_MT_TOOL_Y": 0x3d,
+		},
+		"EV_MSC": {"MSC_SERIAL": 0, "MSC_PULSELED": 1, "MSC_GESTURE": 2, "MSC_RAW": 3, "MSC_SCAN": 4, "MSC_TIMESTAMP": 5},
+	}
+)
+
+// Numeric 返回事件类型与事件码的数值，供 sendevent 使用。
+// getevent -l 对不认识的类型和事件码同样输出十六进制数值
+func (e InputEvent) Numeric() (uint16, uint16, error) {
+	typ, ok := eventTypeCodes[e.Type]
+	if !ok {
+		value, err := strconv.ParseUint(e.Type, 16, 16)
+		if err != nil {
+			return 0, 0, fmt.Errorf("未知的事件类型: %s", e.Type)
+		}
+		typ = uint16(value)
+	}
+	code, ok := eventCodes[e.Type][e.Code]
+	if !ok {
+		value, err := strconv.ParseUint(e.Code, 16, 16)
+		if err != nil {
+			return 0, 0, fmt.Errorf("未知的事件码: %s %s", e.Type, e.Code)
+		}
+		code = uint16(value)
+	}
+	return typ, code, nil
+}
+
+// RawRecording 原始输入事件录制，按时间顺序保存所有输入设备的事件
+type RawRecording struct {
+	Devices map[string]string // 设备路径 → 设备名称，回放到其它设备时按名称匹配
+	Events  []InputEvent
+}
+
+// Duration 第一个事件到最后一个事件的时长
+func (r *RawRecording) Duration() time.Duration {
+	if len(r.Events) == 0 {
+		return 0
+	}
+	return r.Events[len(r.Events)-1].Time - r.Events[0].Time
+}
+
+// ByDevice 按输入设备分组的事件
+func (r *RawRecording) ByDevice() map[string][]InputEvent {
+	groups := make(map[string][]InputEvent)
+	for _, e := range r.Events {
+		groups[e.Device] = append(groups[e.Device], e)
+	}
+	return groups
+}
+
+// Summary 简要描述录制内容，如“/dev/input/event2 (touch): 812 个事件”
+func (r *RawRecording) Summary() string {
+	groups := r.ByDevice()
+	paths := make([]string, 0, len(groups))
+	for path := range groups {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	var lines []string
+	for _, path := range paths {
+		lines = append(lines, fmt.Sprintf("%s (%s): %d 个事件", path, r.Devices[path], len(groups[path])))
+	}
+	return strings.Join(lines, "\n")
+}
+
+var rawDeviceHeaderPattern = regexp.MustCompile(`^#\s*device\s+(\S+)\s+"(.*)"`)
+
+// FormatRawRecording 以 getevent -lt 的格式输出录制内容，文件头以注释记录设备名称
+func FormatRawRecording(r *RawRecording) string {
+	var sb strings.Builder
+	sb.WriteString("# yikong getevent 录制\n")
+	paths := make([]string, 0, len(r.Devices))
+	for path := range r.Devices {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	for _, path := range paths {
+		fmt.Fprintf(&sb, "# device %s %q\n", path, r.Devices[path])
+	}
+	for _, e := range r.Events {
+		value := fmt.Sprintf("%08x", uint32(e.Value))
+		if e.Type == "EV_KEY" {
+			for label, v := range geteventValueLabels {
+				if v == e.Value {
+					value = label
+				}
+			}
+		}
+		fmt.Fprintf(&sb, "[%8d.%06d] %s: %-12s %-20s %s\n",
+			int64(e.Time/time.Second), int64(e.Time%time.Second/time.Microsecond), e.Device, e.Type, e.Code, value)
+	}
+	return sb.String()
+}
+
+// ParseRawRecording 解析 FormatRawRecording 的输出，也可直接解析 getevent -lt 的原始输出
+func ParseRawRecording(text string) (*RawRecording, error) {
+	r := &RawRecording{Devices: make(map[string]string)}
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			if match := rawDeviceHeaderPattern.FindStringSubmatch(line); match != nil {
+				r.Devices[match[1]] = match[2]
+			}
+			continue
+		}
+		event, ok := ParseGeteventLine(line)
+		if !ok {
+			return nil, fmt.Errorf("第 %d 行无法解析: %s", lineNo, line)
+		}
+		if _, _, err := event.Numeric(); err != nil {
+			return nil, fmt.Errorf("第 %d 行: %w", lineNo, err)
+		}
+		r.Events = append(r.Events, event)
+	}
+	if len(r.Events) == 0 {
+		return nil, fmt.Errorf("没有任何输入事件")
+	}
+	return r, nil
+}
+
+// LoadRawRecording 从文件读取原始输入录制
+func LoadRawRecording(path string) (*RawRecording, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRawRecording(string(data))
+}
+
+// SaveRawRecording 将原始输入录制保存为文本文件
+func SaveRawRecording(path string, r *RawRecording) error {
+	return os.WriteFile(path, []byte(FormatRawRecording(r)), 0644)
+}
+
+// RecordRawInput 通过 getevent -lt 录制原始输入事件，直到 ctx 取消后返回录制结果。
+// touchOnly 为 true 时只保留触摸屏的事件；onEvent 在收到每个事件时回调（可为 nil）
+func RecordRawInput(ctx context.Context, deviceID string, touchOnly bool, onEvent func(InputEvent)) (*RawRecording, error) {
+	devices, err := GetInputDevices(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	r := &RawRecording{Devices: make(map[string]string)}
+	for _, d := range devices {
+		if !touchOnly || d.IsTouchscreen() {
+			r.Devices[d.Path] = d.Name
+		}
+	}
+	if len(r.Devices) == 0 {
+		return nil, fmt.Errorf("未找到触摸屏输入设备")
+	}
+
+	err = StreamGetevent(ctx, deviceID, true, func(event InputEvent) {
+		if _, ok := r.Devices[event.Device]; !ok {
+			return
+		}
+		r.Events = append(r.Events, event)
+		if onEvent != nil {
+			onEvent(event)
+		}
+	})
+	if err != nil {
+		return r, err
+	}
+	// 只保留实际产生了事件的设备
+	groups := r.ByDevice()
+	for path := range r.Devices {
+		if _, ok := groups[path]; !ok {
+			delete(r.Devices, path)
+		}
+	}
+	log.Printf("原始输入录制结束: deviceID=%s, %d 个事件, 时长 %v", deviceID, len(r.Events), r.Duration())
+	return r, nil
+}
+
+// mapInputDevices 将录制中的设备路径映射到目标设备上同名的输入设备，
+// 名称找不到时沿用原路径（同一台设备或同型号设备）
+func mapInputDevices(r *RawRecording, targets []InputDevice) map[string]string {
+	mapping := make(map[string]string)
+	for path := range r.ByDevice() {
+		mapping[path] = path
+		name := r.Devices[path]
+		if name == "" {
+			continue
+		}
+		for _, d := range targets {
+			if d.Name == name {
+				mapping[path] = d.Path
+				break
+			}
+		}
+	}
+	return mapping
+}
+
+// BuildSendeventScript 将录制内容转换为按原始时间间隔执行的 sendevent 脚本。
+// speed 为速度倍数；cost 为每条 sendevent 在设备上的耗时（见 MeasureSendeventCost），
+// 相邻事件间隔小于 sendevent 自身耗时的部分不再额外等待
+func BuildSendeventScript(r *RawRecording, mapping map[string]string, speed float64, cost time.Duration) (string, error) {
+	if speed <= 0 {
+		speed = 1
+	}
+	if cost <= 0 {
+		cost = defaultSendeventCost
+	}
+	var sb strings.Builder
+	sb.WriteString("#!/system/bin/sh\n")
+	var last time.Duration
+	var debt time.Duration // 自上次 sleep 以来 sendevent 已占用的时间
+	for i, e := range r.Events {
+		typ, code, err := e.Numeric()
+		if err != nil {
+			return "", err
+		}
+		if i > 0 {
+			gap := time.Duration(float64(e.Time-last)/speed) - debt
+			if gap >= 2*time.Millisecond {
+				fmt.Fprintf(&sb, "sleep %.3f\n", gap.Seconds())
+				debt = 0
+			} else if gap > 0 {
+				debt = 0
+			} else {
+				debt = -gap
+			}
+		}
+		last = e.Time
+		fmt.Fprintf(&sb, "sendevent %s %d %d %d\n", mapping[e.Device], typ, code, e.Value)
+		debt += cost
+	}
+	return sb.String(), nil
+}
+
+// MeasureSendeventCost 在设备上连续执行若干次不带参数的 sendevent（只输出用法，不写入事件），
+// 返回平均每条命令的耗时
+func MeasureSendeventCost(deviceID string) (time.Duration, error) {
+	script := fmt.Sprintf("s=$(date +%%s%%N); i=0; while [ $i -lt %d ]; do sendevent >/dev/null 2>&1; i=$((i+1)); done; e=$(date +%%s%%N); echo $((e-s))",
+		sendeventProbeCount)
+	result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", script}, 30*time.Second)
+	if err != nil {
+		return 0, err
+	}
+	return parseSendeventCost(result.Output, sendeventProbeCount)
+}
+
+// parseSendeventCost 解析测量脚本输出的总纳秒数并求平均值
+func parseSendeventCost(output string, count int) (time.Duration, error) {
+	// 不支持 %N 的 date 会原样输出，算术展开失败后没有输出或输出非数字
+	total, err := strconv.ParseInt(strings.TrimSpace(output), 10, 64)
+	if err != nil || total <= 0 || count <= 0 {
+		return 0, fmt.Errorf("无法解析 sendevent 耗时: %q", strings.TrimSpace(output))
+	}
+	return time.Duration(total) / time.Duration(count), nil
+}
+
+// releaseTouches 抬起录制中用到的所有触点，回放中途停止时避免手指一直处于按下状态
+func releaseTouches(deviceID string, r *RawRecording, mapping map[string]string) {
+	for path, events := range r.ByDevice() {
+		slots := map[int32]bool{0: true}
+		multiTouch, btnTouch := false, false
+		for _, e := range events {
+			switch e.Code {
+			case "ABS_MT_SLOT":
+				slots[e.Value] = true
+			case "ABS_MT_TRACKING_ID":
+				multiTouch = true
+			case "BTN_TOUCH":
+				btnTouch = true
+			}
+		}
+		if !multiTouch && !btnTouch {
+			continue
+		}
+		target := mapping[path]
+		var commands []string
+		if multiTouch {
+			for slot := range slots {
+				commands = append(commands,
+					fmt.Sprintf("sendevent %s 3 47 %d", target, slot),
+					fmt.Sprintf("sendevent %s 3 57 -1", target))
+			}
+		}
+		if btnTouch {
+			commands = append(commands, fmt.Sprintf("sendevent %s 1 330 0", target))
+		}
+		commands = append(commands, fmt.Sprintf("sendevent %s 0 0 0", target))
+		if err := runShellCommand(deviceID, strings.Join(commands, "; ")); err != nil {
+			log.Printf("释放触点失败: deviceID=%s, 错误: %v", deviceID, err)
+		}
+	}
+}
+
+// ReplayRawRecording 在设备上通过 sendevent 回放原始输入事件，可还原多点触控与精确时序。
+// 坐标为触摸屏原始坐标，不做换算，适用于录制设备本身或同型号设备；
+// 写入 /dev/input 需要 shell 用户属于 input 组（绝大多数设备满足）或 root
+func ReplayRawRecording(ctx context.Context, deviceID string, r *RawRecording, speed float64) error {
+	targets, err := GetInputDevices(deviceID)
+	if err != nil {
+		return err
+	}
+	mapping := mapInputDevices(r, targets)
+	cost, err := MeasureSendeventCost(deviceID)
+	if err != nil {
+		log.Printf("测量 sendevent 耗时失败，使用默认值 %v: deviceID=%s, 错误: %v", defaultSendeventCost, deviceID, err)
+		cost = defaultSendeventCost
+	}
+	script, err := BuildSendeventScript(r, mapping, speed, cost)
+	if err != nil {
+		return err
+	}
+	if _, err := ExecIn(ctx, deviceID, "cat > "+rawReplayScript, strings.NewReader(script)); err != nil {
+		return fmt.Errorf("推送回放脚本失败: %w", err)
+	}
+	defer runShellCommand(deviceID, "rm", "-f", rawReplayScript)
+
+	log.Printf("回放原始输入: deviceID=%s, %d 个事件, 速度 %.2gx, 每条 sendevent 约 %v", deviceID, len(r.Events), speed, cost)
+	cmd := exec.CommandContext(ctx, "adb", "-s", deviceID, "shell", "sh "+rawReplayScript)
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		// 结束本地 adb 进程不一定能结束设备上的脚本
+		runShellCommand(deviceID, "pkill -f "+rawReplayScript)
+		releaseTouches(deviceID, r, mapping)
+		return ctx.Err()
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	// sendevent 失败（如没有权限）时脚本本身不会返回错误
+	if msg := strings.TrimSpace(string(output)); msg != "" {
+		return fmt.Errorf("sendevent 执行出错: %s", msg)
+	}
+	return nil
+}
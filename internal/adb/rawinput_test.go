@@ -0,0 +1,71 @@
+package adb
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildSendeventScript(t *testing.T) {
+	r := &RawRecording{Events: []InputEvent{
+		{Time: 0, Device: "/dev/input/event1", Type: "EV_ABS", Code: "ABS_MT_POSITION_X", Value: 100},
+		{Time: time.Millisecond, Device: "/dev/input/event1", Type: "EV_ABS", Code: "ABS_MT_POSITION_Y", Value: 200},
+		{Time: 2 * time.Millisecond, Device: "/dev/input/event1", Type: "EV_SYN", Code: "SYN_REPORT", Value: 0},
+		{Time: 100 * time.Millisecond, Device: "/dev/input/event1", Type: "EV_KEY", Code: "BTN_TOUCH", Value: 0},
+	}}
+	mapping := map[string]string{"/dev/input/event1": "/dev/input/event3"}
+	events := "sendevent /dev/input/event3 3 53 100\n" +
+		"sendevent /dev/input/event3 3 54 200\n" +
+		"sendevent /dev/input/event3 0 0 0\n"
+
+	tests := []struct {
+		name  string
+		speed float64
+		cost  time.Duration
+		want  string
+	}{
+		// 前三条命令共占用 15ms，超出录制间隔的部分从之后的等待中扣除
+		{"实测耗时", 1, 5 * time.Millisecond, events + "sleep 0.085\n"},
+		{"未测量时使用默认值", 1, 0, events + "sleep 0.085\n"},
+		{"两倍速", 2, 5 * time.Millisecond, events + "sleep 0.035\n"},
+		{"耗时更高的设备", 1, 20 * time.Millisecond, events + "sleep 0.040\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildSendeventScript(r, mapping, tt.speed, tt.cost)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := "#!/system/bin/sh\n" + tt.want + "sendevent /dev/input/event3 1 330 0\n"
+			if got != want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+
+	bad := &RawRecording{Events: []InputEvent{{Type: "EV_BOGUS", Code: "X"}}}
+	if _, err := BuildSendeventScript(bad, nil, 1, 0); err == nil {
+		t.Error("未知事件类型应返回错误")
+	}
+}
+
+func TestParseSendeventCost(t *testing.T) {
+	tests := []struct {
+		output  string
+		count   int
+		want    time.Duration
+		wantErr bool
+	}{
+		{"80000000\n", 20, 4 * time.Millisecond, false},
+		{"  123456789\r\n", 1, 123456789, false},
+		{"", 20, 0, true},
+		{"1700000000N", 20, 0, true},
+		{"0", 20, 0, true},
+		{"-5", 20, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSendeventCost(tt.output, tt.count)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseSendeventCost(%q, %d) = %v, %v; want %v, wantErr %v", tt.output, tt.count, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package adb
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// screenrecord 单次录制的最长时间，超过后需要开始新的分段
+const maxRecordSegment = 180 * time.Second
+
+// recordRemoteDir 录屏分段在设备上的临时目录
+const recordRemoteDir = "/sdcard"
+
+// RecordOptions 录屏选项，零值表示使用 screenrecord 的默认值
+type RecordOptions struct {
+	Size      string        // 分辨率，如 1280x720
+	BitRate   int           // 码率（bps）
+	TimeLimit time.Duration // 总时长上限，0 表示直到手动停止
+}
+
+// args 转换为 screenrecord 参数（不含时长与输出路径）
+func (o RecordOptions) args() []string {
+	var args []string
+	if o.Size != "" {
+		args = append(args, "--size", o.Size)
+	}
+	if o.BitRate > 0 {
+		args = append(args, "--bit-rate", strconv.Itoa(o.BitRate))
+	}
+	return args
+}
+
+// ScreenRecorder 一次进行中的录屏。screenrecord 单次最长 3 分钟，
+// 到时后自动开始下一个分段，直到达到总时长或被停止
+type ScreenRecorder struct {
+	DeviceID string
+	Options  RecordOptions
+
+	tag       string // 分段文件名前缀，用于停止时精确匹配进程
+	started   time.Time
+	onSegment func(index int, remotePath string)
+
+	mu       sync.Mutex
+	cmd      *exec.Cmd
+	stopping bool
+	segments []string
+	err      error
+	done     chan struct{}
+}
+
+// StartScreenRecord 开始录屏，onSegment 在每个分段开始时回调
+func StartScreenRecord(deviceID string, opts RecordOptions, onSegment func(index int, remotePath string)) (*ScreenRecorder, error) {
+	log.Printf("开始录屏: deviceID=%s, opts=%+v", deviceID, opts)
+	if opts.Size != "" {
+		if w, h, ok := strings.Cut(opts.Size, "x"); !ok || !isDigits(w) || !isDigits(h) {
+			return nil, fmt.Errorf("分辨率格式应为 宽x高，如 1280x720: %s", opts.Size)
+		}
+	}
+	r := &ScreenRecorder{
+		DeviceID:  deviceID,
+		Options:   opts,
+		tag:       "yikong_rec_" + time.Now().Format("20060102_150405"),
+		started:   time.Now(),
+		onSegment: onSegment,
+		done:      make(chan struct{}),
+	}
+	go r.run()
+	return r, nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *ScreenRecorder) run() {
+	defer close(r.done)
+	for index := 0; ; index++ {
+		limit := maxRecordSegment
+		if r.Options.TimeLimit > 0 {
+			remaining := r.Options.TimeLimit - time.Since(r.started)
+			if remaining < time.Second {
+				return
+			}
+			limit = min(limit, remaining)
+		}
+
+		remotePath := path.Join(recordRemoteDir, fmt.Sprintf("%s_%03d.mp4", r.tag, index))
+		args := append(r.Options.args(), "--time-limit", strconv.Itoa(int(limit.Round(time.Second).Seconds())), remotePath)
+		cmd := exec.Command("adb", "-s", r.DeviceID, "shell", "screenrecord "+strings.Join(args, " "))
+
+		r.mu.Lock()
+		if r.stopping {
+			r.mu.Unlock()
+			return
+		}
+		r.cmd = cmd
+		r.segments = append(r.segments, remotePath)
+		r.mu.Unlock()
+
+		if r.onSegment != nil {
+			r.onSegment(index, remotePath)
+		}
+		segmentStart := time.Now()
+		output, err := cmd.CombinedOutput()
+
+		r.mu.Lock()
+		stopping := r.stopping
+		r.cmd = nil
+		r.mu.Unlock()
+		if stopping {
+			return
+		}
+		// screenrecord 参数错误或设备不支持时会立即退出
+		if err != nil || time.Since(segmentStart) < limit/2 {
+			msg := strings.TrimSpace(string(output))
+			if msg == "" && err != nil {
+				msg = err.Error()
+			}
+			if msg == "" {
+				msg = "screenrecord 提前退出"
+			}
+			r.mu.Lock()
+			r.err = fmt.Errorf("录屏中断: %s", msg)
+			r.mu.Unlock()
+			log.Printf("录屏中断: deviceID=%s, segment=%d, output=%s", r.DeviceID, index, msg)
+			return
+		}
+	}
+}
+
+// Stop 向设备上的 screenrecord 发送 SIGINT，使其写完文件尾后退出，并等待录制结束
+func (r *ScreenRecorder) Stop() error {
+	r.mu.Lock()
+	if r.stopping {
+		r.mu.Unlock()
+		<-r.done
+		return nil
+	}
+	r.stopping = true
+	r.mu.Unlock()
+
+	log.Printf("停止录屏: deviceID=%s", r.DeviceID)
+	// 只结束本次录制的进程；旧版 toybox 没有 pkill -f 时退回 killall
+	command := "pkill -INT -f " + r.tag + " || killall -INT screenrecord"
+	if _, err := ExecuteADBCommandWithDevice(r.DeviceID, []string{"shell", command}, 10*time.Second); err != nil {
+		log.Printf("发送SIGINT失败: %v", err)
+	}
+
+	select {
+	case <-r.done:
+		return nil
+	case <-time.After(10 * time.Second):
+	}
+	// screenrecord 没有响应，结束本地的 adb 进程
+	r.mu.Lock()
+	if r.cmd != nil && r.cmd.Process != nil {
+		r.cmd.Process.Kill()
+	}
+	r.mu.Unlock()
+	<-r.done
+	return fmt.Errorf("screenrecord 未响应停止信号，最后一个分段可能不完整")
+}
+
+// Done 录制结束（到达时长、被停止或出错）时关闭
+func (r *ScreenRecorder) Done() <-chan struct{} {
+	return r.done
+}
+
+// Err 录制过程中的错误
+func (r *ScreenRecorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Elapsed 已录制时长
+func (r *ScreenRecorder) Elapsed() time.Duration {
+	return time.Since(r.started)
+}
+
+// Segments 设备上已生成的分段文件
+func (r *ScreenRecorder) Segments() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.segments...)
+}
+
+// FetchRecording 将录屏分段拉取到 localDir，文件名以 baseName 开头；
+// deleteRemote 为 true 时拉取成功后删除设备上的分段。返回本地文件路径
+func FetchRecording(ctx context.Context, deviceID string, segments []string, localDir string, baseName string, deleteRemote bool, progress SyncProgress) ([]string, error) {
+	if err := os.MkdirAll(localDir, 0755); err != nil {
+		return nil, err
+	}
+	var files []string
+	for i, remotePath := range segments {
+		name := baseName + ".mp4"
+		if len(segments) > 1 {
+			name = fmt.Sprintf("%s_part%03d.mp4", baseName, i+1)
+		}
+		localPath := filepath.Join(localDir, name)
+		if err := Pull(ctx, deviceID, remotePath, localPath, progress); err != nil {
+			return files, fmt.Errorf("拉取 %s 失败: %w", remotePath, err)
+		}
+		files = append(files, localPath)
+	}
+	if deleteRemote {
+		var quoted []string
+		for _, remotePath := range segments {
+			quoted = append(quoted, shellQuote(remotePath))
+		}
+		if err := runFileCommand(deviceID, "rm -f "+strings.Join(quoted, " ")); err != nil {
+			return files, fmt.Errorf("删除设备上的录屏文件失败: %w", err)
+		}
+	}
+	return files, nil
+}
+
+// ConcatVideos 使用 ffmpeg 的 concat 分离器无损拼接分段（需要 ffmpeg 在 PATH 中）
+func ConcatVideos(ctx context.Context, segments []string, output string) error {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return fmt.Errorf("拼接分段需要 ffmpeg，未在 PATH 中找到")
+	}
+	listFile, err := os.CreateTemp("", "yikong_concat_*.txt")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(listFile.Name())
+	for _, segment := range segments {
+		abs, err := filepath.Abs(segment)
+		if err != nil {
+			listFile.Close()
+			return err
+		}
+		fmt.Fprintf(listFile, "file '%s'\n", strings.ReplaceAll(filepath.ToSlash(abs), "'", `'\''`))
+	}
+	listFile.Close()
+
+	log.Printf("拼接录屏: %d 个分段 -> %s", len(segments), output)
+	cmd := exec.CommandContext(ctx, ffmpeg, "-y", "-loglevel", "error", "-f", "concat", "-safe", "0", "-i", listFile.Name(), "-c", "copy", output)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("ffmpeg 拼接失败: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
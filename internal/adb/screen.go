@@ -0,0 +1,58 @@
+package adb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// pngSignature PNG 文件头
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// DefaultScreenshotTemplate 截图文件名模板的默认值
+const DefaultScreenshotTemplate = "{model}_{serial}_{time}.png"
+
+// CaptureScreenshot 通过 exec-out screencap -p 将截图直接读入内存，不在设备上生成文件
+func CaptureScreenshot(ctx context.Context, deviceID string) ([]byte, error) {
+	log.Printf("截图: deviceID=%s", deviceID)
+	var buf bytes.Buffer
+	if err := ExecOut(ctx, deviceID, "screencap -p", &buf); err != nil {
+		return nil, err
+	}
+	data := buf.Bytes()
+	// 个别旧设备即使通过 exec-out 也会在输出前附加警告文本，跳到 PNG 文件头
+	if index := bytes.Index(data, pngSignature); index > 0 {
+		data = data[index:]
+	}
+	if !bytes.HasPrefix(data, pngSignature) {
+		preview := strings.TrimSpace(string(data[:min(len(data), 200)]))
+		return nil, fmt.Errorf("截图失败，设备返回的不是PNG数据: %s", preview)
+	}
+	return data, nil
+}
+
+// ExpandFileTemplate 展开文件名模板，支持 {serial}、{model}、{date}、{time}、{timestamp}，
+// 并替换文件名中不允许的字符（如无线调试序列号中的冒号）
+func ExpandFileTemplate(template string, serial string, model string, t time.Time) string {
+	replacer := strings.NewReplacer(
+		"{serial}", serial,
+		"{model}", model,
+		"{date}", t.Format("20060102"),
+		"{time}", t.Format("20060102_150405"),
+		"{timestamp}", fmt.Sprint(t.UnixMilli()),
+	)
+	name := replacer.Replace(template)
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		if r < 0x20 {
+			return '_'
+		}
+		return r
+	}, name)
+}
@@ -0,0 +1,330 @@
+package adb
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// settings 的三个命名空间
+const (
+	SettingsSystem = "system"
+	SettingsSecure = "secure"
+	SettingsGlobal = "global"
+)
+
+// SettingsNamespaces 全部命名空间
+var SettingsNamespaces = []string{SettingsSystem, SettingsSecure, SettingsGlobal}
+
+// SettingsResetModes settings reset 支持的重置模式及说明
+var SettingsResetModes = map[string]string{
+	"untrusted_defaults": "将非系统应用修改的设置恢复为默认值",
+	"untrusted_clear":    "删除非系统应用添加的设置，其余恢复为默认值",
+	"trusted_defaults":   "将全部设置恢复为系统默认值",
+}
+
+var settingKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.\-:]+$`)
+
+// SettingValue 一项设置；Delete 为 true 时表示删除该项
+type SettingValue struct {
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+	Value     string `json:"value,omitempty"`
+	Delete    bool   `json:"delete,omitempty"`
+}
+
+// String 以 "namespace key value" 的形式显示
+func (v SettingValue) String() string {
+	if v.Delete {
+		return fmt.Sprintf("delete %s %s", v.Namespace, v.Key)
+	}
+	return fmt.Sprintf("%s %s %s", v.Namespace, v.Key, v.Value)
+}
+
+// SettingsProfile 一组命名的设置，可一次性应用到设备
+type SettingsProfile struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Settings    []SettingValue `json:"settings"`
+	Builtin     bool           `json:"-"`
+}
+
+// BuiltinSettingsProfiles 内置的常用设置组合
+var BuiltinSettingsProfiles = []SettingsProfile{
+	{
+		Name:        "关闭动画（UI 测试）",
+		Description: "将窗口、过渡和动画时长缩放设为 0，避免动画导致 UI 测试不稳定",
+		Settings: []SettingValue{
+			{Namespace: SettingsGlobal, Key: "window_animation_scale", Value: "0"},
+			{Namespace: SettingsGlobal, Key: "transition_animation_scale", Value: "0"},
+			{Namespace: SettingsGlobal, Key: "animator_duration_scale", Value: "0"},
+		},
+		Builtin: true,
+	},
+	{
+		Name:        "恢复动画",
+		Description: "将动画缩放恢复为 1x",
+		Settings: []SettingValue{
+			{Namespace: SettingsGlobal, Key: "window_animation_scale", Value: "1"},
+			{Namespace: SettingsGlobal, Key: "transition_animation_scale", Value: "1"},
+			{Namespace: SettingsGlobal, Key: "animator_duration_scale", Value: "1"},
+		},
+		Builtin: true,
+	},
+	{
+		Name:        "充电时保持唤醒",
+		Description: "连接 AC、USB 或无线充电时屏幕不休眠（7 = AC | USB | 无线）",
+		Settings: []SettingValue{
+			{Namespace: SettingsGlobal, Key: "stay_on_while_plugged_in", Value: "7"},
+		},
+		Builtin: true,
+	},
+	{
+		Name:        "取消充电时保持唤醒",
+		Description: "恢复正常的屏幕休眠",
+		Settings: []SettingValue{
+			{Namespace: SettingsGlobal, Key: "stay_on_while_plugged_in", Value: "0"},
+		},
+		Builtin: true,
+	},
+	{
+		Name:        "显示触摸位置",
+		Description: "显示点按操作反馈和指针位置，便于录屏演示",
+		Settings: []SettingValue{
+			{Namespace: SettingsSystem, Key: "show_touches", Value: "1"},
+			{Namespace: SettingsSystem, Key: "pointer_location", Value: "1"},
+		},
+		Builtin: true,
+	},
+	{
+		Name:        "隐藏触摸位置",
+		Description: "关闭点按操作反馈和指针位置",
+		Settings: []SettingValue{
+			{Namespace: SettingsSystem, Key: "show_touches", Value: "0"},
+			{Namespace: SettingsSystem, Key: "pointer_location", Value: "0"},
+		},
+		Builtin: true,
+	},
+}
+
+// ValidateSetting 检查命名空间与键是否合法
+func ValidateSetting(namespace, key string) error {
+	if !slices.Contains(SettingsNamespaces, namespace) {
+		return fmt.Errorf("未知的命名空间: %s（应为 system、secure 或 global）", namespace)
+	}
+	if !settingKeyPattern.MatchString(key) {
+		return fmt.Errorf("设置键只能包含字母、数字及 _ . - :")
+	}
+	return nil
+}
+
+// ListSettings 列出命名空间下的全部设置（settings list）
+func ListSettings(deviceID, namespace string) (map[string]string, error) {
+	if !slices.Contains(SettingsNamespaces, namespace) {
+		return nil, fmt.Errorf("未知的命名空间: %s", namespace)
+	}
+	result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "settings", "list", namespace}, 15*time.Second)
+	if err != nil {
+		if result != nil && strings.TrimSpace(result.ErrorOutput) != "" {
+			return nil, fmt.Errorf("%s", strings.TrimSpace(result.ErrorOutput))
+		}
+		return nil, err
+	}
+	settings := parseSettingsList(result.Output)
+	log.Printf("读取设置: deviceID=%s, namespace=%s, 数量=%d", deviceID, namespace, len(settings))
+	return settings, nil
+}
+
+// parseSettingsList 解析 "key=value" 格式的 settings list 输出，值中可能含有 "="
+func parseSettingsList(output string) map[string]string {
+	settings := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimRight(scanner.Text(), "\r"), "=")
+		if !ok || key == "" || !settingKeyPattern.MatchString(key) {
+			continue
+		}
+		settings[key] = value
+	}
+	return settings
+}
+
+// GetSetting 读取一项设置，exists 为 false 表示该项不存在（settings get 输出 null）
+func GetSetting(deviceID, namespace, key string) (value string, exists bool, err error) {
+	if err := ValidateSetting(namespace, key); err != nil {
+		return "", false, err
+	}
+	result, err := ExecuteADBCommandWithDevice(deviceID, []string{"shell", "settings", "get", namespace, key}, 10*time.Second)
+	if err != nil {
+		return "", false, err
+	}
+	value = strings.TrimRight(result.Output, "\r\n")
+	if value == "null" {
+		return "", false, nil
+	}
+	return value, true, nil
+}
+
+// PutSetting 修改一项设置（settings put）
+func PutSetting(deviceID, namespace, key, value string) error {
+	if err := ValidateSetting(namespace, key); err != nil {
+		return err
+	}
+	if strings.ContainsAny(value, "\n\x00") {
+		return fmt.Errorf("设置值不能包含换行")
+	}
+	log.Printf("修改设置: deviceID=%s, %s %s=%s", deviceID, namespace, key, value)
+	return runShellCommand(deviceID, "settings", "put", namespace, key, shellQuote(value))
+}
+
+// DeleteSetting 删除一项设置（settings delete），删除后读取时使用系统默认值
+func DeleteSetting(deviceID, namespace, key string) error {
+	if err := ValidateSetting(namespace, key); err != nil {
+		return err
+	}
+	log.Printf("删除设置: deviceID=%s, %s %s", deviceID, namespace, key)
+	return runShellCommand(deviceID, "settings", "delete", namespace, key)
+}
+
+// ResetSettings 按重置模式重置整个命名空间（settings reset），system 命名空间不支持重置
+func ResetSettings(deviceID, namespace, mode string) error {
+	if namespace != SettingsSecure && namespace != SettingsGlobal {
+		return fmt.Errorf("settings reset 只支持 secure 和 global 命名空间")
+	}
+	if _, ok := SettingsResetModes[mode]; !ok {
+		return fmt.Errorf("未知的重置模式: %s", mode)
+	}
+	log.Printf("重置设置: deviceID=%s, namespace=%s, mode=%s", deviceID, namespace, mode)
+	return runShellCommand(deviceID, "settings", "reset", namespace, mode)
+}
+
+// applySetting 写入或删除一项设置
+func applySetting(deviceID string, v SettingValue) error {
+	if v.Delete {
+		return DeleteSetting(deviceID, v.Namespace, v.Key)
+	}
+	return PutSetting(deviceID, v.Namespace, v.Key, v.Value)
+}
+
+// SnapshotSettings 读取 settings 中各项在设备上的当前值，不存在的项记为删除，
+// 应用配置前保存快照即可撤销
+func SnapshotSettings(deviceID string, settings []SettingValue) ([]SettingValue, error) {
+	snapshot := make([]SettingValue, 0, len(settings))
+	for _, v := range settings {
+		value, exists, err := GetSetting(deviceID, v.Namespace, v.Key)
+		if err != nil {
+			return nil, fmt.Errorf("读取 %s %s 失败: %w", v.Namespace, v.Key, err)
+		}
+		snapshot = append(snapshot, SettingValue{Namespace: v.Namespace, Key: v.Key, Value: value, Delete: !exists})
+	}
+	return snapshot, nil
+}
+
+// ApplySettings 依次应用一组设置，遇到错误时停止
+func ApplySettings(deviceID string, settings []SettingValue) error {
+	for _, v := range settings {
+		if err := applySetting(deviceID, v); err != nil {
+			return fmt.Errorf("%s: %w", v, err)
+		}
+	}
+	return nil
+}
+
+// FormatSettingValues 将设置列表格式化为文本，每行一项：
+//
+//	global window_animation_scale 0
+//	delete secure some_key
+func FormatSettingValues(settings []SettingValue) string {
+	var sb strings.Builder
+	for _, v := range settings {
+		sb.WriteString(v.String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// ParseSettingValues 解析 FormatSettingValues 格式的文本，空行与 # 开头的行被忽略
+func ParseSettingValues(text string) ([]SettingValue, error) {
+	var settings []SettingValue
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		var v SettingValue
+		if fields[0] == "delete" {
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("第 %d 行: 格式应为 delete <命名空间> <键>", i+1)
+			}
+			v = SettingValue{Namespace: fields[1], Key: fields[2], Delete: true}
+		} else {
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("第 %d 行: 格式应为 <命名空间> <键> <值>", i+1)
+			}
+			// 值取键之后的剩余部分，保留其中的空格
+			rest := strings.TrimSpace(line[len(fields[0]):])
+			rest = strings.TrimSpace(rest[len(fields[1]):])
+			v = SettingValue{Namespace: fields[0], Key: fields[1], Value: rest}
+		}
+		if err := ValidateSetting(v.Namespace, v.Key); err != nil {
+			return nil, fmt.Errorf("第 %d 行: %w", i+1, err)
+		}
+		settings = append(settings, v)
+	}
+	if len(settings) == 0 {
+		return nil, fmt.Errorf("配置中没有任何设置")
+	}
+	return settings, nil
+}
+
+// DefaultSettingsProfilePath 自定义设置配置的默认保存位置
+func DefaultSettingsProfilePath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "yikong", "settings_profiles.json")
+}
+
+// LoadSettingsProfiles 读取保存的自定义配置，文件不存在时返回空列表
+func LoadSettingsProfiles(path string) ([]SettingsProfile, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var profiles []SettingsProfile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("解析设置配置失败: %w", err)
+	}
+	return profiles, nil
+}
+
+// SaveSettingsProfiles 保存自定义配置，先写临时文件再重命名
+func SaveSettingsProfiles(path string, profiles []SettingsProfile) error {
+	data, err := json.MarshalIndent(profiles, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
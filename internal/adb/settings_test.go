@@ -0,0 +1,54 @@
+package adb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSettingsList(t *testing.T) {
+	output := "adb_enabled=1\r\n" +
+		"window_animation_scale=0.5\r\n" +
+		"empty=\r\n" +
+		"policy=a=b;c=d\r\n" +
+		"no_separator\r\n" +
+		"=value_without_key\r\n" +
+		"bad key=1\r\n" +
+		"vendor:feature.x=on\r\n"
+	want := map[string]string{
+		"adb_enabled":            "1",
+		"window_animation_scale": "0.5",
+		"empty":                  "",
+		"policy":                 "a=b;c=d",
+		"vendor:feature.x":       "on",
+	}
+	if got := parseSettingsList(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %v\nwant %v", got, want)
+	}
+}
+
+func TestValidateSetting(t *testing.T) {
+	tests := []struct {
+		namespace, key string
+		wantErr        bool
+	}{
+		{SettingsGlobal, "adb_enabled", false},
+		{SettingsSecure, "enabled_accessibility_services", false},
+		{SettingsSystem, "vendor:feature.x-y", false},
+		{"config", "adb_enabled", true},
+		{SettingsGlobal, "", true},
+		{SettingsGlobal, "a b", true},
+		{SettingsGlobal, "a;reboot", true},
+	}
+	for _, tt := range tests {
+		if err := ValidateSetting(tt.namespace, tt.key); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateSetting(%q, %q) = %v, wantErr %v", tt.namespace, tt.key, err, tt.wantErr)
+		}
+	}
+	for _, p := range BuiltinSettingsProfiles {
+		for _, s := range p.Settings {
+			if err := ValidateSetting(s.Namespace, s.Key); err != nil {
+				t.Errorf("内置配置 %s: %v", p.Name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package adb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ExecOut 执行 adb exec-out，将设备命令的原始二进制输出写入 w
+// 与 adb shell 不同，exec-out 不分配终端，不会把 \n 转换为 \r\n
+func ExecOut(ctx context.Context, deviceID string, command string, w io.Writer) error {
+	log.Printf("exec-out: deviceID=%s, command=%s", deviceID, command)
+	cmd := exec.CommandContext(ctx, "adb", "-s", deviceID, "exec-out", command)
+	var stderr bytes.Buffer
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// ExecIn 执行 adb exec-in，将 r 的内容作为设备命令的标准输入，返回命令输出
+func ExecIn(ctx context.Context, deviceID string, command string, r io.Reader) (string, error) {
+	log.Printf("exec-in: deviceID=%s, command=%s", deviceID, command)
+	cmd := exec.CommandContext(ctx, "adb", "-s", deviceID, "exec-in", command)
+	cmd.Stdin = r
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
+
+// countingWriter 统计写入的字节数并回调
+type countingWriter struct {
+	writer     io.Writer
+	written    int64
+	onProgress func(written int64)
+	lastReport time.Time
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.written += int64(n)
+	// 限制回调频率，避免界面刷新过于频繁
+	if w.onProgress != nil && time.Since(w.lastReport) >= 100*time.Millisecond {
+		w.lastReport = time.Now()
+		w.onProgress(w.written)
+	}
+	return n, err
+}
+
+// shellQuote 为 shell 参数加单引号，防止路径中的空格和特殊字符被解释
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,617 @@
+package adb
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sync 协议请求/响应ID，见 AOSP packages/modules/adb/file_sync_protocol.h
+const (
+	syncIDStat  = "STAT"
+	syncIDStat2 = "STA2"
+	syncIDList  = "LIST"
+	syncIDList2 = "LIS2"
+	syncIDDent  = "DENT"
+	syncIDDent2 = "DNT2"
+	syncIDSend  = "SEND"
+	syncIDRecv  = "RECV"
+	syncIDData  = "DATA"
+	syncIDDone  = "DONE"
+	syncIDOkay  = "OKAY"
+	syncIDFail  = "FAIL"
+	syncIDQuit  = "QUIT"
+
+	// 单个 DATA 包的最大负载
+	syncDataMax = 64 * 1024
+)
+
+// 设备特性，通过 host-serial:<serial>:features 查询
+const (
+	featureStatV2 = "stat_v2"
+	featureLsV2   = "ls_v2"
+)
+
+// unix 文件类型位
+const (
+	unixTypeMask = 0170000
+	unixTypeDir  = 0040000
+	unixTypeLink = 0120000
+	unixTypeReg  = 0100000
+)
+
+// FileEntry 设备上的一个文件或目录
+type FileEntry struct {
+	Name    string
+	Size    int64
+	Mode    fs.FileMode
+	ModTime time.Time
+}
+
+// IsDir 是否为目录
+func (e FileEntry) IsDir() bool {
+	return e.Mode.IsDir()
+}
+
+// SyncProgress 传输进度回调，path 为当前文件，transferred/total 为当前文件已传输/总字节数
+type SyncProgress func(path string, transferred, total int64)
+
+// SyncConn 一个 adb sync 服务连接，直接与 adb server 通信，不经过 adb push/pull 子进程。
+// 同一连接上的请求必须串行执行
+type SyncConn struct {
+	conn     net.Conn
+	deviceID string
+	statV2   bool
+	lsV2     bool
+	stop     func() bool
+	ctx      context.Context
+}
+
+// adbServerAddress 返回 adb server 地址，兼容 ANDROID_ADB_SERVER_ADDRESS/PORT 环境变量
+func adbServerAddress() string {
+	host := os.Getenv("ANDROID_ADB_SERVER_ADDRESS")
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	port := os.Getenv("ANDROID_ADB_SERVER_PORT")
+	if port == "" {
+		port = "5037"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// dialADBServer 连接 adb server，连接失败时尝试启动 server 后重试一次
+func dialADBServer(ctx context.Context) (net.Conn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", adbServerAddress())
+	if err == nil {
+		return conn, nil
+	}
+	log.Printf("连接adb server失败，尝试启动: %v", err)
+	if startErr := exec.CommandContext(ctx, "adb", "start-server").Run(); startErr != nil {
+		return nil, fmt.Errorf("连接adb server失败: %w", err)
+	}
+	return dialer.DialContext(ctx, "tcp", adbServerAddress())
+}
+
+// sendHostRequest 发送带4位十六进制长度前缀的 host 请求并读取 OKAY/FAIL 状态
+func sendHostRequest(conn net.Conn, request string) error {
+	if _, err := fmt.Fprintf(conn, "%04x%s", len(request), request); err != nil {
+		return err
+	}
+	status := make([]byte, 4)
+	if _, err := io.ReadFull(conn, status); err != nil {
+		return err
+	}
+	switch string(status) {
+	case syncIDOkay:
+		return nil
+	case syncIDFail:
+		message, err := readHostString(conn)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("adb: %s", message)
+	default:
+		return fmt.Errorf("adb: 未知响应 %q", status)
+	}
+}
+
+// readHostString 读取带4位十六进制长度前缀的字符串
+func readHostString(conn net.Conn) (string, error) {
+	lengthHex := make([]byte, 4)
+	if _, err := io.ReadFull(conn, lengthHex); err != nil {
+		return "", err
+	}
+	length, err := strconv.ParseUint(string(lengthHex), 16, 32)
+	if err != nil {
+		return "", fmt.Errorf("adb: 无效的长度 %q", lengthHex)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(conn, data); err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// GetDeviceFeatures 查询设备支持的 adb 特性列表
+func GetDeviceFeatures(ctx context.Context, deviceID string) ([]string, error) {
+	conn, err := dialADBServer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if err := sendHostRequest(conn, "host-serial:"+deviceID+":features"); err != nil {
+		return nil, err
+	}
+	features, err := readHostString(conn)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSpace(features), ","), nil
+}
+
+// OpenSync 打开到设备的 sync 连接。ctx 取消时连接被关闭，正在进行的传输返回 ctx.Err()
+func OpenSync(ctx context.Context, deviceID string) (*SyncConn, error) {
+	features, err := GetDeviceFeatures(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := dialADBServer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := sendHostRequest(conn, "host:transport:"+deviceID); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := sendHostRequest(conn, "sync:"); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	s := &SyncConn{conn: conn, deviceID: deviceID, ctx: ctx}
+	for _, feature := range features {
+		switch feature {
+		case featureStatV2:
+			s.statV2 = true
+		case featureLsV2:
+			s.lsV2 = true
+		}
+	}
+	s.stop = context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	log.Printf("已打开sync连接: deviceID=%s, stat_v2=%v, ls_v2=%v", deviceID, s.statV2, s.lsV2)
+	return s, nil
+}
+
+// Close 发送 QUIT 并关闭连接
+func (s *SyncConn) Close() error {
+	s.stop()
+	s.sendRequest(syncIDQuit, "")
+	return s.conn.Close()
+}
+
+// wrapErr ctx 已取消时返回 ctx.Err()，而不是关闭连接导致的网络错误
+func (s *SyncConn) wrapErr(err error) error {
+	if err != nil && s.ctx.Err() != nil {
+		return s.ctx.Err()
+	}
+	return err
+}
+
+// sendRequest 发送 id + 小端长度 + 负载
+func (s *SyncConn) sendRequest(id string, payload string) error {
+	buf := make([]byte, 8+len(payload))
+	copy(buf, id)
+	binary.LittleEndian.PutUint32(buf[4:], uint32(len(payload)))
+	copy(buf[8:], payload)
+	_, err := s.conn.Write(buf)
+	return err
+}
+
+// readFail 读取 FAIL 之后的错误信息，length 为已读取的长度字段
+func (s *SyncConn) readFail(length uint32) error {
+	message := make([]byte, length)
+	if _, err := io.ReadFull(s.conn, message); err != nil {
+		return err
+	}
+	return errors.New(string(message))
+}
+
+// Stat 获取设备上文件的信息，文件不存在时返回 fs.ErrNotExist
+func (s *SyncConn) Stat(remotePath string) (*FileEntry, error) {
+	entry, err := s.stat(remotePath)
+	return entry, s.wrapErr(err)
+}
+
+func (s *SyncConn) stat(remotePath string) (*FileEntry, error) {
+	if s.statV2 {
+		if err := s.sendRequest(syncIDStat2, remotePath); err != nil {
+			return nil, err
+		}
+		// id, error, dev, ino, mode, nlink, uid, gid, size, atime, mtime, ctime
+		buf := make([]byte, 72)
+		if _, err := io.ReadFull(s.conn, buf); err != nil {
+			return nil, err
+		}
+		if string(buf[:4]) != syncIDStat2 {
+			return nil, fmt.Errorf("sync: 意外的响应 %q", buf[:4])
+		}
+		if errno := binary.LittleEndian.Uint32(buf[4:]); errno != 0 {
+			if errno == 2 { // ENOENT
+				return nil, fmt.Errorf("%s: %w", remotePath, fs.ErrNotExist)
+			}
+			return nil, fmt.Errorf("%s: stat 失败 (errno %d)", remotePath, errno)
+		}
+		return &FileEntry{
+			Name:    path.Base(remotePath),
+			Mode:    unixModeToFileMode(binary.LittleEndian.Uint32(buf[24:])),
+			Size:    int64(binary.LittleEndian.Uint64(buf[40:])),
+			ModTime: time.Unix(int64(binary.LittleEndian.Uint64(buf[56:])), 0),
+		}, nil
+	}
+
+	if err := s.sendRequest(syncIDStat, remotePath); err != nil {
+		return nil, err
+	}
+	// id, mode, size, mtime
+	buf := make([]byte, 16)
+	if _, err := io.ReadFull(s.conn, buf); err != nil {
+		return nil, err
+	}
+	if string(buf[:4]) != syncIDStat {
+		return nil, fmt.Errorf("sync: 意外的响应 %q", buf[:4])
+	}
+	mode := binary.LittleEndian.Uint32(buf[4:])
+	if mode == 0 {
+		// v1 协议无法区分错误类型，mode 为0即表示不存在或无权限
+		return nil, fmt.Errorf("%s: %w", remotePath, fs.ErrNotExist)
+	}
+	return &FileEntry{
+		Name:    path.Base(remotePath),
+		Mode:    unixModeToFileMode(mode),
+		Size:    int64(binary.LittleEndian.Uint32(buf[8:])),
+		ModTime: time.Unix(int64(binary.LittleEndian.Uint32(buf[12:])), 0),
+	}, nil
+}
+
+// List 列出设备目录内容（不含 . 和 ..）
+func (s *SyncConn) List(remotePath string) ([]FileEntry, error) {
+	entries, err := s.list(remotePath)
+	return entries, s.wrapErr(err)
+}
+
+func (s *SyncConn) list(remotePath string) ([]FileEntry, error) {
+	id := syncIDList
+	headerSize := 20 // id, mode, size, mtime, namelen
+	if s.lsV2 {
+		id = syncIDList2
+		headerSize = 76 // id, error, dev, ino, mode, nlink, uid, gid, size, atime, mtime, ctime, namelen
+	}
+	// 目录不存在时 LIST 同样只返回 DONE，与空目录无法区分，因此先 stat 确认存在
+	if _, err := s.stat(remotePath); err != nil {
+		return nil, err
+	}
+	if err := s.sendRequest(id, remotePath); err != nil {
+		return nil, err
+	}
+
+	var entries []FileEntry
+	header := make([]byte, headerSize)
+	for {
+		// 先读取 id 与长度字段，FAIL 之后跟的是错误信息而不是完整的 dent
+		if _, err := io.ReadFull(s.conn, header[:8]); err != nil {
+			return nil, err
+		}
+		respID := string(header[:4])
+		if respID == syncIDFail {
+			return nil, s.readFail(binary.LittleEndian.Uint32(header[4:]))
+		}
+		// DONE 与 dent 等长，需要读完剩余部分以保持连接可复用
+		if _, err := io.ReadFull(s.conn, header[8:]); err != nil {
+			return nil, err
+		}
+		if respID == syncIDDone {
+			return entries, nil
+		}
+
+		var entry FileEntry
+		var nameLen uint32
+		switch {
+		case respID == syncIDDent && !s.lsV2:
+			entry.Mode = unixModeToFileMode(binary.LittleEndian.Uint32(header[4:]))
+			entry.Size = int64(binary.LittleEndian.Uint32(header[8:]))
+			entry.ModTime = time.Unix(int64(binary.LittleEndian.Uint32(header[12:])), 0)
+			nameLen = binary.LittleEndian.Uint32(header[16:])
+		case respID == syncIDDent2 && s.lsV2:
+			entry.Mode = unixModeToFileMode(binary.LittleEndian.Uint32(header[24:]))
+			entry.Size = int64(binary.LittleEndian.Uint64(header[40:]))
+			entry.ModTime = time.Unix(int64(binary.LittleEndian.Uint64(header[56:])), 0)
+			nameLen = binary.LittleEndian.Uint32(header[72:])
+		default:
+			return nil, fmt.Errorf("sync: 意外的响应 %q", respID)
+		}
+
+		name := make([]byte, nameLen)
+		if _, err := io.ReadFull(s.conn, name); err != nil {
+			return nil, err
+		}
+		entry.Name = string(name)
+		if entry.Name == "." || entry.Name == ".." {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// Send 将 r 的内容写入设备文件 remotePath，mode 与 mtime 会被设置到设备文件上。
+// total 仅用于进度回调，未知时传 -1
+func (s *SyncConn) Send(r io.Reader, remotePath string, mode fs.FileMode, mtime time.Time, total int64, progress SyncProgress) error {
+	return s.wrapErr(s.send(r, remotePath, mode, mtime, total, progress))
+}
+
+func (s *SyncConn) send(r io.Reader, remotePath string, mode fs.FileMode, mtime time.Time, total int64, progress SyncProgress) error {
+	if err := s.sendRequest(syncIDSend, remotePath+","+strconv.FormatUint(uint64(fileModeToUnixMode(mode)), 10)); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 8+syncDataMax)
+	copy(buf, syncIDData)
+	var transferred int64
+	lastReport := time.Time{}
+	for {
+		n, readErr := r.Read(buf[8:])
+		if n > 0 {
+			binary.LittleEndian.PutUint32(buf[4:], uint32(n))
+			if _, err := s.conn.Write(buf[:8+n]); err != nil {
+				return err
+			}
+			transferred += int64(n)
+			if progress != nil && time.Since(lastReport) >= 100*time.Millisecond {
+				lastReport = time.Now()
+				progress(remotePath, transferred, total)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+
+	done := make([]byte, 8)
+	copy(done, syncIDDone)
+	binary.LittleEndian.PutUint32(done[4:], uint32(mtime.Unix()))
+	if _, err := s.conn.Write(done); err != nil {
+		return err
+	}
+
+	status := make([]byte, 8)
+	if _, err := io.ReadFull(s.conn, status); err != nil {
+		return err
+	}
+	switch string(status[:4]) {
+	case syncIDOkay:
+		if progress != nil {
+			progress(remotePath, transferred, total)
+		}
+		return nil
+	case syncIDFail:
+		return s.readFail(binary.LittleEndian.Uint32(status[4:]))
+	default:
+		return fmt.Errorf("sync: 意外的响应 %q", status[:4])
+	}
+}
+
+// Receive 将设备文件 remotePath 的内容写入 w，total 仅用于进度回调，未知时传 -1
+func (s *SyncConn) Receive(remotePath string, w io.Writer, total int64, progress SyncProgress) error {
+	return s.wrapErr(s.receive(remotePath, w, total, progress))
+}
+
+func (s *SyncConn) receive(remotePath string, w io.Writer, total int64, progress SyncProgress) error {
+	if err := s.sendRequest(syncIDRecv, remotePath); err != nil {
+		return err
+	}
+
+	header := make([]byte, 8)
+	buf := make([]byte, syncDataMax)
+	var transferred int64
+	lastReport := time.Time{}
+	for {
+		if _, err := io.ReadFull(s.conn, header); err != nil {
+			return err
+		}
+		length := binary.LittleEndian.Uint32(header[4:])
+		switch string(header[:4]) {
+		case syncIDData:
+			if length > syncDataMax {
+				return fmt.Errorf("sync: 数据包过大 (%d 字节)", length)
+			}
+			if _, err := io.ReadFull(s.conn, buf[:length]); err != nil {
+				return err
+			}
+			if _, err := w.Write(buf[:length]); err != nil {
+				return err
+			}
+			transferred += int64(length)
+			if progress != nil && time.Since(lastReport) >= 100*time.Millisecond {
+				lastReport = time.Now()
+				progress(remotePath, transferred, total)
+			}
+		case syncIDDone:
+			if progress != nil {
+				progress(remotePath, transferred, total)
+			}
+			return nil
+		case syncIDFail:
+			return s.readFail(length)
+		default:
+			return fmt.Errorf("sync: 意外的响应 %q", header[:4])
+		}
+	}
+}
+
+// PushFile 上传单个本地文件，保留权限与修改时间
+func (s *SyncConn) PushFile(localPath string, remotePath string, progress SyncProgress) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	return s.Send(file, remotePath, info.Mode().Perm(), info.ModTime(), info.Size(), progress)
+}
+
+// PullFile 下载单个设备文件，保留权限与修改时间；失败时删除不完整的本地文件
+func (s *SyncConn) PullFile(remotePath string, localPath string, progress SyncProgress) error {
+	entry, err := s.Stat(remotePath)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(localPath)
+	if err != nil {
+		return err
+	}
+	if err := s.Receive(remotePath, file, entry.Size, progress); err != nil {
+		file.Close()
+		os.Remove(localPath)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if perm := entry.Mode.Perm(); perm != 0 {
+		os.Chmod(localPath, perm)
+	}
+	return os.Chtimes(localPath, entry.ModTime, entry.ModTime)
+}
+
+// Push 上传本地文件或目录到设备。remotePath 为已存在的目录时上传到其中，
+// 与 adb push 的行为一致
+func Push(ctx context.Context, deviceID string, localPath string, remotePath string, progress SyncProgress) error {
+	log.Printf("sync推送: deviceID=%s, local=%s, remote=%s", deviceID, localPath, remotePath)
+	s, err := OpenSync(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	if entry, err := s.Stat(remotePath); err == nil && entry.IsDir() {
+		remotePath = path.Join(remotePath, filepath.Base(localPath))
+	}
+
+	return filepath.WalkDir(localPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(localPath, p)
+		if err != nil {
+			return err
+		}
+		target := remotePath
+		if rel != "." {
+			target = path.Join(remotePath, filepath.ToSlash(rel))
+		}
+		// sync SEND 会自动创建父目录，空目录无法通过 SEND 创建，这里跳过
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		return s.PushFile(p, target, progress)
+	})
+}
+
+// Pull 下载设备文件或目录到本地。localPath 为已存在的目录时下载到其中，
+// 与 adb pull 的行为一致
+func Pull(ctx context.Context, deviceID string, remotePath string, localPath string, progress SyncProgress) error {
+	log.Printf("sync拉取: deviceID=%s, remote=%s, local=%s", deviceID, remotePath, localPath)
+	s, err := OpenSync(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	entry, err := s.Stat(remotePath)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(localPath); err == nil && info.IsDir() {
+		localPath = filepath.Join(localPath, path.Base(remotePath))
+	}
+	if !entry.IsDir() {
+		return s.PullFile(remotePath, localPath, progress)
+	}
+	return s.pullDir(remotePath, localPath, progress)
+}
+
+func (s *SyncConn) pullDir(remotePath string, localPath string, progress SyncProgress) error {
+	if err := os.MkdirAll(localPath, 0755); err != nil {
+		return err
+	}
+	entries, err := s.List(remotePath)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		remote := path.Join(remotePath, entry.Name)
+		local := filepath.Join(localPath, entry.Name)
+		switch {
+		case entry.IsDir():
+			if err := s.pullDir(remote, local, progress); err != nil {
+				return err
+			}
+		case entry.Mode.IsRegular():
+			if err := s.PullFile(remote, local, progress); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// unixModeToFileMode 将 st_mode 转换为 fs.FileMode
+func unixModeToFileMode(mode uint32) fs.FileMode {
+	result := fs.FileMode(mode & 0777)
+	switch mode & unixTypeMask {
+	case unixTypeDir:
+		result |= fs.ModeDir
+	case unixTypeLink:
+		result |= fs.ModeSymlink
+	case unixTypeReg:
+	default:
+		result |= fs.ModeIrregular
+	}
+	return result
+}
+
+// fileModeToUnixMode 将 fs.FileMode 转换为 SEND 请求使用的 st_mode
+func fileModeToUnixMode(mode fs.FileMode) uint32 {
+	perm := uint32(mode.Perm())
+	if perm == 0 {
+		perm = 0644
+	}
+	if mode&fs.ModeSymlink != 0 {
+		return unixTypeLink | perm
+	}
+	return unixTypeReg | perm
+}
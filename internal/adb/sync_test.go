@@ -0,0 +1,85 @@
+package adb
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+	"reflect"
+	"testing"
+)
+
+// fakeSyncServer 每读到一个请求就依次写回 responses 中对应的响应
+func fakeSyncServer(t *testing.T, responses ...[]byte) *SyncConn {
+	t.Helper()
+	client, server := net.Pipe()
+	t.Cleanup(func() { client.Close(); server.Close() })
+	go func() {
+		for _, resp := range responses {
+			header := make([]byte, 8)
+			if _, err := io.ReadFull(server, header); err != nil {
+				return
+			}
+			if _, err := io.ReadFull(server, make([]byte, binary.LittleEndian.Uint32(header[4:]))); err != nil {
+				return
+			}
+			if _, err := server.Write(resp); err != nil {
+				return
+			}
+		}
+	}()
+	return &SyncConn{conn: client, ctx: context.Background()}
+}
+
+func syncFrame(id string, fields ...uint32) []byte {
+	buf := []byte(id)
+	for _, f := range fields {
+		buf = binary.LittleEndian.AppendUint32(buf, f)
+	}
+	return buf
+}
+
+func TestSyncListV1(t *testing.T) {
+	statDir := syncFrame(syncIDStat, 0o040755, 4096, 0)
+	dent := func(name string, mode uint32, size uint32) []byte {
+		return append(syncFrame(syncIDDent, mode, size, 0, uint32(len(name))), name...)
+	}
+	var listing []byte
+	listing = append(listing, dent(".", 0o040755, 0)...)
+	listing = append(listing, dent("..", 0o040755, 0)...)
+	listing = append(listing, dent("a.txt", 0o100644, 3)...)
+	listing = append(listing, syncFrame(syncIDDone, 0, 0, 0, 0)...)
+
+	t.Run("正常列出且连接可复用", func(t *testing.T) {
+		s := fakeSyncServer(t, statDir, listing, syncFrame(syncIDStat, 0o100644, 3, 0))
+		entries, err := s.List("/sdcard")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name)
+		}
+		if !reflect.DeepEqual(names, []string{"a.txt"}) {
+			t.Errorf("names = %v", names)
+		}
+		// DONE 之后的 STAT 响应必须能正确对齐
+		if entry, err := s.Stat("/sdcard/a.txt"); err != nil || entry.Size != 3 {
+			t.Errorf("Stat after List = %+v, %v", entry, err)
+		}
+	})
+	t.Run("目录不存在", func(t *testing.T) {
+		s := fakeSyncServer(t, syncFrame(syncIDStat, 0, 0, 0))
+		if _, err := s.List("/sdcard/missing"); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("err = %v, want fs.ErrNotExist", err)
+		}
+	})
+	t.Run("FAIL 的错误信息短于 dent", func(t *testing.T) {
+		s := fakeSyncServer(t, statDir, append(syncFrame(syncIDFail, 2), "no"...))
+		if _, err := s.List("/data"); err == nil || err.Error() != "no" {
+			t.Errorf("err = %v, want \"no\"", err)
+		}
+	})
+}
@@ -0,0 +1,586 @@
+package adb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// 传输任务状态
+const (
+	TransferPending  = "pending"
+	TransferRunning  = "running"
+	TransferRetrying = "retrying"
+	TransferDone     = "done"
+	TransferFailed   = "failed"
+	TransferCanceled = "canceled"
+)
+
+// 重试参数：第 n 次重试前等待 transferBackoffBase * 2^(n-1)，不超过 transferBackoffMax
+const (
+	transferMaxAttempts = 5
+	transferBackoffBase = 2 * time.Second
+	transferBackoffMax  = time.Minute
+)
+
+// 进度通知的最小间隔，避免界面刷新过于频繁
+const transferNotifyInterval = 200 * time.Millisecond
+
+// TransferJob 传输队列中的一个任务，Source/Target 可以是文件或目录。
+// Direction 取值为 SyncPush 或 SyncPull
+type TransferJob struct {
+	ID          string     `json:"id"`
+	DeviceID    string     `json:"device_id"`
+	Direction   string     `json:"direction"`
+	Source      string     `json:"source"`
+	Target      string     `json:"target"`
+	Access      FileAccess `json:"access"`
+	Status      string     `json:"status"`
+	Attempts    int        `json:"attempts"`
+	Error       string     `json:"error,omitempty"`
+	Total       int64      `json:"total"`
+	Transferred int64      `json:"transferred"`
+	CreatedAt   time.Time  `json:"created_at"`
+	NextAttempt time.Time  `json:"next_attempt,omitempty"`
+
+	// 以下为运行时状态，不持久化
+	Speed      float64 `json:"-"` // 字节/秒
+	cancel     context.CancelFunc
+	onDone     func(err error)
+	sampleTime time.Time
+	sampleSize int64
+}
+
+// ETA 按当前速度估算剩余时间，无法估算时返回 0
+func (j TransferJob) ETA() time.Duration {
+	if j.Speed <= 0 || j.Total <= 0 || j.Transferred >= j.Total {
+		return 0
+	}
+	return time.Duration(float64(j.Total-j.Transferred) / j.Speed * float64(time.Second))
+}
+
+// Finished 任务是否已结束（完成、失败或取消）
+func (j TransferJob) Finished() bool {
+	return j.Status == TransferDone || j.Status == TransferFailed || j.Status == TransferCanceled
+}
+
+// TransferManager 持久化的传输队列：按设备限制并发数，失败自动退避重试，
+// 拉取中断的文件按偏移续传
+type TransferManager struct {
+	mu        sync.Mutex
+	jobs      []*TransferJob
+	storePath string
+	perDevice int
+	running   map[string]int
+	nextID    int
+	onChange  func()
+	notified  time.Time
+}
+
+// DefaultTransferStorePath 传输队列的默认保存位置
+func DefaultTransferStorePath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "yikong", "transfers.json")
+}
+
+// NewTransferManager 创建传输管理器，perDevice 为每台设备同时进行的任务数
+func NewTransferManager(storePath string, perDevice int) *TransferManager {
+	if perDevice < 1 {
+		perDevice = 1
+	}
+	return &TransferManager{
+		storePath: storePath,
+		perDevice: perDevice,
+		running:   make(map[string]int),
+	}
+}
+
+// SetOnChange 设置队列变化回调（在后台 goroutine 中调用）
+func (m *TransferManager) SetOnChange(onChange func()) {
+	m.mu.Lock()
+	m.onChange = onChange
+	m.mu.Unlock()
+}
+
+// SetPerDeviceLimit 修改每台设备的并发数
+func (m *TransferManager) SetPerDeviceLimit(perDevice int) {
+	m.mu.Lock()
+	if perDevice >= 1 {
+		m.perDevice = perDevice
+	}
+	m.mu.Unlock()
+	m.schedule()
+}
+
+// Load 读取上次保存的队列，未完成的任务重新排队
+func (m *TransferManager) Load() error {
+	data, err := os.ReadFile(m.storePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var jobs []*TransferJob
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return fmt.Errorf("解析传输队列失败: %w", err)
+	}
+
+	m.mu.Lock()
+	for _, job := range jobs {
+		if job.Status == TransferRunning || job.Status == TransferRetrying {
+			job.Status = TransferPending
+		}
+		if id, err := strconv.Atoi(job.ID); err == nil && id >= m.nextID {
+			m.nextID = id + 1
+		}
+	}
+	m.jobs = jobs
+	m.mu.Unlock()
+	log.Printf("已加载传输队列: %d 个任务", len(jobs))
+
+	m.schedule()
+	return nil
+}
+
+// Enqueue 加入一个传输任务，access 为访问设备文件的身份，
+// onDone 在任务最终完成、失败或取消时调用
+func (m *TransferManager) Enqueue(deviceID string, direction string, source string, target string, access FileAccess, onDone func(err error)) *TransferJob {
+	m.mu.Lock()
+	job := &TransferJob{
+		ID:        strconv.Itoa(m.nextID),
+		DeviceID:  deviceID,
+		Direction: direction,
+		Source:    source,
+		Target:    target,
+		Access:    access,
+		Status:    TransferPending,
+		CreatedAt: time.Now(),
+		onDone:    onDone,
+	}
+	m.nextID++
+	m.jobs = append(m.jobs, job)
+	m.saveLocked()
+	m.mu.Unlock()
+	log.Printf("加入传输队列: id=%s, %s %s → %s (%s)", job.ID, direction, source, target, deviceID)
+
+	m.notify(true)
+	m.schedule()
+	return job
+}
+
+// Jobs 返回当前队列的快照
+func (m *TransferManager) Jobs() []TransferJob {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	jobs := make([]TransferJob, len(m.jobs))
+	for i, job := range m.jobs {
+		jobs[i] = *job
+	}
+	return jobs
+}
+
+// Cancel 取消任务，运行中的传输会被中断
+func (m *TransferManager) Cancel(id string) {
+	m.mu.Lock()
+	job := m.findLocked(id)
+	if job == nil || job.Finished() {
+		m.mu.Unlock()
+		return
+	}
+	if job.cancel != nil {
+		// 由 run 负责更新状态
+		job.cancel()
+		m.mu.Unlock()
+		return
+	}
+	job.Status = TransferCanceled
+	m.saveLocked()
+	onDone := job.onDone
+	m.mu.Unlock()
+
+	if onDone != nil {
+		onDone(context.Canceled)
+	}
+	m.notify(true)
+}
+
+// Retry 重新执行失败或已取消的任务
+func (m *TransferManager) Retry(id string) {
+	m.mu.Lock()
+	job := m.findLocked(id)
+	if job == nil || (job.Status != TransferFailed && job.Status != TransferCanceled) {
+		m.mu.Unlock()
+		return
+	}
+	job.Status = TransferPending
+	job.Attempts = 0
+	job.Error = ""
+	m.saveLocked()
+	m.mu.Unlock()
+
+	m.notify(true)
+	m.schedule()
+}
+
+// ClearFinished 移除已结束的任务
+func (m *TransferManager) ClearFinished() {
+	m.mu.Lock()
+	kept := m.jobs[:0]
+	for _, job := range m.jobs {
+		if !job.Finished() {
+			kept = append(kept, job)
+		}
+	}
+	m.jobs = kept
+	m.saveLocked()
+	m.mu.Unlock()
+	m.notify(true)
+}
+
+func (m *TransferManager) findLocked(id string) *TransferJob {
+	for _, job := range m.jobs {
+		if job.ID == id {
+			return job
+		}
+	}
+	return nil
+}
+
+// saveLocked 将队列写入磁盘，先写临时文件再重命名，避免写入中断导致文件损坏
+func (m *TransferManager) saveLocked() {
+	if m.storePath == "" {
+		return
+	}
+	data, err := json.MarshalIndent(m.jobs, "", "  ")
+	if err != nil {
+		log.Printf("序列化传输队列失败: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(m.storePath), 0755); err != nil {
+		log.Printf("创建传输队列目录失败: %v", err)
+		return
+	}
+	tmp := m.storePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		log.Printf("保存传输队列失败: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, m.storePath); err != nil {
+		log.Printf("保存传输队列失败: %v", err)
+	}
+}
+
+// notify 调用变化回调，force 为 false 时按 transferNotifyInterval 限流
+func (m *TransferManager) notify(force bool) {
+	m.mu.Lock()
+	if !force && time.Since(m.notified) < transferNotifyInterval {
+		m.mu.Unlock()
+		return
+	}
+	m.notified = time.Now()
+	onChange := m.onChange
+	m.mu.Unlock()
+	if onChange != nil {
+		onChange()
+	}
+}
+
+// schedule 在不超过每台设备并发限制的前提下启动等待中的任务
+func (m *TransferManager) schedule() {
+	m.mu.Lock()
+	now := time.Now()
+	started := false
+	for _, job := range m.jobs {
+		ready := job.Status == TransferPending || (job.Status == TransferRetrying && !now.Before(job.NextAttempt))
+		if !ready || m.running[job.DeviceID] >= m.perDevice {
+			continue
+		}
+		m.running[job.DeviceID]++
+		ctx, cancel := context.WithCancel(context.Background())
+		job.cancel = cancel
+		job.Status = TransferRunning
+		job.Attempts++
+		job.Error = ""
+		job.Speed = 0
+		job.sampleTime = time.Time{}
+		started = true
+		go m.run(ctx, job)
+	}
+	if started {
+		m.saveLocked()
+	}
+	m.mu.Unlock()
+	if started {
+		m.notify(true)
+	}
+}
+
+// run 执行一次传输尝试并根据结果决定完成、重试或失败
+func (m *TransferManager) run(ctx context.Context, job *TransferJob) {
+	m.mu.Lock()
+	snapshot := *job
+	m.mu.Unlock()
+	log.Printf("开始传输: id=%s, 第 %d 次尝试", snapshot.ID, snapshot.Attempts)
+
+	err := m.execute(ctx, snapshot, func(transferred, total int64) {
+		m.mu.Lock()
+		job.Transferred = transferred
+		job.Total = total
+		now := time.Now()
+		if job.sampleTime.IsZero() {
+			job.sampleTime, job.sampleSize = now, transferred
+		} else if elapsed := now.Sub(job.sampleTime).Seconds(); elapsed >= 0.5 {
+			// 指数平滑，避免速度与剩余时间剧烈跳动
+			instant := float64(transferred-job.sampleSize) / elapsed
+			if job.Speed == 0 {
+				job.Speed = instant
+			} else {
+				job.Speed = job.Speed*0.7 + instant*0.3
+			}
+			job.sampleTime, job.sampleSize = now, transferred
+		}
+		m.mu.Unlock()
+		m.notify(false)
+	})
+
+	m.mu.Lock()
+	m.running[job.DeviceID]--
+	job.cancel = nil
+	job.Speed = 0
+	var onDone func(error)
+	switch {
+	case ctx.Err() != nil:
+		job.Status = TransferCanceled
+		err = context.Canceled
+		onDone = job.onDone
+	case err == nil:
+		job.Status = TransferDone
+		job.Transferred = job.Total
+		onDone = job.onDone
+	case job.Attempts < transferMaxAttempts:
+		backoff := transferBackoffBase << (job.Attempts - 1)
+		if backoff > transferBackoffMax {
+			backoff = transferBackoffMax
+		}
+		job.Status = TransferRetrying
+		job.Error = err.Error()
+		job.NextAttempt = time.Now().Add(backoff)
+		time.AfterFunc(backoff, m.schedule)
+		log.Printf("传输失败，%v 后重试: id=%s, 错误: %v", backoff, job.ID, err)
+	default:
+		job.Status = TransferFailed
+		job.Error = err.Error()
+		onDone = job.onDone
+	}
+	m.saveLocked()
+	m.mu.Unlock()
+
+	if onDone != nil {
+		onDone(err)
+	}
+	m.notify(true)
+	m.schedule()
+}
+
+// execute 执行传输。同一文件在两端大小与修改时间一致时跳过，
+// 因此重试时已完成的文件不会重复传输
+func (m *TransferManager) execute(ctx context.Context, job TransferJob, progress func(transferred, total int64)) error {
+	if !job.Access.IsDirect() {
+		return executePrivileged(ctx, job, progress)
+	}
+
+	s, err := OpenSync(ctx, job.DeviceID)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	if job.Direction == SyncPush {
+		return pushTree(s, job.Source, job.Target, progress)
+	}
+	return pullTree(ctx, s, job.DeviceID, job.Source, job.Target, progress)
+}
+
+// executePrivileged 通过 run-as/su 以 tar 流传输，无法续传，失败时整体重试。
+// 拉取时无法预先得知总大小，只报告已传输字节数
+func executePrivileged(ctx context.Context, job TransferJob, progress func(transferred, total int64)) error {
+	if job.Direction == SyncPush {
+		var total int64
+		filepath.WalkDir(job.Source, func(_ string, d fs.DirEntry, err error) error {
+			if err == nil && d.Type().IsRegular() {
+				if info, err := d.Info(); err == nil {
+					total += info.Size()
+				}
+			}
+			return nil
+		})
+		return PushAs(ctx, job.DeviceID, job.Source, job.Target, job.Access, func(written int64) {
+			progress(min(written, total), total)
+		})
+	}
+	return PullAs(ctx, job.DeviceID, job.Source, job.Target, job.Access, func(written int64) {
+		progress(written, 0)
+	})
+}
+
+// pushTree 推送文件或目录，target 为已存在的设备目录时推送到其中
+func pushTree(s *SyncConn, source string, target string, progress func(transferred, total int64)) error {
+	if entry, err := s.Stat(target); err == nil && entry.IsDir() {
+		target = path.Join(target, filepath.Base(source))
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+	files := map[string]syncFileInfo{}
+	if info.IsDir() {
+		if files, err = walkLocalTree(source); err != nil {
+			return err
+		}
+	} else {
+		files["."] = syncFileInfo{size: info.Size(), modTime: info.ModTime()}
+	}
+
+	var total, done int64
+	for _, f := range files {
+		total += f.size
+	}
+	progress(0, total)
+
+	for rel, f := range files {
+		local, remote := source, target
+		if rel != "." {
+			local = filepath.Join(source, filepath.FromSlash(rel))
+			remote = path.Join(target, rel)
+		}
+		if existing, err := s.Stat(remote); err == nil && existing.Size == f.size &&
+			absDuration(existing.ModTime.Sub(f.modTime)) <= mtimeTolerance {
+			done += f.size
+			progress(done, total)
+			continue
+		}
+		base := done
+		err := s.PushFile(local, remote, func(_ string, transferred, _ int64) {
+			progress(base+transferred, total)
+		})
+		if err != nil {
+			return err
+		}
+		done += f.size
+	}
+	return nil
+}
+
+// pullTree 拉取文件或目录，target 为已存在的本地目录时拉取到其中
+func pullTree(ctx context.Context, s *SyncConn, deviceID string, source string, target string, progress func(transferred, total int64)) error {
+	entry, err := s.Stat(source)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(target); err == nil && info.IsDir() {
+		target = filepath.Join(target, path.Base(source))
+	}
+
+	files := map[string]syncFileInfo{}
+	if entry.IsDir() {
+		if err := walkRemoteTree(s, source, "", files); err != nil {
+			return err
+		}
+	} else {
+		files["."] = syncFileInfo{size: entry.Size, modTime: entry.ModTime}
+	}
+
+	var total, done int64
+	for _, f := range files {
+		total += f.size
+	}
+	progress(0, total)
+
+	for rel, f := range files {
+		remote, local := source, target
+		if rel != "." {
+			remote = path.Join(source, rel)
+			local = filepath.Join(target, filepath.FromSlash(rel))
+		}
+		base := done
+		err := pullFileResumable(ctx, s, deviceID, remote, local, f, func(transferred int64) {
+			progress(base+transferred, total)
+		})
+		if err != nil {
+			return err
+		}
+		done += f.size
+		progress(done, total)
+	}
+	return nil
+}
+
+// pullFileResumable 拉取单个文件。数据先写入以远端大小和修改时间命名的 .part 文件，
+// 中断后再次拉取同一版本的文件时通过 `tail -c +N` 从已有偏移处续传
+func pullFileResumable(ctx context.Context, s *SyncConn, deviceID string, remote string, local string, f syncFileInfo, progress func(transferred int64)) error {
+	if info, err := os.Stat(local); err == nil && info.Size() == f.size &&
+		absDuration(info.ModTime().Sub(f.modTime)) <= mtimeTolerance {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(local), 0755); err != nil {
+		return err
+	}
+
+	part := fmt.Sprintf("%s.%d-%d.part", local, f.size, f.modTime.Unix())
+	var offset int64
+	if info, err := os.Stat(part); err == nil && info.Size() <= f.size {
+		offset = info.Size()
+	}
+
+	file, err := os.OpenFile(part, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	if offset > 0 && offset < f.size {
+		log.Printf("续传: %s 从偏移 %d 开始", remote, offset)
+		writer := &countingWriter{writer: file, onProgress: func(written int64) {
+			progress(offset + written)
+		}}
+		err = ExecOut(ctx, deviceID, "tail -c +"+strconv.FormatInt(offset+1, 10)+" "+shellQuote(remote), writer)
+		if err == nil && offset+writer.written != f.size {
+			err = fmt.Errorf("续传后大小不一致: %d / %d", offset+writer.written, f.size)
+		}
+		if err != nil && ctx.Err() == nil {
+			// 设备不支持 tail -c 或数据不一致时从头下载
+			log.Printf("续传失败，改为完整下载: %s, 错误: %v", remote, err)
+			offset = 0
+			if err = file.Truncate(0); err == nil {
+				err = s.Receive(remote, file, f.size, func(_ string, transferred, _ int64) { progress(transferred) })
+			}
+		}
+	} else if offset == 0 {
+		err = s.Receive(remote, file, f.size, func(_ string, transferred, _ int64) { progress(transferred) })
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// 保留 .part 文件供下次续传
+		return err
+	}
+
+	if err := os.Rename(part, local); err != nil {
+		return err
+	}
+	return os.Chtimes(local, f.modTime, f.modTime)
+}
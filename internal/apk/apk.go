@@ -0,0 +1,255 @@
+package apk
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Info APK文件的静态分析结果
+type Info struct {
+	Path               string
+	FileSize           int64
+	PackageName        string
+	VersionCode        int64
+	VersionName        string
+	Label              string
+	MinSDK             int
+	TargetSDK          int
+	ABIs               []string
+	Permissions        []string
+	Activities         []string
+	LaunchableActivity string
+	Debuggable         bool
+	Certificates       []Certificate
+	SigningSchemes     []string
+}
+
+// Analyze 打开APK（zip）并解析清单、资源表和签名信息
+func Analyze(path string) (*Info, error) {
+	log.Printf("开始分析APK: %s", path)
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("无法作为zip打开: %w", err)
+	}
+	defer reader.Close()
+
+	info := &Info{Path: path, FileSize: stat.Size()}
+
+	manifestData, err := readZipEntry(&reader.Reader, "AndroidManifest.xml")
+	if err != nil {
+		return nil, fmt.Errorf("读取AndroidManifest.xml失败: %w", err)
+	}
+	manifest, err := ParseAXML(manifestData)
+	if err != nil {
+		return nil, fmt.Errorf("解析AndroidManifest.xml失败: %w", err)
+	}
+
+	// resources.arsc 只用于解析引用，缺失或损坏时不影响其他字段
+	var table *ResourceTable
+	if arscData, err := readZipEntry(&reader.Reader, "resources.arsc"); err == nil {
+		if table, err = ParseResourceTable(arscData); err != nil {
+			log.Printf("解析resources.arsc失败: %v", err)
+		}
+	}
+
+	info.applyManifest(manifest, table)
+	info.ABIs = collectABIs(reader.File)
+
+	v1Certs, err := readV1Certificates(reader.File)
+	if err != nil {
+		log.Printf("读取v1签名失败: %v", err)
+	}
+	blockCerts, err := readSigningBlockCertificates(path)
+	if err != nil {
+		log.Printf("读取APK签名块失败: %v", err)
+	}
+	info.Certificates = append(v1Certs, blockCerts...)
+
+	seen := make(map[string]bool)
+	for _, cert := range info.Certificates {
+		if !seen[cert.Scheme] {
+			seen[cert.Scheme] = true
+			info.SigningSchemes = append(info.SigningSchemes, cert.Scheme)
+		}
+	}
+
+	log.Printf("APK分析完成: package=%s, versionCode=%d, 签名方案=%v", info.PackageName, info.VersionCode, info.SigningSchemes)
+	return info, nil
+}
+
+func readZipEntry(reader *zip.Reader, name string) ([]byte, error) {
+	for _, f := range reader.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	return nil, fmt.Errorf("zip中不存在 %s", name)
+}
+
+// resolveAttr 返回属性值，引用类型通过资源表解析
+func resolveAttr(elem *XMLElement, name string, table *ResourceTable) string {
+	attr := elem.Attr(name)
+	if attr == nil {
+		return ""
+	}
+	if attr.Value.IsReference() {
+		if resolved, ok := table.Resolve(attr.Value.Data); ok {
+			return resolved
+		}
+	}
+	return attr.Text
+}
+
+func (info *Info) applyManifest(manifest *XMLElement, table *ResourceTable) {
+	info.PackageName = manifest.AttrString("package")
+	info.VersionName = resolveAttr(manifest, "versionName", table)
+	if code, err := strconv.ParseInt(resolveAttr(manifest, "versionCode", table), 10, 64); err == nil {
+		info.VersionCode = code
+	}
+	// versionCodeMajor 为高32位
+	if major, err := strconv.ParseInt(manifest.AttrString("versionCodeMajor"), 10, 64); err == nil && major > 0 {
+		info.VersionCode |= major << 32
+	}
+
+	for _, sdk := range manifest.Find("uses-sdk") {
+		info.MinSDK, _ = strconv.Atoi(resolveAttr(sdk, "minSdkVersion", table))
+		info.TargetSDK, _ = strconv.Atoi(resolveAttr(sdk, "targetSdkVersion", table))
+	}
+	if info.MinSDK == 0 {
+		// 未声明时系统按1处理
+		info.MinSDK = 1
+	}
+	if info.TargetSDK == 0 {
+		info.TargetSDK = info.MinSDK
+	}
+
+	for _, tag := range []string{"uses-permission", "uses-permission-sdk-23", "uses-permission-sdk-m"} {
+		for _, perm := range manifest.Find(tag) {
+			if name := perm.AttrString("name"); name != "" {
+				info.Permissions = append(info.Permissions, name)
+			}
+		}
+	}
+	sort.Strings(info.Permissions)
+
+	for _, application := range manifest.Find("application") {
+		info.Label = resolveAttr(application, "label", table)
+		info.Debuggable = application.AttrString("debuggable") == "true"
+
+		for _, tag := range []string{"activity", "activity-alias"} {
+			for _, activity := range application.Find(tag) {
+				name := qualifyClassName(info.PackageName, activity.AttrString("name"))
+				if name == "" {
+					continue
+				}
+				info.Activities = append(info.Activities, name)
+				if info.LaunchableActivity == "" && isLauncherActivity(activity) {
+					info.LaunchableActivity = name
+				}
+			}
+		}
+	}
+}
+
+// qualifyClassName 将 ".MainActivity" 这类相对类名补全为完整类名
+func qualifyClassName(packageName, name string) string {
+	if strings.HasPrefix(name, ".") {
+		return packageName + name
+	}
+	if name != "" && !strings.Contains(name, ".") {
+		return packageName + "." + name
+	}
+	return name
+}
+
+func isLauncherActivity(activity *XMLElement) bool {
+	for _, filter := range activity.Find("intent-filter") {
+		hasMain, hasLauncher := false, false
+		for _, action := range filter.Find("action") {
+			if action.AttrString("name") == "android.intent.action.MAIN" {
+				hasMain = true
+			}
+		}
+		for _, category := range filter.Find("category") {
+			if category.AttrString("name") == "android.intent.category.LAUNCHER" {
+				hasLauncher = true
+			}
+		}
+		if hasMain && hasLauncher {
+			return true
+		}
+	}
+	return false
+}
+
+// collectABIs 根据 lib/<abi>/*.so 统计APK包含的原生库架构
+func collectABIs(files []*zip.File) []string {
+	seen := make(map[string]bool)
+	var abis []string
+	for _, f := range files {
+		parts := strings.Split(f.Name, "/")
+		if len(parts) == 3 && parts[0] == "lib" && strings.HasSuffix(parts[2], ".so") && !seen[parts[1]] {
+			seen[parts[1]] = true
+			abis = append(abis, parts[1])
+		}
+	}
+	sort.Strings(abis)
+	return abis
+}
+
+// Summary 生成用于展示的多行文本摘要
+func (info *Info) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "文件: %s (%d 字节)\n", info.Path, info.FileSize)
+	fmt.Fprintf(&b, "应用名称: %s\n", info.Label)
+	fmt.Fprintf(&b, "包名: %s\n", info.PackageName)
+	fmt.Fprintf(&b, "版本: %s (versionCode=%d)\n", info.VersionName, info.VersionCode)
+	fmt.Fprintf(&b, "SDK: min=%d, target=%d\n", info.MinSDK, info.TargetSDK)
+	fmt.Fprintf(&b, "可调试: %v\n", info.Debuggable)
+	if len(info.ABIs) > 0 {
+		fmt.Fprintf(&b, "ABI: %s\n", strings.Join(info.ABIs, ", "))
+	} else {
+		b.WriteString("ABI: 无原生库（通用）\n")
+	}
+	if info.LaunchableActivity != "" {
+		fmt.Fprintf(&b, "启动Activity: %s\n", info.LaunchableActivity)
+	}
+
+	fmt.Fprintf(&b, "\n签名方案: %s\n", strings.Join(info.SigningSchemes, ", "))
+	for _, cert := range info.Certificates {
+		fmt.Fprintf(&b, "[%s] %s\n", cert.Scheme, cert.Subject)
+		fmt.Fprintf(&b, "  有效期: %s ~ %s\n", cert.NotBefore, cert.NotAfter)
+		fmt.Fprintf(&b, "  SHA-256: %s\n", cert.SHA256)
+		fmt.Fprintf(&b, "  SHA-1: %s\n", cert.SHA1)
+		fmt.Fprintf(&b, "  MD5: %s\n", cert.MD5)
+	}
+
+	fmt.Fprintf(&b, "\n权限 (%d):\n", len(info.Permissions))
+	for _, perm := range info.Permissions {
+		b.WriteString("  " + perm + "\n")
+	}
+
+	fmt.Fprintf(&b, "\nActivity (%d):\n", len(info.Activities))
+	for _, activity := range info.Activities {
+		b.WriteString("  " + activity + "\n")
+	}
+	return b.String()
+}
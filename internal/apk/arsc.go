@@ -0,0 +1,198 @@
+package apk
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Res_table_type 标志位
+const (
+	typeFlagSparse   = 0x01
+	typeFlagOffset16 = 0x02
+
+	entryFlagComplex = 0x0001
+	entryFlagCompact = 0x0008
+
+	noEntry   = 0xffffffff
+	noEntry16 = 0xffff
+)
+
+// tableEntry 资源表中某个配置下的取值
+type tableEntry struct {
+	value         ResValue
+	defaultConfig bool
+}
+
+// ResourceTable 是 resources.arsc 的精简解析结果，
+// 只保留简单值（字符串、整数等），足以解析清单中引用的 label、versionName 等
+type ResourceTable struct {
+	strings []string
+	entries map[uint32][]tableEntry
+}
+
+// ParseResourceTable 解析 resources.arsc
+func ParseResourceTable(data []byte) (*ResourceTable, error) {
+	root, err := readChunkHeader(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	if root.Type != chunkTable {
+		return nil, fmt.Errorf("不是资源表文件: type=0x%04x", root.Type)
+	}
+
+	table := &ResourceTable{entries: make(map[uint32][]tableEntry)}
+
+	offset := int(root.HeaderSize)
+	for offset < int(root.Size) {
+		h, err := readChunkHeader(data, offset)
+		if err != nil {
+			return nil, err
+		}
+		chunk := data[offset : offset+int(h.Size)]
+
+		switch h.Type {
+		case chunkStringPool:
+			if table.strings == nil {
+				table.strings, err = parseStringPool(chunk)
+				if err != nil {
+					return nil, err
+				}
+			}
+		case chunkTablePackage:
+			if err := table.parsePackage(chunk, h); err != nil {
+				return nil, err
+			}
+		}
+
+		offset += int(h.Size)
+	}
+
+	return table, nil
+}
+
+func (t *ResourceTable) parsePackage(chunk []byte, h chunkHeader) error {
+	if len(chunk) < 12 {
+		return fmt.Errorf("资源包块过短")
+	}
+	packageID := binary.LittleEndian.Uint32(chunk[8:])
+
+	offset := int(h.HeaderSize)
+	for offset < len(chunk) {
+		sub, err := readChunkHeader(chunk, offset)
+		if err != nil {
+			return err
+		}
+		if sub.Type == chunkTableType {
+			t.parseType(chunk[offset:offset+int(sub.Size)], sub, packageID)
+		}
+		offset += int(sub.Size)
+	}
+	return nil
+}
+
+func (t *ResourceTable) parseType(chunk []byte, h chunkHeader, packageID uint32) {
+	if len(chunk) < 24 {
+		return
+	}
+	typeID := uint32(chunk[8])
+	flags := chunk[9]
+	entryCount := int(binary.LittleEndian.Uint32(chunk[12:]))
+	entriesStart := int(binary.LittleEndian.Uint32(chunk[16:]))
+
+	// 配置结构紧随其后，首个字段为自身大小；除大小字段外全为0即为默认配置
+	defaultConfig := true
+	if len(chunk) >= 24 {
+		configSize := int(binary.LittleEndian.Uint32(chunk[20:]))
+		for i := 24; i < 20+configSize && i < int(h.HeaderSize); i++ {
+			if chunk[i] != 0 {
+				defaultConfig = false
+				break
+			}
+		}
+	}
+
+	indexStart := int(h.HeaderSize)
+	for i := 0; i < entryCount; i++ {
+		var entryIndex, entryOffset uint32
+		switch {
+		case flags&typeFlagSparse != 0:
+			p := indexStart + i*4
+			if p+4 > len(chunk) {
+				return
+			}
+			entryIndex = uint32(binary.LittleEndian.Uint16(chunk[p:]))
+			entryOffset = uint32(binary.LittleEndian.Uint16(chunk[p+2:])) * 4
+		case flags&typeFlagOffset16 != 0:
+			p := indexStart + i*2
+			if p+2 > len(chunk) {
+				return
+			}
+			raw := binary.LittleEndian.Uint16(chunk[p:])
+			if raw == noEntry16 {
+				continue
+			}
+			entryIndex = uint32(i)
+			entryOffset = uint32(raw) * 4
+		default:
+			p := indexStart + i*4
+			if p+4 > len(chunk) {
+				return
+			}
+			entryOffset = binary.LittleEndian.Uint32(chunk[p:])
+			if entryOffset == noEntry {
+				continue
+			}
+			entryIndex = uint32(i)
+		}
+
+		e := entriesStart + int(entryOffset)
+		if e+8 > len(chunk) {
+			continue
+		}
+		entryFlags := binary.LittleEndian.Uint16(chunk[e+2:])
+
+		var value ResValue
+		switch {
+		case entryFlags&entryFlagCompact != 0:
+			value = ResValue{DataType: uint8(entryFlags >> 8), Data: binary.LittleEndian.Uint32(chunk[e+4:])}
+		case entryFlags&entryFlagComplex != 0:
+			// 样式、数组等复合资源不在此解析范围内
+			continue
+		default:
+			size := int(binary.LittleEndian.Uint16(chunk[e:]))
+			v := e + size
+			if v+8 > len(chunk) {
+				continue
+			}
+			value = ResValue{DataType: chunk[v+3], Data: binary.LittleEndian.Uint32(chunk[v+4:])}
+		}
+
+		id := packageID<<24 | typeID<<16 | entryIndex
+		t.entries[id] = append(t.entries[id], tableEntry{value: value, defaultConfig: defaultConfig})
+	}
+}
+
+// Resolve 将资源ID解析为字符串，优先使用默认配置的取值，并跟随引用链
+func (t *ResourceTable) Resolve(id uint32) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	for depth := 0; depth < 8; depth++ {
+		candidates := t.entries[id]
+		if len(candidates) == 0 {
+			return "", false
+		}
+		chosen := candidates[0]
+		for _, c := range candidates {
+			if c.defaultConfig {
+				chosen = c
+				break
+			}
+		}
+		if chosen.value.DataType != typeReference {
+			return chosen.value.format(t.strings), true
+		}
+		id = chosen.value.Data
+	}
+	return "", false
+}
@@ -0,0 +1,94 @@
+package apk
+
+import "testing"
+
+// buildTableType 构造 RES_TABLE_TYPE 块；language 非空时为非默认配置。
+// index 为偏移表（已按 flags 编码），entries 为条目数据
+func buildTableType(typeID uint8, flags uint8, language string, entryCount int, index, entries []byte) []byte {
+	config := make([]byte, 64)
+	copy(config, le32(64))
+	copy(config[8:], language) // imsi 之后为 language[2]
+	headerSize := 8 + 12 + len(config)
+	header := concat([]byte{typeID, flags}, le16(0), le32(uint32(entryCount)), le32(uint32(headerSize+len(index))), config)
+	return buildChunk(chunkTableType, header, concat(index, entries))
+}
+
+// simpleEntry 普通条目：ResTable_entry + Res_value
+func simpleEntry(dataType uint8, data uint32) []byte {
+	return concat(le16(8), le16(0), le32(0), le16(8), []byte{0, dataType}, le32(data))
+}
+
+func buildResourceTable(types ...[]byte) []byte {
+	packageHeader := concat(le32(0x7f), make([]byte, 256), le32(0), le32(0), le32(0), le32(0), le32(0))
+	pkg := buildChunk(chunkTablePackage, packageHeader, concat(types...))
+	pool := buildStringPool(false, "Default App", "中文应用")
+	return buildChunk(chunkTable, le32(1), concat(pool, pkg))
+}
+
+func TestParseResourceTable(t *testing.T) {
+	// type 1：非默认配置在前，条目 1 不存在，条目 2 引用条目 0，条目 3 为复合资源
+	zh := buildTableType(1, 0, "zh", 1, le32(0), simpleEntry(typeString, 1))
+	strEntries := concat(
+		simpleEntry(typeString, 0),
+		simpleEntry(typeReference, 0x7f010000),
+		concat(le16(16), le16(entryFlagComplex), le32(0), le32(0), le32(0)),
+	)
+	def := buildTableType(1, 0, "", 4, concat(le32(0), le32(noEntry), le32(16), le32(32)), strEntries)
+	// type 2：16 位偏移
+	offset16 := buildTableType(2, typeFlagOffset16, "", 2, concat(le16(noEntry16), le16(0)), simpleEntry(typeIntDec, 7))
+	// type 3：稀疏条目，其中条目 5 为紧凑格式
+	sparse := buildTableType(3, typeFlagSparse, "", 2,
+		concat(le16(1), le16(0), le16(5), le16(4)),
+		concat(simpleEntry(typeIntBool, 1), le16(0), le16(entryFlagCompact|typeIntHex<<8), le32(0xff)))
+
+	table, err := ParseResourceTable(buildResourceTable(zh, def, offset16, sparse))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		id   uint32
+		want string
+		ok   bool
+	}{
+		{0x7f010000, "Default App", true},
+		{0x7f010001, "", false},
+		{0x7f010002, "Default App", true},
+		{0x7f010003, "", false},
+		{0x7f020000, "", false},
+		{0x7f020001, "7", true},
+		{0x7f030001, "true", true},
+		{0x7f030005, "0xff", true},
+		{0x01010001, "", false},
+	}
+	for _, tt := range tests {
+		got, ok := table.Resolve(tt.id)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Resolve(0x%08x) = %q, %v; want %q, %v", tt.id, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	var nilTable *ResourceTable
+	if _, ok := nilTable.Resolve(0x7f010000); ok {
+		t.Error("nil 资源表不应解析成功")
+	}
+}
+
+func TestResolveReferenceLoop(t *testing.T) {
+	loop := buildTableType(1, 0, "", 1, le32(0), simpleEntry(typeReference, 0x7f010000))
+	table, err := ParseResourceTable(buildResourceTable(loop))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := table.Resolve(0x7f010000); ok {
+		t.Error("循环引用应解析失败")
+	}
+}
+
+func TestParseResourceTableErrors(t *testing.T) {
+	if _, err := ParseResourceTable(buildChunk(chunkXML, nil, nil)); err == nil {
+		t.Error("非资源表应返回错误")
+	}
+	if _, err := ParseResourceTable([]byte{1, 2}); err == nil {
+		t.Error("过短的数据应返回错误")
+	}
+}
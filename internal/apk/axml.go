@@ -0,0 +1,338 @@
+package apk
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"unicode/utf16"
+)
+
+// 二进制资源块类型（参考 AOSP ResourceTypes.h）
+const (
+	chunkStringPool     = 0x0001
+	chunkTable          = 0x0002
+	chunkXML            = 0x0003
+	chunkXMLStartNS     = 0x0100
+	chunkXMLEndNS       = 0x0101
+	chunkXMLStartElem   = 0x0102
+	chunkXMLEndElem     = 0x0103
+	chunkXMLCData       = 0x0104
+	chunkXMLResourceMap = 0x0180
+	chunkTablePackage   = 0x0200
+	chunkTableType      = 0x0201
+	chunkTableTypeSpec  = 0x0202
+)
+
+// Res_value 数据类型
+const (
+	typeNull      = 0x00
+	typeReference = 0x01
+	typeAttribute = 0x02
+	typeString    = 0x03
+	typeFloat     = 0x04
+	typeIntDec    = 0x10
+	typeIntHex    = 0x11
+	typeIntBool   = 0x12
+)
+
+const stringPoolUTF8Flag = 1 << 8
+
+// 常用 android: 属性的资源ID，用于名称被混淆（空字符串）时回退识别
+var androidAttrNames = map[uint32]string{
+	0x01010001: "label",
+	0x01010003: "name",
+	0x0101020c: "minSdkVersion",
+	0x0101021b: "versionCode",
+	0x0101021c: "versionName",
+	0x01010270: "targetSdkVersion",
+	0x01010271: "maxSdkVersion",
+	0x01010002: "icon",
+	0x01010010: "exported",
+	0x0101000f: "debuggable",
+}
+
+// chunkHeader 资源块通用头部
+type chunkHeader struct {
+	Type       uint16
+	HeaderSize uint16
+	Size       uint32
+}
+
+func readChunkHeader(data []byte, offset int) (chunkHeader, error) {
+	if offset < 0 || offset+8 > len(data) {
+		return chunkHeader{}, fmt.Errorf("资源块头部越界: offset=%d", offset)
+	}
+	h := chunkHeader{
+		Type:       binary.LittleEndian.Uint16(data[offset:]),
+		HeaderSize: binary.LittleEndian.Uint16(data[offset+2:]),
+		Size:       binary.LittleEndian.Uint32(data[offset+4:]),
+	}
+	if h.Size < 8 || offset+int(h.Size) > len(data) {
+		return chunkHeader{}, fmt.Errorf("资源块大小无效: type=0x%04x, size=%d", h.Type, h.Size)
+	}
+	return h, nil
+}
+
+// parseStringPool 解析字符串池块，data 为整个块（含头部）
+func parseStringPool(data []byte) ([]string, error) {
+	if len(data) < 28 {
+		return nil, fmt.Errorf("字符串池过短")
+	}
+	count := int(binary.LittleEndian.Uint32(data[8:]))
+	flags := binary.LittleEndian.Uint32(data[16:])
+	stringsStart := int(binary.LittleEndian.Uint32(data[20:]))
+	headerSize := int(binary.LittleEndian.Uint16(data[2:]))
+
+	if headerSize+count*4 > len(data) {
+		return nil, fmt.Errorf("字符串池偏移表越界: count=%d", count)
+	}
+
+	isUTF8 := flags&stringPoolUTF8Flag != 0
+	pool := make([]string, count)
+	for i := 0; i < count; i++ {
+		offset := stringsStart + int(binary.LittleEndian.Uint32(data[headerSize+i*4:]))
+		if offset >= len(data) {
+			continue
+		}
+		if isUTF8 {
+			pool[i] = decodeUTF8String(data, offset)
+		} else {
+			pool[i] = decodeUTF16String(data, offset)
+		}
+	}
+	return pool, nil
+}
+
+func decodeUTF8String(data []byte, offset int) string {
+	// 先是UTF-16长度，再是UTF-8字节长度，均为1或2字节变长编码
+	_, n := decodeLength8(data, offset)
+	offset += n
+	length, n := decodeLength8(data, offset)
+	offset += n
+	if offset+length > len(data) {
+		return ""
+	}
+	return string(data[offset : offset+length])
+}
+
+func decodeLength8(data []byte, offset int) (int, int) {
+	if offset >= len(data) {
+		return 0, 1
+	}
+	length := int(data[offset])
+	if length&0x80 != 0 && offset+1 < len(data) {
+		return (length&0x7f)<<8 | int(data[offset+1]), 2
+	}
+	return length, 1
+}
+
+func decodeUTF16String(data []byte, offset int) string {
+	if offset+2 > len(data) {
+		return ""
+	}
+	length := int(binary.LittleEndian.Uint16(data[offset:]))
+	offset += 2
+	if length&0x8000 != 0 && offset+2 <= len(data) {
+		length = (length&0x7fff)<<16 | int(binary.LittleEndian.Uint16(data[offset:]))
+		offset += 2
+	}
+	if offset+length*2 > len(data) {
+		return ""
+	}
+	units := make([]uint16, length)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(data[offset+i*2:])
+	}
+	return string(utf16.Decode(units))
+}
+
+// ResValue 表示一个类型化的资源值
+type ResValue struct {
+	DataType uint8
+	Data     uint32
+}
+
+// IsReference 判断该值是否为资源引用（@xxx）
+func (v ResValue) IsReference() bool {
+	return v.DataType == typeReference
+}
+
+// format 将类型化的值格式化为字符串，strings 用于解析字符串类型
+func (v ResValue) format(strings []string) string {
+	switch v.DataType {
+	case typeNull:
+		return ""
+	case typeReference:
+		return fmt.Sprintf("@0x%08x", v.Data)
+	case typeAttribute:
+		return fmt.Sprintf("?0x%08x", v.Data)
+	case typeString:
+		if int(v.Data) < len(strings) {
+			return strings[v.Data]
+		}
+		return ""
+	case typeFloat:
+		return strconv.FormatFloat(float64(math.Float32frombits(v.Data)), 'g', -1, 32)
+	case typeIntDec:
+		return strconv.Itoa(int(int32(v.Data)))
+	case typeIntHex:
+		return fmt.Sprintf("0x%x", v.Data)
+	case typeIntBool:
+		return strconv.FormatBool(v.Data != 0)
+	default:
+		return fmt.Sprintf("0x%08x", v.Data)
+	}
+}
+
+// XMLAttr 二进制XML中的一个属性
+type XMLAttr struct {
+	Namespace  string
+	Name       string
+	ResourceID uint32 // 属性名对应的资源ID（如 android:versionCode 为 0x0101021b）
+	Raw        string // 原始字符串值（可能为空）
+	Value      ResValue
+	Text       string // 格式化后的值
+}
+
+// XMLElement 二进制XML中的元素节点
+type XMLElement struct {
+	Name     string
+	Attrs    []XMLAttr
+	Children []*XMLElement
+}
+
+// Attr 按名称查找属性（忽略命名空间），找不到时返回nil
+func (e *XMLElement) Attr(name string) *XMLAttr {
+	for i := range e.Attrs {
+		if e.Attrs[i].Name == name {
+			return &e.Attrs[i]
+		}
+	}
+	return nil
+}
+
+// AttrString 返回属性的文本值，属性不存在时返回空字符串
+func (e *XMLElement) AttrString(name string) string {
+	if attr := e.Attr(name); attr != nil {
+		return attr.Text
+	}
+	return ""
+}
+
+// Find 返回所有名称匹配的直接子元素
+func (e *XMLElement) Find(name string) []*XMLElement {
+	var result []*XMLElement
+	for _, child := range e.Children {
+		if child.Name == name {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+// ParseAXML 解析Android二进制XML（如编译后的 AndroidManifest.xml），返回根元素
+func ParseAXML(data []byte) (*XMLElement, error) {
+	root, err := readChunkHeader(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	if root.Type != chunkXML {
+		return nil, fmt.Errorf("不是二进制XML文件: type=0x%04x", root.Type)
+	}
+
+	var (
+		pool        []string
+		resourceIDs []uint32
+		stack       []*XMLElement
+		document    *XMLElement
+	)
+
+	str := func(index uint32) string {
+		if index == math.MaxUint32 || int(index) >= len(pool) {
+			return ""
+		}
+		return pool[index]
+	}
+
+	offset := int(root.HeaderSize)
+	for offset < int(root.Size) {
+		h, err := readChunkHeader(data, offset)
+		if err != nil {
+			return nil, err
+		}
+		chunk := data[offset : offset+int(h.Size)]
+
+		switch h.Type {
+		case chunkStringPool:
+			pool, err = parseStringPool(chunk)
+			if err != nil {
+				return nil, err
+			}
+		case chunkXMLResourceMap:
+			for i := int(h.HeaderSize); i+4 <= len(chunk); i += 4 {
+				resourceIDs = append(resourceIDs, binary.LittleEndian.Uint32(chunk[i:]))
+			}
+		case chunkXMLStartElem:
+			ext := int(h.HeaderSize)
+			if ext+20 > len(chunk) {
+				return nil, fmt.Errorf("元素节点过短")
+			}
+			nameIndex := binary.LittleEndian.Uint32(chunk[ext+4:])
+			attrStart := int(binary.LittleEndian.Uint16(chunk[ext+8:]))
+			attrSize := int(binary.LittleEndian.Uint16(chunk[ext+10:]))
+			attrCount := int(binary.LittleEndian.Uint16(chunk[ext+12:]))
+
+			elem := &XMLElement{Name: str(nameIndex)}
+			for i := 0; i < attrCount; i++ {
+				a := ext + attrStart + i*attrSize
+				if a+20 > len(chunk) {
+					break
+				}
+				nameIdx := binary.LittleEndian.Uint32(chunk[a+4:])
+				attr := XMLAttr{
+					Namespace: str(binary.LittleEndian.Uint32(chunk[a:])),
+					Name:      str(nameIdx),
+					Raw:       str(binary.LittleEndian.Uint32(chunk[a+8:])),
+					Value: ResValue{
+						DataType: chunk[a+15],
+						Data:     binary.LittleEndian.Uint32(chunk[a+16:]),
+					},
+				}
+				if int(nameIdx) < len(resourceIDs) {
+					attr.ResourceID = resourceIDs[nameIdx]
+					if known, ok := androidAttrNames[attr.ResourceID]; ok && attr.Name != known {
+						attr.Name = known
+					}
+				}
+				if attr.Raw != "" && attr.Value.DataType == typeString {
+					attr.Text = attr.Raw
+				} else {
+					attr.Text = attr.Value.format(pool)
+				}
+				elem.Attrs = append(elem.Attrs, attr)
+			}
+
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, elem)
+			} else if document == nil {
+				document = elem
+			}
+			stack = append(stack, elem)
+		case chunkXMLEndElem:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case chunkXMLStartNS, chunkXMLEndNS, chunkXMLCData:
+			// 命名空间与文本节点对清单解析无影响，直接跳过
+		}
+
+		offset += int(h.Size)
+	}
+
+	if document == nil {
+		return nil, fmt.Errorf("二进制XML中没有根元素")
+	}
+	return document, nil
+}
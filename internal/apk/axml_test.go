@@ -0,0 +1,193 @@
+package apk
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// 以下辅助函数按 AOSP ResourceTypes.h 的布局手工构造二进制资源块
+
+func le16(v uint16) []byte { return binary.LittleEndian.AppendUint16(nil, v) }
+func le32(v uint32) []byte { return binary.LittleEndian.AppendUint32(nil, v) }
+
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+// buildChunk 构造资源块，header 为通用头部之后的扩展头部
+func buildChunk(typ uint16, header []byte, body []byte) []byte {
+	return concat(le16(typ), le16(uint16(8+len(header))), le32(uint32(8+len(header)+len(body))), header, body)
+}
+
+func encodeLength8(n int) []byte {
+	if n > 0x7f {
+		return []byte{byte(0x80 | n>>8), byte(n)}
+	}
+	return []byte{byte(n)}
+}
+
+func buildStringPool(utf8 bool, values ...string) []byte {
+	var offsets, data []byte
+	for _, s := range values {
+		offsets = append(offsets, le32(uint32(len(data)))...)
+		if utf8 {
+			data = concat(data, encodeLength8(len(utf16.Encode([]rune(s)))), encodeLength8(len(s)), []byte(s), []byte{0})
+		} else {
+			units := utf16.Encode([]rune(s))
+			data = append(data, le16(uint16(len(units)))...)
+			for _, u := range units {
+				data = append(data, le16(u)...)
+			}
+			data = append(data, 0, 0)
+		}
+	}
+	for len(data)%4 != 0 {
+		data = append(data, 0)
+	}
+	var flags uint32
+	if utf8 {
+		flags = stringPoolUTF8Flag
+	}
+	header := concat(le32(uint32(len(values))), le32(0), le32(flags), le32(uint32(28+len(offsets))), le32(0))
+	return buildChunk(chunkStringPool, header, concat(offsets, data))
+}
+
+type testAttr struct {
+	ns, name, raw uint32
+	dataType      uint8
+	data          uint32
+}
+
+func buildStartElement(name uint32, attrs ...testAttr) []byte {
+	body := concat(le32(math.MaxUint32), le32(name), le16(20), le16(20), le16(uint16(len(attrs))), le16(0), le16(0), le16(0))
+	for _, a := range attrs {
+		body = concat(body, le32(a.ns), le32(a.name), le32(a.raw), le16(8), []byte{0, a.dataType}, le32(a.data))
+	}
+	return buildChunk(chunkXMLStartElem, concat(le32(1), le32(math.MaxUint32)), body)
+}
+
+func buildEndElement(name uint32) []byte {
+	return buildChunk(chunkXMLEndElem, concat(le32(1), le32(math.MaxUint32)), concat(le32(math.MaxUint32), le32(name)))
+}
+
+func TestParseStringPool(t *testing.T) {
+	long := strings.Repeat("长", 200) // UTF-8 字节长度超过 0x7f，需要两字节长度编码
+	values := []string{"", "manifest", "中文", long}
+	for _, utf8 := range []bool{true, false} {
+		got, err := parseStringPool(buildStringPool(utf8, values...))
+		if err != nil {
+			t.Fatalf("utf8=%v: %v", utf8, err)
+		}
+		if !reflect.DeepEqual(got, values) {
+			t.Errorf("utf8=%v: got %q", utf8, got)
+		}
+	}
+	if _, err := parseStringPool(make([]byte, 10)); err == nil {
+		t.Error("过短的字符串池应返回错误")
+	}
+}
+
+func TestResValueFormat(t *testing.T) {
+	pool := []string{"hello"}
+	tests := []struct {
+		value ResValue
+		want  string
+	}{
+		{ResValue{typeNull, 0}, ""},
+		{ResValue{typeReference, 0x7f010000}, "@0x7f010000"},
+		{ResValue{typeAttribute, 0x01010000}, "?0x01010000"},
+		{ResValue{typeString, 0}, "hello"},
+		{ResValue{typeString, 5}, ""},
+		{ResValue{typeFloat, math.Float32bits(1.5)}, "1.5"},
+		{ResValue{typeIntDec, 42}, "42"},
+		{ResValue{typeIntDec, math.MaxUint32}, "-1"},
+		{ResValue{typeIntHex, 255}, "0xff"},
+		{ResValue{typeIntBool, math.MaxUint32}, "true"},
+		{ResValue{typeIntBool, 0}, "false"},
+		{ResValue{0x1c, 0xff000000}, "0xff000000"},
+	}
+	for _, tt := range tests {
+		if got := tt.value.format(pool); got != tt.want {
+			t.Errorf("%+v.format() = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseAXML(t *testing.T) {
+	const (
+		sVersionCode = iota
+		sLabel       // 名称被混淆为空字符串，依靠资源ID识别
+		sDebuggable
+		sManifest
+		sPackage
+		sComExample
+		sApplication
+		sAndroidNS
+	)
+	none := uint32(math.MaxUint32)
+	pool := buildStringPool(true, "versionCode", "", "debuggable", "manifest", "package", "com.example", "application",
+		"http://schemas.android.com/apk/res/android")
+	resourceMap := buildChunk(chunkXMLResourceMap, nil, concat(le32(0x0101021b), le32(0x01010001), le32(0x0101000f)))
+
+	body := concat(
+		pool,
+		resourceMap,
+		buildStartElement(sManifest,
+			testAttr{none, sPackage, sComExample, typeString, sComExample},
+			testAttr{sAndroidNS, sVersionCode, none, typeIntDec, 42},
+		),
+		buildStartElement(sApplication,
+			testAttr{sAndroidNS, sLabel, none, typeReference, 0x7f010000},
+			testAttr{sAndroidNS, sDebuggable, none, typeIntBool, math.MaxUint32},
+		),
+		buildEndElement(sApplication),
+		buildEndElement(sManifest),
+	)
+	root, err := ParseAXML(buildChunk(chunkXML, nil, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if root.Name != "manifest" || root.AttrString("package") != "com.example" || root.AttrString("versionCode") != "42" {
+		t.Errorf("manifest = %+v", root)
+	}
+	if attr := root.Attr("versionCode"); attr == nil || attr.ResourceID != 0x0101021b || attr.Namespace != "http://schemas.android.com/apk/res/android" {
+		t.Errorf("versionCode attr = %+v", attr)
+	}
+	apps := root.Find("application")
+	if len(apps) != 1 {
+		t.Fatalf("application count = %d", len(apps))
+	}
+	label := apps[0].Attr("label")
+	if label == nil || !label.Value.IsReference() || label.Text != "@0x7f010000" {
+		t.Errorf("label attr = %+v", label)
+	}
+	if got := apps[0].AttrString("debuggable"); got != "true" {
+		t.Errorf("debuggable = %q", got)
+	}
+	if apps[0].Attr("missing") != nil || root.Find("activity") != nil {
+		t.Error("不存在的属性或子元素应返回空")
+	}
+}
+
+func TestParseAXMLErrors(t *testing.T) {
+	tests := map[string][]byte{
+		"空数据":   nil,
+		"不是XML": buildChunk(chunkTable, le32(0), nil),
+		"没有根元素": buildChunk(chunkXML, nil, buildStringPool(true, "a")),
+		"块大小越界": concat(le16(chunkXML), le16(8), le32(1000)),
+	}
+	for name, data := range tests {
+		if _, err := ParseAXML(data); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
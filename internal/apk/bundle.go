@@ -0,0 +1,443 @@
+package apk
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// 安装包格式
+const (
+	FormatAPK  = "apk"
+	FormatAPKS = "apks"
+	FormatXAPK = "xapk"
+)
+
+// 拆分包类型
+const (
+	SplitBase     = "base"
+	SplitFeature  = "feature"
+	SplitABI      = "abi"
+	SplitDensity  = "density"
+	SplitLanguage = "language"
+)
+
+// densityBuckets 屏幕密度配置名与dpi的对应关系
+var densityBuckets = map[string]int{
+	"ldpi":    120,
+	"mdpi":    160,
+	"tvdpi":   213,
+	"hdpi":    240,
+	"xhdpi":   320,
+	"xxhdpi":  480,
+	"xxxhdpi": 640,
+}
+
+// knownABIs 拆分包中可能出现的ABI名称（"-" 在拆分名中会被写成 "_"）
+var knownABIs = map[string]string{
+	"armeabi":     "armeabi",
+	"armeabi_v7a": "armeabi-v7a",
+	"arm64_v8a":   "arm64-v8a",
+	"x86":         "x86",
+	"x86_64":      "x86_64",
+	"mips":        "mips",
+	"mips64":      "mips64",
+	"riscv64":     "riscv64",
+}
+
+// Split 安装包中的一个APK
+type Split struct {
+	Name   string // 拆分名，基础包为空
+	File   string // 解压后的本地路径
+	Kind   string // SplitBase / SplitFeature / SplitABI / SplitDensity / SplitLanguage
+	Value  string // ABI名、密度名或语言代码
+	Module string // 配置拆分所属的模块，基础模块为空
+}
+
+// Expansion XAPK中的OBB扩展文件
+type Expansion struct {
+	File       string // 解压后的本地路径
+	DevicePath string // 设备上的目标路径（相对于外部存储）
+}
+
+// Bundle 表示一个待安装的应用，可能由多个拆分APK组成
+type Bundle struct {
+	Path        string
+	Format      string
+	PackageName string
+	VersionCode int64
+	Splits      []Split
+	Expansions  []Expansion
+
+	tempDir string
+}
+
+// DeviceSpec 目标设备的配置，用于挑选拆分包
+type DeviceSpec struct {
+	ABIs    []string // 按优先级排列，如 arm64-v8a, armeabi-v7a
+	Density int      // dpi
+	Locales []string // 如 zh-CN, en-US
+	SDK     int
+}
+
+// expansionDir OBB 扩展文件在外部存储上的目录
+const expansionDir = "Android/obb/"
+
+// xapkManifest XAPK 根目录下 manifest.json 的相关字段
+type xapkManifest struct {
+	Expansions []struct {
+		File        string `json:"file"`
+		InstallPath string `json:"install_path"`
+	} `json:"expansions"`
+}
+
+// OpenBundle 打开 .apk / .apks / .xapk 文件
+// .apks 与 .xapk 中的APK会被解压到临时目录，使用完毕后需调用 Close 清理
+func OpenBundle(filePath string) (*Bundle, error) {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	bundle := &Bundle{Path: filePath}
+
+	switch ext {
+	case ".apk":
+		bundle.Format = FormatAPK
+		split, err := describeSplit(filePath)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Splits = []Split{split}
+	case ".apks", ".xapk":
+		bundle.Format = FormatAPKS
+		if ext == ".xapk" {
+			bundle.Format = FormatXAPK
+		}
+		if err := bundle.extract(); err != nil {
+			bundle.Close()
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("不支持的安装包格式: %s", ext)
+	}
+
+	if info, err := Analyze(bundle.baseFile()); err == nil {
+		bundle.PackageName = info.PackageName
+		bundle.VersionCode = info.VersionCode
+	}
+
+	log.Printf("已打开安装包: %s, 格式=%s, 拆分数=%d, OBB数=%d", filePath, bundle.Format, len(bundle.Splits), len(bundle.Expansions))
+	return bundle, nil
+}
+
+// Close 删除解压产生的临时文件
+func (b *Bundle) Close() {
+	if b != nil && b.tempDir != "" {
+		os.RemoveAll(b.tempDir)
+		b.tempDir = ""
+	}
+}
+
+func (b *Bundle) baseFile() string {
+	for _, split := range b.Splits {
+		if split.Kind == SplitBase {
+			return split.File
+		}
+	}
+	if len(b.Splits) > 0 {
+		return b.Splits[0].File
+	}
+	return ""
+}
+
+func (b *Bundle) extract() error {
+	reader, err := zip.OpenReader(b.Path)
+	if err != nil {
+		return fmt.Errorf("无法作为zip打开: %w", err)
+	}
+	defer reader.Close()
+
+	b.tempDir, err = os.MkdirTemp("", "yikong-bundle-*")
+	if err != nil {
+		return err
+	}
+
+	var manifest xapkManifest
+	if data, err := readZipEntry(&reader.Reader, "manifest.json"); err == nil {
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			log.Printf("解析XAPK manifest.json失败: %v", err)
+		}
+	}
+
+	expansionTargets := make(map[string]string)
+	for _, e := range manifest.Expansions {
+		target, err := cleanExpansionPath(e.InstallPath)
+		if err != nil {
+			return err
+		}
+		expansionTargets[e.File] = target
+	}
+
+	var standalones []string
+	for i, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		if target, ok := expansionTargets[f.Name]; ok {
+			local, err := extractZipFile(f, b.tempDir, fmt.Sprintf("obb_%d_%s", i, path.Base(f.Name)))
+			if err != nil {
+				return err
+			}
+			b.Expansions = append(b.Expansions, Expansion{File: local, DevicePath: target})
+			continue
+		}
+
+		if !strings.HasSuffix(strings.ToLower(f.Name), ".apk") {
+			continue
+		}
+
+		local, err := extractZipFile(f, b.tempDir, fmt.Sprintf("%d_%s", i, path.Base(f.Name)))
+		if err != nil {
+			return err
+		}
+
+		// bundletool 的 standalones/ 目录只用于不支持拆分安装的旧设备
+		if strings.HasPrefix(f.Name, "standalones/") {
+			standalones = append(standalones, local)
+			continue
+		}
+
+		split, err := describeSplit(local)
+		if err != nil {
+			log.Printf("跳过无法解析的APK %s: %v", f.Name, err)
+			continue
+		}
+		b.Splits = append(b.Splits, split)
+	}
+
+	if len(b.Splits) == 0 && len(standalones) > 0 {
+		split, err := describeSplit(standalones[0])
+		if err != nil {
+			return err
+		}
+		b.Splits = append(b.Splits, split)
+	}
+	if len(b.Splits) == 0 {
+		return fmt.Errorf("安装包中没有找到APK")
+	}
+
+	sort.SliceStable(b.Splits, func(i, j int) bool {
+		return b.Splits[i].Kind == SplitBase && b.Splits[j].Kind != SplitBase
+	})
+	return nil
+}
+
+// cleanExpansionPath 校验 manifest.json 中 OBB 的 install_path（相对外部存储），
+// 该值来自安装包本身，只允许落在 Android/obb/ 之下
+func cleanExpansionPath(installPath string) (string, error) {
+	invalid := fmt.Errorf("XAPK 中的 OBB 安装路径无效: %q", installPath)
+	if installPath == "" || strings.HasPrefix(installPath, "/") || strings.ContainsAny(installPath, "\\\x00\n") {
+		return "", invalid
+	}
+	for _, segment := range strings.Split(installPath, "/") {
+		if segment == ".." {
+			return "", invalid
+		}
+	}
+	cleaned := path.Clean(installPath)
+	if !strings.HasPrefix(cleaned, expansionDir) || len(cleaned) == len(expansionDir) {
+		return "", invalid
+	}
+	return cleaned, nil
+}
+
+func extractZipFile(f *zip.File, dir string, name string) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	local := filepath.Join(dir, name)
+	out, err := os.Create(local)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, rc); err != nil {
+		return "", err
+	}
+	return local, nil
+}
+
+// describeSplit 读取APK清单中的 split 属性确定拆分类型
+func describeSplit(file string) (Split, error) {
+	reader, err := zip.OpenReader(file)
+	if err != nil {
+		return Split{}, err
+	}
+	defer reader.Close()
+
+	data, err := readZipEntry(&reader.Reader, "AndroidManifest.xml")
+	if err != nil {
+		return Split{}, err
+	}
+	manifest, err := ParseAXML(data)
+	if err != nil {
+		return Split{}, err
+	}
+
+	split := classifySplit(manifest.AttrString("split"))
+	split.File = file
+	return split, nil
+}
+
+// classifySplit 根据拆分名判断类型，如 config.arm64_v8a、config.xxhdpi、feature.config.zh
+func classifySplit(name string) Split {
+	if name == "" {
+		return Split{Kind: SplitBase}
+	}
+
+	idx := strings.LastIndex(name, "config.")
+	if idx < 0 {
+		return Split{Name: name, Kind: SplitFeature, Value: name}
+	}
+	module := strings.TrimSuffix(name[:idx], ".")
+	qualifier := name[idx+len("config."):]
+
+	if abi, ok := knownABIs[qualifier]; ok {
+		return Split{Name: name, Kind: SplitABI, Value: abi, Module: module}
+	}
+	if _, ok := densityBuckets[qualifier]; ok {
+		return Split{Name: name, Kind: SplitDensity, Value: qualifier, Module: module}
+	}
+	return Split{Name: name, Kind: SplitLanguage, Value: qualifier, Module: module}
+}
+
+// localeLanguage 取语言代码部分，如 zh-CN -> zh
+func localeLanguage(locale string) string {
+	lang, _, _ := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	return strings.ToLower(lang)
+}
+
+// SelectSplits 按设备配置挑选需要安装的拆分包：
+// 基础包与功能模块全部安装；每个模块的配置拆分中，ABI取设备最优先支持的一个，
+// 密度取最接近的一个，语言取与设备语言匹配的（设备语言未知时全部安装）
+func (b *Bundle) SelectSplits(spec DeviceSpec) ([]Split, error) {
+	languages := make(map[string]bool)
+	for _, locale := range spec.Locales {
+		if lang := localeLanguage(locale); lang != "" {
+			languages[lang] = true
+		}
+	}
+
+	var (
+		selected      []Split
+		modules       []string
+		abiSplits     = make(map[string]map[string]Split)
+		densitySplits = make(map[string][]Split)
+	)
+	addModule := func(module string) {
+		if _, ok := abiSplits[module]; !ok {
+			abiSplits[module] = make(map[string]Split)
+			modules = append(modules, module)
+		}
+	}
+
+	for _, split := range b.Splits {
+		switch split.Kind {
+		case SplitBase, SplitFeature:
+			selected = append(selected, split)
+		case SplitABI:
+			addModule(split.Module)
+			abiSplits[split.Module][split.Value] = split
+		case SplitDensity:
+			addModule(split.Module)
+			densitySplits[split.Module] = append(densitySplits[split.Module], split)
+		case SplitLanguage:
+			if len(languages) == 0 || languages[localeLanguage(split.Value)] {
+				selected = append(selected, split)
+			}
+		}
+	}
+
+	for _, module := range modules {
+		if len(abiSplits[module]) > 0 {
+			split, err := preferredABI(abiSplits[module], spec.ABIs)
+			if err != nil {
+				return nil, err
+			}
+			selected = append(selected, split)
+		}
+		if split, ok := closestDensity(densitySplits[module], spec.Density); ok {
+			selected = append(selected, split)
+		}
+	}
+
+	return selected, nil
+}
+
+// preferredABI 按设备ABI优先级选择第一个可用的ABI拆分
+func preferredABI(splits map[string]Split, deviceABIs []string) (Split, error) {
+	for _, abi := range deviceABIs {
+		if split, ok := splits[abi]; ok {
+			return split, nil
+		}
+	}
+	available := make([]string, 0, len(splits))
+	for abi := range splits {
+		available = append(available, abi)
+	}
+	sort.Strings(available)
+	return Split{}, fmt.Errorf("安装包不支持设备的ABI %v，可用ABI: %v", deviceABIs, available)
+}
+
+// closestDensity 选择不小于设备密度的最小密度包，都小于时选择最大的
+func closestDensity(splits []Split, density int) (Split, bool) {
+	if len(splits) == 0 {
+		return Split{}, false
+	}
+	sort.Slice(splits, func(i, j int) bool {
+		return densityBuckets[splits[i].Value] < densityBuckets[splits[j].Value]
+	})
+	for _, split := range splits {
+		if densityBuckets[split.Value] >= density {
+			return split, true
+		}
+	}
+	return splits[len(splits)-1], true
+}
+
+// GroupInstallFiles 将多个待安装文件按应用分组：
+// .apks/.xapk 各自单独一组；包名相同的 .apk 视为同一应用的拆分包归为一组
+func GroupInstallFiles(files []string) [][]string {
+	var groups [][]string
+	packageGroup := make(map[string]int)
+
+	for _, f := range files {
+		if !strings.EqualFold(filepath.Ext(f), ".apk") {
+			groups = append(groups, []string{f})
+			continue
+		}
+
+		info, err := Analyze(f)
+		if err != nil || info.PackageName == "" {
+			// 无法解析的文件单独安装，由安装命令给出具体错误
+			groups = append(groups, []string{f})
+			continue
+		}
+		if idx, ok := packageGroup[info.PackageName]; ok {
+			groups[idx] = append(groups[idx], f)
+			continue
+		}
+		packageGroup[info.PackageName] = len(groups)
+		groups = append(groups, []string{f})
+	}
+	return groups
+}
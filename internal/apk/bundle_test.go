@@ -0,0 +1,113 @@
+package apk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClassifySplit(t *testing.T) {
+	tests := []struct {
+		name string
+		want Split
+	}{
+		{"", Split{Kind: SplitBase}},
+		{"config.arm64_v8a", Split{Name: "config.arm64_v8a", Kind: SplitABI, Value: "arm64-v8a"}},
+		{"config.xxhdpi", Split{Name: "config.xxhdpi", Kind: SplitDensity, Value: "xxhdpi"}},
+		{"config.zh", Split{Name: "config.zh", Kind: SplitLanguage, Value: "zh"}},
+		{"camera.config.x86_64", Split{Name: "camera.config.x86_64", Kind: SplitABI, Value: "x86_64", Module: "camera"}},
+		{"camera", Split{Name: "camera", Kind: SplitFeature, Value: "camera"}},
+	}
+	for _, tt := range tests {
+		if got := classifySplit(tt.name); got != tt.want {
+			t.Errorf("classifySplit(%q) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSelectSplits(t *testing.T) {
+	bundle := &Bundle{Splits: []Split{
+		classifySplit(""),
+		classifySplit("config.arm64_v8a"),
+		classifySplit("config.armeabi_v7a"),
+		classifySplit("config.x86_64"),
+		classifySplit("config.mdpi"),
+		classifySplit("config.xhdpi"),
+		classifySplit("config.xxhdpi"),
+		classifySplit("config.zh"),
+		classifySplit("config.en"),
+		classifySplit("camera"),
+		classifySplit("camera.config.armeabi_v7a"),
+	}}
+	names := func(splits []Split) []string {
+		var out []string
+		for _, s := range splits {
+			out = append(out, s.Name)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name    string
+		spec    DeviceSpec
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "arm64 高密度中文设备",
+			spec: DeviceSpec{ABIs: []string{"arm64-v8a", "armeabi-v7a"}, Density: 440, Locales: []string{"zh-CN"}},
+			want: []string{"", "config.zh", "camera", "config.arm64_v8a", "config.xxhdpi", "camera.config.armeabi_v7a"},
+		},
+		{
+			name: "密度高于所有拆分时取最大，语言未知时全部安装",
+			spec: DeviceSpec{ABIs: []string{"armeabi-v7a"}, Density: 640},
+			want: []string{"", "config.zh", "config.en", "camera", "config.armeabi_v7a", "config.xxhdpi", "camera.config.armeabi_v7a"},
+		},
+		{
+			name: "语言代码带下划线",
+			spec: DeviceSpec{ABIs: []string{"armeabi-v7a"}, Density: 160, Locales: []string{"en_US"}},
+			want: []string{"", "config.en", "camera", "config.armeabi_v7a", "config.mdpi", "camera.config.armeabi_v7a"},
+		},
+		{
+			name:    "不支持设备 ABI",
+			spec:    DeviceSpec{ABIs: []string{"riscv64"}, Density: 320},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bundle.SelectSplits(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(names(got), tt.want) {
+				t.Errorf("got  %q\nwant %q", names(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestCleanExpansionPath(t *testing.T) {
+	tests := []struct {
+		installPath string
+		want        string
+		wantErr     bool
+	}{
+		{"Android/obb/com.example/main.1.com.example.obb", "Android/obb/com.example/main.1.com.example.obb", false},
+		{"Android/obb/com.example//./patch.2.com.example.obb", "Android/obb/com.example/patch.2.com.example.obb", false},
+		{"Android/obb/com.example/my file's.obb", "Android/obb/com.example/my file's.obb", false},
+		{"", "", true},
+		{"/sdcard/Android/obb/x.obb", "", true},
+		{"Android/obb/../../data/local/tmp/x", "", true},
+		{"Android/obb/com.example/../x.obb", "", true},
+		{"Android/data/com.example/x.obb", "", true},
+		{"Android/obb/", "", true},
+		{"Android/obb", "", true},
+		{`Android\obb\x.obb`, "", true},
+	}
+	for _, tt := range tests {
+		got, err := cleanExpansionPath(tt.installPath)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("cleanExpansionPath(%q) = %q, %v; want %q, wantErr %v", tt.installPath, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
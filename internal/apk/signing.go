@@ -0,0 +1,310 @@
+package apk
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// 签名方案
+const (
+	SchemeV1  = "v1"
+	SchemeV2  = "v2"
+	SchemeV3  = "v3"
+	SchemeV31 = "v3.1"
+)
+
+// APK Signing Block 中各方案对应的ID
+const (
+	blockIDV2  = 0x7109871a
+	blockIDV3  = 0xf05368c0
+	blockIDV31 = 0x1b93ad61
+)
+
+const apkSigBlockMagic = "APK Sig Block 42"
+
+// Certificate 签名证书摘要信息
+//
+// 注意：这里只提取证书并计算指纹，不校验签名本身是否有效。
+type Certificate struct {
+	Scheme    string
+	Subject   string
+	Issuer    string
+	NotBefore string
+	NotAfter  string
+	MD5       string
+	SHA1      string
+	SHA256    string
+}
+
+func newCertificate(scheme string, der []byte) (Certificate, error) {
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return Certificate{}, err
+	}
+	md5Sum := md5.Sum(der)
+	sha1Sum := sha1.Sum(der)
+	sha256Sum := sha256.Sum256(der)
+	return Certificate{
+		Scheme:    scheme,
+		Subject:   cert.Subject.String(),
+		Issuer:    cert.Issuer.String(),
+		NotBefore: cert.NotBefore.Format("2006-01-02"),
+		NotAfter:  cert.NotAfter.Format("2006-01-02"),
+		MD5:       formatFingerprint(md5Sum[:]),
+		SHA1:      formatFingerprint(sha1Sum[:]),
+		SHA256:    formatFingerprint(sha256Sum[:]),
+	}, nil
+}
+
+// formatFingerprint 以 AA:BB:CC 的形式输出指纹，与 keytool/apksigner 一致
+func formatFingerprint(sum []byte) string {
+	encoded := strings.ToUpper(hex.EncodeToString(sum))
+	parts := make([]string, 0, len(sum))
+	for i := 0; i < len(encoded); i += 2 {
+		parts = append(parts, encoded[i:i+2])
+	}
+	return strings.Join(parts, ":")
+}
+
+// readV1Certificates 从 META-INF 下的 PKCS#7 签名文件中提取证书
+func readV1Certificates(files []*zip.File) ([]Certificate, error) {
+	var certs []Certificate
+	for _, f := range files {
+		dir, name := path.Split(f.Name)
+		if !strings.EqualFold(dir, "META-INF/") {
+			continue
+		}
+		ext := strings.ToUpper(path.Ext(name))
+		if ext != ".RSA" && ext != ".DSA" && ext != ".EC" {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return certs, err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return certs, err
+		}
+
+		ders, err := pkcs7Certificates(data)
+		if err != nil {
+			return certs, fmt.Errorf("解析 %s 失败: %w", f.Name, err)
+		}
+		for _, der := range ders {
+			cert, err := newCertificate(SchemeV1, der)
+			if err != nil {
+				return certs, err
+			}
+			certs = append(certs, cert)
+		}
+	}
+	return certs, nil
+}
+
+// pkcs7Certificates 从 PKCS#7 SignedData 中取出 certificates 字段里的每个证书
+func pkcs7Certificates(data []byte) ([][]byte, error) {
+	var contentInfo struct {
+		ContentType asn1.ObjectIdentifier
+		Content     asn1.RawValue `asn1:"tag:0"`
+	}
+	if _, err := asn1.Unmarshal(data, &contentInfo); err != nil {
+		return nil, err
+	}
+
+	// SignedData ::= SEQUENCE { version, digestAlgorithms, contentInfo,
+	//   certificates [0] IMPLICIT OPTIONAL, crls [1] IMPLICIT OPTIONAL, signerInfos }
+	var signedData asn1.RawValue
+	if _, err := asn1.Unmarshal(contentInfo.Content.Bytes, &signedData); err != nil {
+		return nil, err
+	}
+
+	var certificates []byte
+	fields := signedData.Bytes
+	for len(fields) > 0 {
+		var field asn1.RawValue
+		var err error
+		fields, err = asn1.Unmarshal(fields, &field)
+		if err != nil {
+			return nil, err
+		}
+		if field.Class == asn1.ClassContextSpecific && field.Tag == 0 {
+			certificates = field.Bytes
+			break
+		}
+	}
+
+	var ders [][]byte
+	rest := certificates
+	for len(rest) > 0 {
+		var raw asn1.RawValue
+		var err error
+		rest, err = asn1.Unmarshal(rest, &raw)
+		if err != nil {
+			return ders, err
+		}
+		ders = append(ders, raw.FullBytes)
+	}
+	return ders, nil
+}
+
+// readSigningBlockCertificates 读取 APK Signing Block 中 v2/v3/v3.1 方案的证书
+func readSigningBlockCertificates(apkPath string) ([]Certificate, error) {
+	f, err := os.Open(apkPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	block, err := findSigningBlock(f)
+	if err != nil || block == nil {
+		return nil, err
+	}
+
+	var certs []Certificate
+	for len(block) >= 12 {
+		pairLen := binary.LittleEndian.Uint64(block)
+		if pairLen < 4 || uint64(len(block)-8) < pairLen {
+			return certs, fmt.Errorf("签名块格式错误")
+		}
+		id := binary.LittleEndian.Uint32(block[8:])
+		value := block[12 : 8+pairLen]
+		block = block[8+pairLen:]
+
+		var scheme string
+		switch id {
+		case blockIDV2:
+			scheme = SchemeV2
+		case blockIDV3:
+			scheme = SchemeV3
+		case blockIDV31:
+			scheme = SchemeV31
+		default:
+			continue
+		}
+
+		found, err := parseSchemeSigners(scheme, value)
+		if err != nil {
+			return certs, fmt.Errorf("解析%s签名失败: %w", scheme, err)
+		}
+		certs = append(certs, found...)
+	}
+	return certs, nil
+}
+
+// findSigningBlock 通过中央目录定位 APK Signing Block，返回其中的 ID-值 对区域
+func findSigningBlock(f *os.File) ([]byte, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+
+	// 结束记录最少22字节，注释最长65535字节
+	searchLen := int64(22 + 65535)
+	if searchLen > size {
+		searchLen = size
+	}
+	tail := make([]byte, searchLen)
+	if _, err := f.ReadAt(tail, size-searchLen); err != nil {
+		return nil, err
+	}
+	eocd := bytes.LastIndex(tail, []byte{0x50, 0x4b, 0x05, 0x06})
+	if eocd < 0 || eocd+22 > len(tail) {
+		return nil, fmt.Errorf("未找到ZIP中央目录结束记录")
+	}
+	cdOffset := int64(binary.LittleEndian.Uint32(tail[eocd+16:]))
+	if cdOffset < 32 {
+		return nil, nil
+	}
+
+	footer := make([]byte, 24)
+	if _, err := f.ReadAt(footer, cdOffset-24); err != nil {
+		return nil, err
+	}
+	if string(footer[8:]) != apkSigBlockMagic {
+		return nil, nil
+	}
+
+	blockSize := int64(binary.LittleEndian.Uint64(footer))
+	start := cdOffset - blockSize - 8
+	if blockSize < 24 || start < 0 {
+		return nil, fmt.Errorf("签名块大小无效: %d", blockSize)
+	}
+
+	// 块结构: size(8) + pairs + size(8) + magic(16)
+	pairs := make([]byte, blockSize-24)
+	if _, err := f.ReadAt(pairs, start+8); err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
+// readLengthPrefixed 读取一个以 uint32 长度为前缀的字段
+func readLengthPrefixed(data []byte) (value, rest []byte, err error) {
+	if len(data) < 4 {
+		return nil, nil, fmt.Errorf("长度前缀不完整")
+	}
+	n := binary.LittleEndian.Uint32(data)
+	if uint64(len(data)-4) < uint64(n) {
+		return nil, nil, fmt.Errorf("长度前缀越界: %d", n)
+	}
+	return data[4 : 4+n], data[4+n:], nil
+}
+
+// parseSchemeSigners 解析 v2/v3 签名方案块：signers -> signer -> signed data -> certificates
+func parseSchemeSigners(scheme string, value []byte) ([]Certificate, error) {
+	signers, _, err := readLengthPrefixed(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []Certificate
+	for len(signers) > 0 {
+		var signer []byte
+		signer, signers, err = readLengthPrefixed(signers)
+		if err != nil {
+			return certs, err
+		}
+		signedData, _, err := readLengthPrefixed(signer)
+		if err != nil {
+			return certs, err
+		}
+		// signed data: digests, certificates, ...
+		_, afterDigests, err := readLengthPrefixed(signedData)
+		if err != nil {
+			return certs, err
+		}
+		certList, _, err := readLengthPrefixed(afterDigests)
+		if err != nil {
+			return certs, err
+		}
+		for len(certList) > 0 {
+			var der []byte
+			der, certList, err = readLengthPrefixed(certList)
+			if err != nil {
+				return certs, err
+			}
+			cert, err := newCertificate(scheme, der)
+			if err != nil {
+				return certs, err
+			}
+			certs = append(certs, cert)
+		}
+	}
+	return certs, nil
+}
@@ -0,0 +1,222 @@
+package apk
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestCertificate(t *testing.T, cn string) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2054, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+// lengthPrefixed 以 uint32 长度为前缀拼接字段
+func lengthPrefixed(parts ...[]byte) []byte {
+	var out []byte
+	for _, p := range parts {
+		out = binary.LittleEndian.AppendUint32(out, uint32(len(p)))
+		out = append(out, p...)
+	}
+	return out
+}
+
+// schemeBlock 构造 v2/v3 方案块：signers -> signer -> signed data(digests, certificates)
+func schemeBlock(ders ...[]byte) []byte {
+	signedData := concat(lengthPrefixed(nil), lengthPrefixed(lengthPrefixed(ders...)))
+	signer := lengthPrefixed(signedData)
+	return lengthPrefixed(lengthPrefixed(signer))
+}
+
+func TestFormatFingerprint(t *testing.T) {
+	if got := formatFingerprint([]byte{0x0a, 0xbc, 0xff}); got != "0A:BC:FF" {
+		t.Errorf("got %q", got)
+	}
+	if got := formatFingerprint(nil); got != "" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestReadLengthPrefixed(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []byte
+		value     []byte
+		rest      []byte
+		wantError bool
+	}{
+		{"正常", []byte{2, 0, 0, 0, 'a', 'b', 'c'}, []byte("ab"), []byte("c"), false},
+		{"空值", []byte{0, 0, 0, 0}, []byte{}, []byte{}, false},
+		{"长度不完整", []byte{1, 0}, nil, nil, true},
+		{"长度越界", []byte{5, 0, 0, 0, 'a'}, nil, nil, true},
+		{"长度溢出", []byte{0xff, 0xff, 0xff, 0xff}, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, rest, err := readLengthPrefixed(tt.data)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("err = %v, wantError %v", err, tt.wantError)
+			}
+			if !tt.wantError && (!bytes.Equal(value, tt.value) || !bytes.Equal(rest, tt.rest)) {
+				t.Errorf("got %q, %q", value, rest)
+			}
+		})
+	}
+}
+
+func TestParseSchemeSigners(t *testing.T) {
+	first, second := newTestCertificate(t, "first"), newTestCertificate(t, "second")
+	certs, err := parseSchemeSigners(SchemeV2, schemeBlock(first, second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) != 2 || certs[0].Subject != "CN=first" || certs[1].Subject != "CN=second" {
+		t.Fatalf("certs = %+v", certs)
+	}
+	c := certs[0]
+	if c.Scheme != SchemeV2 || c.NotBefore != "2024-01-01" || c.NotAfter != "2054-01-01" || len(c.SHA256) != 32*3-1 {
+		t.Errorf("cert = %+v", c)
+	}
+
+	if _, err := parseSchemeSigners(SchemeV2, []byte{8, 0, 0, 0, 1}); err == nil {
+		t.Error("截断的方案块应返回错误")
+	}
+	if _, err := parseSchemeSigners(SchemeV2, schemeBlock([]byte("not a certificate"))); err == nil {
+		t.Error("无效证书应返回错误")
+	}
+}
+
+func TestPKCS7Certificates(t *testing.T) {
+	der := newTestCertificate(t, "v1")
+	set := asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true}
+	signedData, err := asn1.Marshal(struct {
+		Version      int
+		Digests      asn1.RawValue
+		ContentInfo  struct{ Type asn1.ObjectIdentifier }
+		Certificates asn1.RawValue
+		SignerInfos  asn1.RawValue
+	}{
+		Version:      1,
+		Digests:      set,
+		ContentInfo:  struct{ Type asn1.ObjectIdentifier }{asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}},
+		Certificates: asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: der},
+		SignerInfos:  set,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := asn1.Marshal(struct {
+		ContentType asn1.ObjectIdentifier
+		Content     asn1.RawValue
+	}{
+		ContentType: asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2},
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signedData},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ders, err := pkcs7Certificates(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ders) != 1 || !bytes.Equal(ders[0], der) {
+		t.Errorf("got %d certificates", len(ders))
+	}
+	if _, err := pkcs7Certificates([]byte{0x30, 0x05}); err == nil {
+		t.Error("无效的 PKCS#7 应返回错误")
+	}
+}
+
+// writeSignedAPK 生成一个 zip，并在中央目录之前插入 APK Signing Block
+func writeSignedAPK(t *testing.T, pairs map[uint32][]byte) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create("AndroidManifest.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte("manifest"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	eocd := bytes.LastIndex(data, []byte{0x50, 0x4b, 0x05, 0x06})
+	cdOffset := binary.LittleEndian.Uint32(data[eocd+16:])
+
+	var block []byte
+	if pairs != nil {
+		var body []byte
+		for _, id := range []uint32{blockIDV2, 0x42726577, blockIDV3} {
+			value, ok := pairs[id]
+			if !ok {
+				continue
+			}
+			body = binary.LittleEndian.AppendUint64(body, uint64(4+len(value)))
+			body = binary.LittleEndian.AppendUint32(body, id)
+			body = append(body, value...)
+		}
+		size := uint64(len(body) + 24)
+		block = binary.LittleEndian.AppendUint64(nil, size)
+		block = append(block, body...)
+		block = binary.LittleEndian.AppendUint64(block, size)
+		block = append(block, apkSigBlockMagic...)
+	}
+
+	out := concat(data[:cdOffset], block, data[cdOffset:])
+	binary.LittleEndian.PutUint32(out[eocd+len(block)+16:], cdOffset+uint32(len(block)))
+	path := filepath.Join(t.TempDir(), "test.apk")
+	if err := os.WriteFile(path, out, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadSigningBlockCertificates(t *testing.T) {
+	v2, v3 := newTestCertificate(t, "v2"), newTestCertificate(t, "v3")
+	path := writeSignedAPK(t, map[uint32][]byte{
+		blockIDV2:  schemeBlock(v2),
+		0x42726577: make([]byte, 16), // 填充块等未知 ID 应跳过
+		blockIDV3:  schemeBlock(v3),
+	})
+	certs, err := readSigningBlockCertificates(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) != 2 || certs[0].Scheme != SchemeV2 || certs[0].Subject != "CN=v2" || certs[1].Scheme != SchemeV3 {
+		t.Errorf("certs = %+v", certs)
+	}
+
+	// 只有 v1 签名（没有签名块）时不报错
+	certs, err = readSigningBlockCertificates(writeSignedAPK(t, nil))
+	if err != nil || len(certs) != 0 {
+		t.Errorf("unsigned: certs = %+v, err = %v", certs, err)
+	}
+}
@@ -48,6 +48,8 @@ const (
 	ADBShell = "adb shell"
 	// Shell子命令
 	ShellGetProp        = "adb shell getprop %s" // 需要属性名参数
+	ShellGetPropAll     = "adb shell getprop"
+	ShellSetProp        = "adb shell setprop {KEY} {VALUE}" // 需要 KEY 属性名和 VALUE 值
 	ShellAndroidVersion = "adb shell getprop ro.build.version.release"
 	ShellPWD            = "adb shell pwd"
 	ShellLS             = "adb shell ls"
@@ -56,8 +58,10 @@ const (
 
 // 文件操作
 const (
-	ADBPush = "adb push %s %s" // 需要源文件和目标路径
-	ADBPull = "adb pull %s %s" // 需要设备路径和本地路径
+	// 使用具名占位符：params 为 map，多个 %s 按位置替换时顺序不确定
+	// 程序内的文件传输使用 adb.Push/adb.Pull（sync 协议），可获得进度并支持取消
+	ADBPush = "adb push {LOCAL} {REMOTE}" // 需要 LOCAL 本地路径和 REMOTE 设备路径
+	ADBPull = "adb pull {REMOTE} {LOCAL}" // 需要 REMOTE 设备路径和 LOCAL 本地路径
 )
 
 // 应用管理
@@ -67,6 +71,8 @@ const (
 	ADBInstallK   = "adb install -k %s"   // 保留数据安装
 	ADBUninstall  = "adb uninstall %s"    // 需要包名
 	ADBUninstallK = "adb uninstall -k %s" // 卸载保留数据
+
+	ADBInstallMultiple = "adb install-multiple %s" // 需要多个拆分APK路径
 )
 
 // 包管理器命令
@@ -79,6 +85,13 @@ const (
 	PMGrant            = "adb shell pm grant %s %s"      // 包名和权限
 	PMRevoke           = "adb shell pm revoke %s %s"     // 包名和权限
 	PMResetPermissions = "adb shell pm reset-permissions"
+
+	// 安装会话（拆分APK）
+	// 使用具名占位符，与 ADBPush/ADBPull 一致
+	PMInstallCreate  = "adb shell pm install-create -S {SIZE}"                           // 需要 SIZE 总大小
+	PMInstallWrite   = "adb shell pm install-write -S {SIZE} {SESSION} {SPLIT} {REMOTE}" // 需要 SIZE 大小、SESSION 会话ID、SPLIT 拆分名、REMOTE 设备路径
+	PMInstallCommit  = "adb shell pm install-commit {SESSION}"                           // 需要 SESSION 会话ID
+	PMInstallAbandon = "adb shell pm install-abandon {SESSION}"                          // 需要 SESSION 会话ID
 )
 
 // 日志命令
@@ -114,6 +127,11 @@ const (
 	AMBroadcast = "adb shell am broadcast -a '%s'" // 需要action名称
 )
 
+// 状态栏演示模式，需先 settings put global sysui_demo_allowed 1
+const (
+	AMDemoMode = "adb shell am broadcast -a com.android.systemui.demo -e command %s" // enter、clock、battery、network、notifications、exit 等
+)
+
 // 窗口管理器
 const (
 	WMSize         = "adb shell wm size %dx%d" // 需要宽高
@@ -122,6 +140,15 @@ const (
 	WMDensityReset = "adb shell wm density reset"
 )
 
+// 系统设置（settings），NAMESPACE 为 system、secure 或 global
+const (
+	SettingsList   = "adb shell settings list {NAMESPACE}"
+	SettingsGet    = "adb shell settings get {NAMESPACE} {KEY}"
+	SettingsPut    = "adb shell settings put {NAMESPACE} {KEY} {VALUE}"
+	SettingsDelete = "adb shell settings delete {NAMESPACE} {KEY}"
+	SettingsReset  = "adb shell settings reset {NAMESPACE} {MODE}" // MODE 为 untrusted_defaults、untrusted_clear 或 trusted_defaults
+)
+
 // 备份和恢复
 const (
 	ADBBackup         = "adb backup -apk -all -f %s" // 需要备份文件路径
@@ -133,17 +160,20 @@ const (
 
 // 设备信息
 const (
-	DumpsysBattery          = "adb shell dumpsys battery"
-	DumpsysBatterySetLevel  = "adb shell dumpsys battery set level %d"
-	DumpsysBatterySetStatus = "adb shell dumpsys battery set status %d"
-	DumpsysBatteryReset     = "adb shell dumpsys battery reset"
-	DumpsysBatterySetUSB    = "adb shell dumpsys battery set usb %s"
-	DumpsysIPhoneSubInfo    = "adb shell dumpsys iphonesubinfo" // 获取IMEI
-	DumpsysWindow           = "adb shell dumpsys window windows"
-	DumpsysPackage          = "adb shell dumpsys package packages"
-	DumpsysActivity         = "adb shell dumpsys activity %s/%s" // 包名/Activity名
-	Netstat                 = "adb shell netstat"
-	PS                      = "adb shell ps"
+	DumpsysBattery            = "adb shell dumpsys battery"
+	DumpsysBatterySetLevel    = "adb shell dumpsys battery set level %d"
+	DumpsysBatterySetStatus   = "adb shell dumpsys battery set status %d"
+	DumpsysBatteryReset       = "adb shell dumpsys battery reset"
+	DumpsysBatterySetUSB      = "adb shell dumpsys battery set usb %s"
+	DumpsysBatterySetAC       = "adb shell dumpsys battery set ac %s"
+	DumpsysBatterySetWireless = "adb shell dumpsys battery set wireless %s"
+	DumpsysBatteryUnplug      = "adb shell dumpsys battery unplug"
+	DumpsysIPhoneSubInfo      = "adb shell dumpsys iphonesubinfo" // 获取IMEI
+	DumpsysWindow             = "adb shell dumpsys window windows"
+	DumpsysPackage            = "adb shell dumpsys package packages"
+	DumpsysActivity           = "adb shell dumpsys activity %s/%s" // 包名/Activity名
+	Netstat                   = "adb shell netstat"
+	PS                        = "adb shell ps"
 )
 
 // Monkey测试
@@ -152,6 +182,8 @@ const (
 )
 
 // Shared Preferences
+// 以下广播需要目标应用内置对应的接收器，大多数应用并没有；
+// 通用的读写请使用应用管理中的 SharedPreferences 编辑器（直接读写 shared_prefs/*.xml）
 const (
 	SPPut    = "adb shell 'am broadcast -a %s.sp.PUT --es key %s --es value \"%s\"'"
 	SPRemove = "adb shell 'am broadcast -a %s.sp.REMOVE --es key %s'"
@@ -193,34 +225,354 @@ const (
 	KeyCodeMediaStop      = 86
 	KeyCodeMediaNext      = 87
 	KeyCodeMediaPrev      = 88
-	KeyCodeMute           = 91
+	KeyCodeMute           = 91 // 麦克风静音
 	KeyCodePageUp         = 92
 	KeyCodePageDown       = 93
 	KeyCodeExplorer       = 64 // 打开浏览器
 	KeyCodeEnvelope       = 65 // 打开邮件
+	KeyCodeDpadUp         = 19
+	KeyCodeDpadDown       = 20
+	KeyCodeDpadLeft       = 21
+	KeyCodeDpadRight      = 22
+	KeyCodeTab            = 61
+	KeyCodeForwardDel     = 112
+	KeyCodeMoveHome       = 122
+	KeyCodeMoveEnd        = 123
+	KeyCodeAppSwitch      = 187 // 最近任务
+	KeyCodeDpadCenter     = 23
+	KeyCodeSpace          = 62
+	KeyCodeNotification   = 83
+	KeyCodeMediaRewind    = 89
+	KeyCodeMediaFastFwd   = 90
+	KeyCodeEscape         = 111
+	KeyCodeVolumeMute     = 164 // 媒体音量静音
+	KeyCodeSettings       = 176
+	KeyCodeAssist         = 219
+	KeyCodeBrightnessDown = 220
+	KeyCodeBrightnessUp   = 221
+	KeyCodeSleep          = 223
+	KeyCodeWakeup         = 224
 )
 
-// 按键码映射表（用于显示）
+// 按键码映射表（用于显示与按名称查找），覆盖 android.view.KeyEvent 中的全部 KEYCODE_*
 var KeyCodeMap = map[int]string{
-	0:  "KEYCODE_0",
-	1:  "KEYCODE_SOFT_LEFT",
-	2:  "KEYCODE_SOFT_RIGHT",
-	3:  "KEYCODE_HOME",
-	4:  "KEYCODE_BACK",
-	5:  "KEYCODE_CALL",
-	6:  "KEYCODE_ENDCALL",
-	26: "KEYCODE_POWER",
-	27: "KEYCODE_CAMERA",
-	64: "KEYCODE_EXPLORER",
-	66: "KEYCODE_ENTER",
-	67: "KEYCODE_DEL",
-	82: "KEYCODE_MENU",
-	84: "KEYCODE_SEARCH",
-	85: "KEYCODE_MEDIA_PLAY_PAUSE",
-	86: "KEYCODE_MEDIA_STOP",
-	87: "KEYCODE_MEDIA_NEXT",
-	88: "KEYCODE_MEDIA_PREVIOUS",
-	91: "KEYCODE_MUTE",
+	0:   "KEYCODE_UNKNOWN",
+	1:   "KEYCODE_SOFT_LEFT",
+	2:   "KEYCODE_SOFT_RIGHT",
+	3:   "KEYCODE_HOME",
+	4:   "KEYCODE_BACK",
+	5:   "KEYCODE_CALL",
+	6:   "KEYCODE_ENDCALL",
+	7:   "KEYCODE_0",
+	8:   "KEYCODE_1",
+	9:   "KEYCODE_2",
+	10:  "KEYCODE_3",
+	11:  "KEYCODE_4",
+	12:  "KEYCODE_5",
+	13:  "KEYCODE_6",
+	14:  "KEYCODE_7",
+	15:  "KEYCODE_8",
+	16:  "KEYCODE_9",
+	17:  "KEYCODE_STAR",
+	18:  "KEYCODE_POUND",
+	19:  "KEYCODE_DPAD_UP",
+	20:  "KEYCODE_DPAD_DOWN",
+	21:  "KEYCODE_DPAD_LEFT",
+	22:  "KEYCODE_DPAD_RIGHT",
+	23:  "KEYCODE_DPAD_CENTER",
+	24:  "KEYCODE_VOLUME_UP",
+	25:  "KEYCODE_VOLUME_DOWN",
+	26:  "KEYCODE_POWER",
+	27:  "KEYCODE_CAMERA",
+	28:  "KEYCODE_CLEAR",
+	29:  "KEYCODE_A",
+	30:  "KEYCODE_B",
+	31:  "KEYCODE_C",
+	32:  "KEYCODE_D",
+	33:  "KEYCODE_E",
+	34:  "KEYCODE_F",
+	35:  "KEYCODE_G",
+	36:  "KEYCODE_H",
+	37:  "KEYCODE_I",
+	38:  "KEYCODE_J",
+	39:  "KEYCODE_K",
+	40:  "KEYCODE_L",
+	41:  "KEYCODE_M",
+	42:  "KEYCODE_N",
+	43:  "KEYCODE_O",
+	44:  "KEYCODE_P",
+	45:  "KEYCODE_Q",
+	46:  "KEYCODE_R",
+	47:  "KEYCODE_S",
+	48:  "KEYCODE_T",
+	49:  "KEYCODE_U",
+	50:  "KEYCODE_V",
+	51:  "KEYCODE_W",
+	52:  "KEYCODE_X",
+	53:  "KEYCODE_Y",
+	54:  "KEYCODE_Z",
+	55:  "KEYCODE_COMMA",
+	56:  "KEYCODE_PERIOD",
+	57:  "KEYCODE_ALT_LEFT",
+	58:  "KEYCODE_ALT_RIGHT",
+	59:  "KEYCODE_SHIFT_LEFT",
+	60:  "KEYCODE_SHIFT_RIGHT",
+	61:  "KEYCODE_TAB",
+	62:  "KEYCODE_SPACE",
+	63:  "KEYCODE_SYM",
+	64:  "KEYCODE_EXPLORER",
+	65:  "KEYCODE_ENVELOPE",
+	66:  "KEYCODE_ENTER",
+	67:  "KEYCODE_DEL",
+	68:  "KEYCODE_GRAVE",
+	69:  "KEYCODE_MINUS",
+	70:  "KEYCODE_EQUALS",
+	71:  "KEYCODE_LEFT_BRACKET",
+	72:  "KEYCODE_RIGHT_BRACKET",
+	73:  "KEYCODE_BACKSLASH",
+	74:  "KEYCODE_SEMICOLON",
+	75:  "KEYCODE_APOSTROPHE",
+	76:  "KEYCODE_SLASH",
+	77:  "KEYCODE_AT",
+	78:  "KEYCODE_NUM",
+	79:  "KEYCODE_HEADSETHOOK",
+	80:  "KEYCODE_FOCUS",
+	81:  "KEYCODE_PLUS",
+	82:  "KEYCODE_MENU",
+	83:  "KEYCODE_NOTIFICATION",
+	84:  "KEYCODE_SEARCH",
+	85:  "KEYCODE_MEDIA_PLAY_PAUSE",
+	86:  "KEYCODE_MEDIA_STOP",
+	87:  "KEYCODE_MEDIA_NEXT",
+	88:  "KEYCODE_MEDIA_PREVIOUS",
+	89:  "KEYCODE_MEDIA_REWIND",
+	90:  "KEYCODE_MEDIA_FAST_FORWARD",
+	91:  "KEYCODE_MUTE",
+	92:  "KEYCODE_PAGE_UP",
+	93:  "KEYCODE_PAGE_DOWN",
+	94:  "KEYCODE_PICTSYMBOLS",
+	95:  "KEYCODE_SWITCH_CHARSET",
+	96:  "KEYCODE_BUTTON_A",
+	97:  "KEYCODE_BUTTON_B",
+	98:  "KEYCODE_BUTTON_C",
+	99:  "KEYCODE_BUTTON_X",
+	100: "KEYCODE_BUTTON_Y",
+	101: "KEYCODE_BUTTON_Z",
+	102: "KEYCODE_BUTTON_L1",
+	103: "KEYCODE_BUTTON_R1",
+	104: "KEYCODE_BUTTON_L2",
+	105: "KEYCODE_BUTTON_R2",
+	106: "KEYCODE_BUTTON_THUMBL",
+	107: "KEYCODE_BUTTON_THUMBR",
+	108: "KEYCODE_BUTTON_START",
+	109: "KEYCODE_BUTTON_SELECT",
+	110: "KEYCODE_BUTTON_MODE",
+	111: "KEYCODE_ESCAPE",
+	112: "KEYCODE_FORWARD_DEL",
+	113: "KEYCODE_CTRL_LEFT",
+	114: "KEYCODE_CTRL_RIGHT",
+	115: "KEYCODE_CAPS_LOCK",
+	116: "KEYCODE_SCROLL_LOCK",
+	117: "KEYCODE_META_LEFT",
+	118: "KEYCODE_META_RIGHT",
+	119: "KEYCODE_FUNCTION",
+	120: "KEYCODE_SYSRQ",
+	121: "KEYCODE_BREAK",
+	122: "KEYCODE_MOVE_HOME",
+	123: "KEYCODE_MOVE_END",
+	124: "KEYCODE_INSERT",
+	125: "KEYCODE_FORWARD",
+	126: "KEYCODE_MEDIA_PLAY",
+	127: "KEYCODE_MEDIA_PAUSE",
+	128: "KEYCODE_MEDIA_CLOSE",
+	129: "KEYCODE_MEDIA_EJECT",
+	130: "KEYCODE_MEDIA_RECORD",
+	131: "KEYCODE_F1",
+	132: "KEYCODE_F2",
+	133: "KEYCODE_F3",
+	134: "KEYCODE_F4",
+	135: "KEYCODE_F5",
+	136: "KEYCODE_F6",
+	137: "KEYCODE_F7",
+	138: "KEYCODE_F8",
+	139: "KEYCODE_F9",
+	140: "KEYCODE_F10",
+	141: "KEYCODE_F11",
+	142: "KEYCODE_F12",
+	143: "KEYCODE_NUM_LOCK",
+	144: "KEYCODE_NUMPAD_0",
+	145: "KEYCODE_NUMPAD_1",
+	146: "KEYCODE_NUMPAD_2",
+	147: "KEYCODE_NUMPAD_3",
+	148: "KEYCODE_NUMPAD_4",
+	149: "KEYCODE_NUMPAD_5",
+	150: "KEYCODE_NUMPAD_6",
+	151: "KEYCODE_NUMPAD_7",
+	152: "KEYCODE_NUMPAD_8",
+	153: "KEYCODE_NUMPAD_9",
+	154: "KEYCODE_NUMPAD_DIVIDE",
+	155: "KEYCODE_NUMPAD_MULTIPLY",
+	156: "KEYCODE_NUMPAD_SUBTRACT",
+	157: "KEYCODE_NUMPAD_ADD",
+	158: "KEYCODE_NUMPAD_DOT",
+	159: "KEYCODE_NUMPAD_COMMA",
+	160: "KEYCODE_NUMPAD_ENTER",
+	161: "KEYCODE_NUMPAD_EQUALS",
+	162: "KEYCODE_NUMPAD_LEFT_PAREN",
+	163: "KEYCODE_NUMPAD_RIGHT_PAREN",
+	164: "KEYCODE_VOLUME_MUTE",
+	165: "KEYCODE_INFO",
+	166: "KEYCODE_CHANNEL_UP",
+	167: "KEYCODE_CHANNEL_DOWN",
+	168: "KEYCODE_ZOOM_IN",
+	169: "KEYCODE_ZOOM_OUT",
+	170: "KEYCODE_TV",
+	171: "KEYCODE_WINDOW",
+	172: "KEYCODE_GUIDE",
+	173: "KEYCODE_DVR",
+	174: "KEYCODE_BOOKMARK",
+	175: "KEYCODE_CAPTIONS",
+	176: "KEYCODE_SETTINGS",
+	177: "KEYCODE_TV_POWER",
+	178: "KEYCODE_TV_INPUT",
+	179: "KEYCODE_STB_POWER",
+	180: "KEYCODE_STB_INPUT",
+	181: "KEYCODE_AVR_POWER",
+	182: "KEYCODE_AVR_INPUT",
+	183: "KEYCODE_PROG_RED",
+	184: "KEYCODE_PROG_GREEN",
+	185: "KEYCODE_PROG_YELLOW",
+	186: "KEYCODE_PROG_BLUE",
+	187: "KEYCODE_APP_SWITCH",
+	188: "KEYCODE_BUTTON_1",
+	189: "KEYCODE_BUTTON_2",
+	190: "KEYCODE_BUTTON_3",
+	191: "KEYCODE_BUTTON_4",
+	192: "KEYCODE_BUTTON_5",
+	193: "KEYCODE_BUTTON_6",
+	194: "KEYCODE_BUTTON_7",
+	195: "KEYCODE_BUTTON_8",
+	196: "KEYCODE_BUTTON_9",
+	197: "KEYCODE_BUTTON_10",
+	198: "KEYCODE_BUTTON_11",
+	199: "KEYCODE_BUTTON_12",
+	200: "KEYCODE_BUTTON_13",
+	201: "KEYCODE_BUTTON_14",
+	202: "KEYCODE_BUTTON_15",
+	203: "KEYCODE_BUTTON_16",
+	204: "KEYCODE_LANGUAGE_SWITCH",
+	205: "KEYCODE_MANNER_MODE",
+	206: "KEYCODE_3D_MODE",
+	207: "KEYCODE_CONTACTS",
+	208: "KEYCODE_CALENDAR",
+	209: "KEYCODE_MUSIC",
+	210: "KEYCODE_CALCULATOR",
+	211: "KEYCODE_ZENKAKU_HANKAKU",
+	212: "KEYCODE_EISU",
+	213: "KEYCODE_MUHENKAN",
+	214: "KEYCODE_HENKAN",
+	215: "KEYCODE_KATAKANA_HIRAGANA",
+	216: "KEYCODE_YEN",
+	217: "KEYCODE_RO",
+	218: "KEYCODE_KANA",
+	219: "KEYCODE_ASSIST",
+	220: "KEYCODE_BRIGHTNESS_DOWN",
+	221: "KEYCODE_BRIGHTNESS_UP",
+	222: "KEYCODE_MEDIA_AUDIO_TRACK",
+	223: "KEYCODE_SLEEP",
+	224: "KEYCODE_WAKEUP",
+	225: "KEYCODE_PAIRING",
+	226: "KEYCODE_MEDIA_TOP_MENU",
+	227: "KEYCODE_11",
+	228: "KEYCODE_12",
+	229: "KEYCODE_LAST_CHANNEL",
+	230: "KEYCODE_TV_DATA_SERVICE",
+	231: "KEYCODE_VOICE_ASSIST",
+	232: "KEYCODE_TV_RADIO_SERVICE",
+	233: "KEYCODE_TV_TELETEXT",
+	234: "KEYCODE_TV_NUMBER_ENTRY",
+	235: "KEYCODE_TV_TERRESTRIAL_ANALOG",
+	236: "KEYCODE_TV_TERRESTRIAL_DIGITAL",
+	237: "KEYCODE_TV_SATELLITE",
+	238: "KEYCODE_TV_SATELLITE_BS",
+	239: "KEYCODE_TV_SATELLITE_CS",
+	240: "KEYCODE_TV_SATELLITE_SERVICE",
+	241: "KEYCODE_TV_NETWORK",
+	242: "KEYCODE_TV_ANTENNA_CABLE",
+	243: "KEYCODE_TV_INPUT_HDMI_1",
+	244: "KEYCODE_TV_INPUT_HDMI_2",
+	245: "KEYCODE_TV_INPUT_HDMI_3",
+	246: "KEYCODE_TV_INPUT_HDMI_4",
+	247: "KEYCODE_TV_INPUT_COMPOSITE_1",
+	248: "KEYCODE_TV_INPUT_COMPOSITE_2",
+	249: "KEYCODE_TV_INPUT_COMPONENT_1",
+	250: "KEYCODE_TV_INPUT_COMPONENT_2",
+	251: "KEYCODE_TV_INPUT_VGA_1",
+	252: "KEYCODE_TV_AUDIO_DESCRIPTION",
+	253: "KEYCODE_TV_AUDIO_DESCRIPTION_MIX_UP",
+	254: "KEYCODE_TV_AUDIO_DESCRIPTION_MIX_DOWN",
+	255: "KEYCODE_TV_ZOOM_MODE",
+	256: "KEYCODE_TV_CONTENTS_MENU",
+	257: "KEYCODE_TV_MEDIA_CONTEXT_MENU",
+	258: "KEYCODE_TV_TIMER_PROGRAMMING",
+	259: "KEYCODE_HELP",
+	260: "KEYCODE_NAVIGATE_PREVIOUS",
+	261: "KEYCODE_NAVIGATE_NEXT",
+	262: "KEYCODE_NAVIGATE_IN",
+	263: "KEYCODE_NAVIGATE_OUT",
+	264: "KEYCODE_STEM_PRIMARY",
+	265: "KEYCODE_STEM_1",
+	266: "KEYCODE_STEM_2",
+	267: "KEYCODE_STEM_3",
+	268: "KEYCODE_DPAD_UP_LEFT",
+	269: "KEYCODE_DPAD_DOWN_LEFT",
+	270: "KEYCODE_DPAD_UP_RIGHT",
+	271: "KEYCODE_DPAD_DOWN_RIGHT",
+	272: "KEYCODE_MEDIA_SKIP_FORWARD",
+	273: "KEYCODE_MEDIA_SKIP_BACKWARD",
+	274: "KEYCODE_MEDIA_STEP_FORWARD",
+	275: "KEYCODE_MEDIA_STEP_BACKWARD",
+	276: "KEYCODE_SOFT_SLEEP",
+	277: "KEYCODE_CUT",
+	278: "KEYCODE_COPY",
+	279: "KEYCODE_PASTE",
+	280: "KEYCODE_SYSTEM_NAVIGATION_UP",
+	281: "KEYCODE_SYSTEM_NAVIGATION_DOWN",
+	282: "KEYCODE_SYSTEM_NAVIGATION_LEFT",
+	283: "KEYCODE_SYSTEM_NAVIGATION_RIGHT",
+	284: "KEYCODE_ALL_APPS",
+	285: "KEYCODE_REFRESH",
+	286: "KEYCODE_THUMBS_UP",
+	287: "KEYCODE_THUMBS_DOWN",
+	288: "KEYCODE_PROFILE_SWITCH",
+	289: "KEYCODE_VIDEO_APP_1",
+	290: "KEYCODE_VIDEO_APP_2",
+	291: "KEYCODE_VIDEO_APP_3",
+	292: "KEYCODE_VIDEO_APP_4",
+	293: "KEYCODE_VIDEO_APP_5",
+	294: "KEYCODE_VIDEO_APP_6",
+	295: "KEYCODE_VIDEO_APP_7",
+	296: "KEYCODE_VIDEO_APP_8",
+	297: "KEYCODE_FEATURED_APP_1",
+	298: "KEYCODE_FEATURED_APP_2",
+	299: "KEYCODE_FEATURED_APP_3",
+	300: "KEYCODE_FEATURED_APP_4",
+	301: "KEYCODE_DEMO_APP_1",
+	302: "KEYCODE_DEMO_APP_2",
+	303: "KEYCODE_DEMO_APP_3",
+	304: "KEYCODE_DEMO_APP_4",
+	305: "KEYCODE_KEYBOARD_BACKLIGHT_DOWN",
+	306: "KEYCODE_KEYBOARD_BACKLIGHT_UP",
+	307: "KEYCODE_KEYBOARD_BACKLIGHT_TOGGLE",
+	308: "KEYCODE_STYLUS_BUTTON_PRIMARY",
+	309: "KEYCODE_STYLUS_BUTTON_SECONDARY",
+	310: "KEYCODE_STYLUS_BUTTON_TERTIARY",
+	311: "KEYCODE_STYLUS_BUTTON_TAIL",
+	312: "KEYCODE_RECENT_APPS",
+	313: "KEYCODE_MACRO_1",
+	314: "KEYCODE_MACRO_2",
+	315: "KEYCODE_MACRO_3",
+	316: "KEYCODE_MACRO_4",
 }
 
 // 常用快捷键组合
@@ -255,7 +607,7 @@ var FeatureMap = map[string]FeatureConfig{
 		Name:         "应用管理",
 		Description:  "安装、卸载和管理Android应用",
 		IconName:     "DocumentSaveIcon",
-		CommandGroup: []string{"ADBInstall", "ADBInstallR", "ADBInstallK", "ADBUninstall", "ADBUninstallK", "PMListPackages", "PMListPackages3", "PMListPackagesS", "PMUninstall", "PMClear", "PMGrant", "PMRevoke", "PMResetPermissions"},
+		CommandGroup: []string{"ADBInstall", "ADBInstallR", "ADBInstallK", "ADBUninstall", "ADBUninstallK", "ADBInstallMultiple", "PMListPackages", "PMListPackages3", "PMListPackagesS", "PMUninstall", "PMClear", "PMGrant", "PMRevoke", "PMResetPermissions", "PMInstallCreate", "PMInstallWrite", "PMInstallCommit", "PMInstallAbandon"},
 		DefaultLabel: "应用管理功能",
 	},
 	"log_viewing": {
@@ -274,6 +626,22 @@ var FeatureMap = map[string]FeatureConfig{
 		CommandGroup: []string{"ADBPush", "ADBPull", "ShellLS", "ShellLSRecursive", "ShellPWD"},
 		DefaultLabel: "文件传输功能",
 	},
+	"screen_control": {
+		ID:           "screen_control",
+		Name:         "屏幕控制",
+		Description:  "截取、录制和实时显示设备屏幕",
+		IconName:     "MediaPhotoIcon",
+		CommandGroup: []string{"Screenshot", "Screenrecord"},
+		DefaultLabel: "屏幕控制功能",
+	},
+	"system_tools": {
+		ID:           "system_tools",
+		Name:         "系统工具",
+		Description:  "查看和修改系统属性、系统设置、屏幕显示，模拟电池状态与状态栏演示模式等系统级配置",
+		IconName:     "ComputerIcon",
+		CommandGroup: []string{"ShellGetPropAll", "ShellSetProp", "SettingsList", "SettingsPut", "SettingsDelete", "SettingsReset", "WMSize", "WMSizeReset", "WMDensity", "WMDensityReset", "DumpsysBattery", "DumpsysBatterySetLevel", "DumpsysBatteryUnplug", "DumpsysBatteryReset", "AMDemoMode"},
+		DefaultLabel: "系统工具功能",
+	},
 	"settings": {
 		ID:           "settings",
 		Name:         "设置",
@@ -311,6 +679,11 @@ var CommandMap = map[string]string{
 	"PMGrant":            PMGrant,
 	"PMRevoke":           PMRevoke,
 	"PMResetPermissions": PMResetPermissions,
+	"ADBInstallMultiple": ADBInstallMultiple,
+	"PMInstallCreate":    PMInstallCreate,
+	"PMInstallWrite":     PMInstallWrite,
+	"PMInstallCommit":    PMInstallCommit,
+	"PMInstallAbandon":   PMInstallAbandon,
 	// 日志查看命令
 	"ADBLogcat":    ADBLogcat,
 	"ADBLogcatC":   ADBLogcatC,
@@ -322,6 +695,32 @@ var CommandMap = map[string]string{
 	"ShellLS":          ShellLS,
 	"ShellLSRecursive": ShellLSRecursive,
 	"ShellPWD":         ShellPWD,
+	// 屏幕控制命令
+	"Screenshot":   Screenshot,
+	"Screenrecord": Screenrecord,
+	// 系统工具命令
+	"ShellGetPropAll": ShellGetPropAll,
+	"ShellSetProp":    ShellSetProp,
+	"SettingsList":    SettingsList,
+	"SettingsGet":     SettingsGet,
+	"SettingsPut":     SettingsPut,
+	"SettingsDelete":  SettingsDelete,
+	"SettingsReset":   SettingsReset,
+	"WMSize":          WMSize,
+	"WMSizeReset":     WMSizeReset,
+	"WMDensity":       WMDensity,
+	"WMDensityReset":  WMDensityReset,
+	// 电池模拟命令
+	"DumpsysBattery":            DumpsysBattery,
+	"DumpsysBatterySetLevel":    DumpsysBatterySetLevel,
+	"DumpsysBatterySetStatus":   DumpsysBatterySetStatus,
+	"DumpsysBatterySetUSB":      DumpsysBatterySetUSB,
+	"DumpsysBatterySetAC":       DumpsysBatterySetAC,
+	"DumpsysBatterySetWireless": DumpsysBatterySetWireless,
+	"DumpsysBatteryUnplug":      DumpsysBatteryUnplug,
+	"DumpsysBatteryReset":       DumpsysBatteryReset,
+	// 演示模式命令
+	"AMDemoMode": AMDemoMode,
 	// 设置命令
 	"ADBKillServer":  ADBKillServer,
 	"ADBStartServer": ADBStartServer,
@@ -0,0 +1,281 @@
+// Package database 在本地打开从设备拉取的 SQLite 数据库，供数据库查看器使用。
+// 使用纯 Go 实现的 modernc.org/sqlite 驱动，不依赖 cgo
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	_ "modernc.org/sqlite"
+)
+
+// blobPreviewLimit BLOB 列在表格中最多显示的字节数
+const blobPreviewLimit = 32
+
+// DB 本地 SQLite 数据库
+type DB struct {
+	Path     string
+	db       *sql.DB
+	modified bool
+}
+
+// Column 表结构中的一列
+type Column struct {
+	Name       string
+	Type       string
+	NotNull    bool
+	Default    string
+	PrimaryKey bool
+}
+
+// Table 表（或视图）及其建表语句
+type Table struct {
+	Name string
+	Type string // table / view
+	SQL  string
+}
+
+// Result 查询结果，值已格式化为便于显示的字符串
+type Result struct {
+	Columns      []string
+	Rows         [][]string
+	RowsAffected int64 // 非查询语句影响的行数
+}
+
+// Open 打开本地数据库文件，同目录下的 -wal 会在打开时被读取
+func Open(path string) (*DB, error) {
+	log.Printf("打开数据库: %s", path)
+	dsn := "file:" + (&url.URL{Path: path}).EscapedPath() + "?_pragma=busy_timeout(5000)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	// 单连接即可，同时避免多个连接各自持有 WAL 读快照
+	db.SetMaxOpenConns(1)
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("无法打开数据库: %w", err)
+	}
+	return &DB{Path: path, db: db}, nil
+}
+
+// Close 关闭数据库
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// Modified 是否执行过修改数据的语句
+func (d *DB) Modified() bool {
+	return d.modified
+}
+
+// Tables 列出数据库中的表与视图（不含 sqlite_ 内部表）
+func (d *DB) Tables() ([]Table, error) {
+	rows, err := d.db.Query(`SELECT name, type, IFNULL(sql, '') FROM sqlite_master
+		WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []Table
+	for rows.Next() {
+		var t Table
+		if err := rows.Scan(&t.Name, &t.Type, &t.SQL); err != nil {
+			return nil, err
+		}
+		tables = append(tables, t)
+	}
+	return tables, rows.Err()
+}
+
+// Columns 返回表的列定义
+func (d *DB) Columns(table string) ([]Column, error) {
+	rows, err := d.db.Query("PRAGMA table_info(" + QuoteIdent(table) + ")")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []Column
+	for rows.Next() {
+		var (
+			cid     int
+			c       Column
+			notNull int
+			def     sql.NullString
+			pk      int
+		)
+		if err := rows.Scan(&cid, &c.Name, &c.Type, &notNull, &def, &pk); err != nil {
+			return nil, err
+		}
+		c.NotNull = notNull != 0
+		c.Default = def.String
+		c.PrimaryKey = pk > 0
+		columns = append(columns, c)
+	}
+	return columns, rows.Err()
+}
+
+// Indexes 返回表上的索引建表语句
+func (d *DB) Indexes(table string) ([]string, error) {
+	rows, err := d.db.Query(`SELECT sql FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var indexes []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, s)
+	}
+	return indexes, rows.Err()
+}
+
+// Count 返回表的行数
+func (d *DB) Count(table string) (int64, error) {
+	var n int64
+	err := d.db.QueryRow("SELECT COUNT(*) FROM " + QuoteIdent(table)).Scan(&n)
+	return n, err
+}
+
+// Page 分页读取表中的行
+func (d *DB) Page(table string, offset int64, limit int) (*Result, error) {
+	return d.query(fmt.Sprintf("SELECT * FROM %s LIMIT %d OFFSET %d", QuoteIdent(table), limit, offset))
+}
+
+// Execute 执行一条任意 SQL 语句。返回结果集的语句（SELECT、PRAGMA 等）返回其行，
+// 其余语句返回影响的行数；修改了数据的语句会将数据库标记为已修改
+func (d *DB) Execute(statement string) (*Result, error) {
+	statement = strings.TrimSpace(statement)
+	if statement == "" {
+		return nil, fmt.Errorf("SQL 语句为空")
+	}
+	log.Printf("执行SQL: %s", statement)
+	if returnsRows(statement) {
+		// WITH ... DELETE/UPDATE 等语句同样走查询路径，通过 total_changes() 判断是否修改过数据
+		before := d.totalChanges()
+		result, err := d.query(statement)
+		if d.totalChanges() != before || (err == nil && !readsOnly(statement)) {
+			d.modified = true
+		}
+		return result, err
+	}
+	res, err := d.db.Exec(statement)
+	if err != nil {
+		return nil, err
+	}
+	d.modified = true
+	affected, _ := res.RowsAffected()
+	return &Result{RowsAffected: affected}, nil
+}
+
+// Checkpoint 将 WAL 中的内容合并回主文件并清空 -wal，
+// 写回设备前调用，使主文件单独即可表示完整的数据库
+func (d *DB) Checkpoint() error {
+	_, err := d.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)")
+	return err
+}
+
+func (d *DB) query(statement string) (*Result, error) {
+	rows, err := d.db.Query(statement)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Columns: columns}
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		row := make([]string, len(columns))
+		for i, v := range values {
+			row[i] = FormatValue(v)
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return result, rows.Err()
+}
+
+// totalChanges 当前连接累计插入、修改、删除的行数，查询失败时返回 -1
+func (d *DB) totalChanges() int64 {
+	var n int64
+	if err := d.db.QueryRow("SELECT total_changes()").Scan(&n); err != nil {
+		return -1
+	}
+	return n
+}
+
+// readsOnly 返回结果集的语句中不会修改数据库的部分：查询，以及不带赋值的 PRAGMA。
+// WITH 可能带有 DELETE/UPDATE，由调用方比较 total_changes() 判断
+func readsOnly(statement string) bool {
+	fields := strings.Fields(statement)
+	if len(fields) == 0 {
+		return true
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "PRAGMA":
+		return !strings.Contains(statement, "=")
+	case "SELECT", "WITH", "EXPLAIN", "VALUES":
+		return true
+	}
+	return false
+}
+
+// returnsRows 根据语句的第一个关键字判断是否返回结果集
+func returnsRows(statement string) bool {
+	fields := strings.Fields(statement)
+	if len(fields) == 0 {
+		return false
+	}
+	switch strings.ToUpper(fields[0]) {
+	case "SELECT", "PRAGMA", "WITH", "EXPLAIN", "VALUES":
+		return true
+	}
+	return false
+}
+
+// FormatValue 将列值格式化为显示文本，BLOB 显示为十六进制预览
+func FormatValue(v any) string {
+	switch value := v.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		if utf8.Valid(value) && !strings.ContainsRune(string(value), 0) {
+			return string(value)
+		}
+		preview := value
+		if len(preview) > blobPreviewLimit {
+			preview = preview[:blobPreviewLimit]
+		}
+		text := fmt.Sprintf("BLOB(%d) %x", len(value), preview)
+		if len(value) > blobPreviewLimit {
+			text += "…"
+		}
+		return text
+	}
+	return fmt.Sprint(v)
+}
+
+// QuoteIdent 为表名、列名加双引号
+func QuoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
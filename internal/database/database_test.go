@@ -0,0 +1,58 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestExecuteMarksModified(t *testing.T) {
+	tests := []struct {
+		name      string
+		statement string
+		modified  bool
+	}{
+		{"查询", "SELECT * FROM t", false},
+		{"WITH 查询", "WITH x AS (SELECT id FROM t) SELECT * FROM x", false},
+		{"EXPLAIN", "EXPLAIN SELECT * FROM t", false},
+		{"读取 PRAGMA", "PRAGMA table_info(t)", false},
+		{"VALUES", "VALUES (1), (2)", false},
+		{"WITH 删除", "WITH x AS (SELECT 1 AS id) DELETE FROM t WHERE id IN (SELECT id FROM x)", true},
+		{"WITH 更新", "WITH x AS (SELECT 2 AS id) UPDATE t SET v = 'z' WHERE id IN (SELECT id FROM x)", true},
+		{"WITH 未匹配任何行", "WITH x AS (SELECT 99 AS id) DELETE FROM t WHERE id IN (SELECT id FROM x)", false},
+		{"设置 PRAGMA", "PRAGMA user_version = 5", true},
+		{"UPDATE", "UPDATE t SET v = 'y'", true},
+		{"建表", "CREATE TABLE u (id INTEGER)", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+			if _, err := db.db.Exec("CREATE TABLE t (id INTEGER PRIMARY KEY, v TEXT); INSERT INTO t VALUES (1, 'a'), (2, 'b')"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := db.Execute(tt.statement); err != nil {
+				t.Fatal(err)
+			}
+			if db.Modified() != tt.modified {
+				t.Errorf("Modified() = %v, want %v", db.Modified(), tt.modified)
+			}
+		})
+	}
+}
+
+func TestExecuteFailedReadIsNotModified(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Execute("SELECT * FROM missing"); err == nil {
+		t.Fatal("expected error")
+	}
+	if db.Modified() {
+		t.Error("失败的查询不应标记为已修改")
+	}
+}
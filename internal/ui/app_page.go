@@ -0,0 +1,239 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"yikong/internal/adb"
+	"yikong/internal/apk"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+func (ui *UI) createAppManagementPage() fyne.CanvasObject {
+	tabs := container.NewAppTabs(
+		container.NewTabItem("APK分析", ui.createAPKAnalyzerTab()),
+		container.NewTabItem("安装", ui.createInstallTab()),
+		container.NewTabItem("权限", ui.createPermissionTab()),
+		container.NewTabItem("备份/恢复", ui.createBackupTab()),
+		container.NewTabItem("数据库", ui.createDatabaseTab()),
+		container.NewTabItem("SharedPreferences", ui.createPrefsTab()),
+	)
+	return tabs
+}
+
+// createAPKAnalyzerTab 安装前查看APK的包名、版本、权限、签名等信息
+func (ui *UI) createAPKAnalyzerTab() fyne.CanvasObject {
+	var analyzed *apk.Info
+
+	pathLabel := widget.NewLabel("未选择APK文件")
+	pathLabel.Wrapping = fyne.TextWrapBreak
+
+	statusLabel := widget.NewLabel("")
+	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	resultDisplay := widget.NewMultiLineEntry()
+	resultDisplay.Wrapping = fyne.TextWrapWord
+	resultDisplay.SetPlaceHolder("APK分析结果将显示在这里...")
+	resultDisplay.Disable()
+
+	scrollContainer := container.NewScroll(resultDisplay)
+	scrollContainer.SetMinSize(fyne.NewSize(600, 360))
+
+	var selectBtn, compareBtn *widget.Button
+
+	analyze := func(path string) {
+		pathLabel.SetText(path)
+		statusLabel.SetText("状态: 正在分析APK...")
+		resultDisplay.SetText("")
+		selectBtn.Disable()
+		compareBtn.Disable()
+
+		go func() {
+			info, err := apk.Analyze(path)
+			fyne.Do(func() {
+				selectBtn.Enable()
+				if err != nil {
+					logging.Error("APK分析失败: %s, 错误: %v", path, err)
+					statusLabel.SetText(fmt.Sprintf("状态: 分析失败 - %v", err))
+					ui.showMessagePopup("错误", "APK分析失败: "+err.Error())
+					return
+				}
+				analyzed = info
+				resultDisplay.SetText(info.Summary())
+				statusLabel.SetText(fmt.Sprintf("状态: 分析完成 - %s %s", info.PackageName, info.VersionName))
+				compareBtn.Enable()
+			})
+		}()
+	}
+
+	selectBtn = widget.NewButton("选择APK文件", func() {
+		fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			path := reader.URI().Path()
+			reader.Close()
+			analyze(path)
+		}, ui.window)
+		fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{".apk"}))
+		fileDialog.Show()
+	})
+	selectBtn.Importance = widget.HighImportance
+
+	compareBtn = widget.NewButton("与设备已安装版本对比", func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		if analyzed == nil {
+			return
+		}
+
+		info := analyzed
+		compareBtn.Disable()
+		statusLabel.SetText("状态: 正在查询设备上的已安装版本...")
+
+		go func() {
+			installed, err := adb.GetInstalledPackage(ui.selectedDevice, info.PackageName)
+			fyne.Do(func() {
+				compareBtn.Enable()
+				if err != nil {
+					statusLabel.SetText(fmt.Sprintf("状态: 查询失败 - %v", err))
+					return
+				}
+				statusLabel.SetText("状态: " + adb.CompareVersionCode(info.VersionCode, installed))
+			})
+		}()
+	})
+	compareBtn.Importance = widget.MediumImportance
+	compareBtn.Disable()
+
+	content := container.NewBorder(
+		container.NewVBox(
+			container.NewHBox(selectBtn, compareBtn),
+			pathLabel,
+			statusLabel,
+			widget.NewSeparator(),
+		),
+		nil, nil, nil,
+		scrollContainer,
+	)
+
+	return container.NewPadded(content)
+}
+
+// createInstallTab 安装单个APK、多个拆分APK，或 .apks/.xapk 安装包，
+// 可同时安装到多台设备；也可以直接把文件拖放到窗口上安装
+func (ui *UI) createInstallTab() fyne.CanvasObject {
+	var files []string
+
+	fileList := widget.NewList(
+		func() int {
+			return len(files)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("文件")
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(filepath.Base(files[i]))
+		},
+	)
+	fileListScroll := container.NewScroll(fileList)
+	fileListScroll.SetMinSize(fyne.NewSize(600, 120))
+
+	hintLabel := widget.NewLabel("提示: 可以将 .apk / .apks / .xapk 文件直接拖放到窗口上安装")
+	hintLabel.TextStyle = fyne.TextStyle{Italic: true}
+
+	deviceGroup := widget.NewCheckGroup(nil, func(selected []string) {
+		ui.installDevices = selected
+	})
+	deviceGroup.Horizontal = true
+
+	refreshDevices := func() {
+		devices, err := adb.GetDevices()
+		if err != nil {
+			logging.Error("获取设备失败: %v", err)
+			return
+		}
+		options := make([]string, 0, len(devices))
+		for _, device := range devices {
+			options = append(options, device.ID)
+		}
+		deviceGroup.Options = options
+
+		selected := ui.installDevices
+		if len(selected) == 0 && ui.selectedDevice != "" {
+			selected = []string{ui.selectedDevice}
+		}
+		deviceGroup.SetSelected(selected)
+		deviceGroup.Refresh()
+	}
+	refreshDevices()
+
+	refreshDevicesBtn := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), refreshDevices)
+
+	replaceCheck := widget.NewCheck("覆盖安装 (-r)", nil)
+	replaceCheck.SetChecked(true)
+	downgradeCheck := widget.NewCheck("允许降级 (-d)", nil)
+	grantCheck := widget.NewCheck("授予运行时权限 (-g)", nil)
+	testCheck := widget.NewCheck("允许测试包 (-t)", nil)
+	sessionCheck := widget.NewCheck("使用pm安装会话", nil)
+
+	addBtn := widget.NewButton("添加文件", func() {
+		fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			files = append(files, reader.URI().Path())
+			reader.Close()
+			fileList.Refresh()
+		}, ui.window)
+		fileDialog.SetFilter(storage.NewExtensionFileFilter(installableExtensions))
+		fileDialog.Show()
+	})
+
+	clearBtn := widget.NewButton("清空列表", func() {
+		files = nil
+		fileList.Refresh()
+	})
+
+	installBtn := widget.NewButton("安装", func() {
+		devices := deviceGroup.Selected
+		if len(devices) == 0 {
+			ui.showMessagePopup("错误", "请至少选择一个目标设备")
+			return
+		}
+		if len(files) == 0 {
+			ui.showMessagePopup("提示", "请先添加要安装的文件")
+			return
+		}
+
+		opts := adb.InstallOptions{
+			Replace:          replaceCheck.Checked,
+			AllowDowngrade:   downgradeCheck.Checked,
+			GrantPermissions: grantCheck.Checked,
+			AllowTest:        testCheck.Checked,
+		}
+		ui.showInstallDialog(append([]string(nil), devices...), append([]string(nil), files...), opts, sessionCheck.Checked)
+	})
+	installBtn.Importance = widget.HighImportance
+
+	content := container.NewVBox(
+		container.NewHBox(addBtn, clearBtn),
+		fileListScroll,
+		hintLabel,
+		widget.NewSeparator(),
+		container.NewBorder(nil, nil, widget.NewLabel("目标设备:"), refreshDevicesBtn, deviceGroup),
+		container.NewGridWithColumns(3, replaceCheck, downgradeCheck, grantCheck, testCheck, sessionCheck),
+		installBtn,
+	)
+
+	return container.NewPadded(content)
+}
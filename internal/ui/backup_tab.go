@@ -0,0 +1,253 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"yikong/internal/adb"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// backupStageNames 备份/恢复阶段的显示名称
+var backupStageNames = map[string]string{
+	adb.BackupStageAPK:     "提取APK",
+	adb.BackupStageData:    "打包数据",
+	adb.BackupStageInstall: "安装APK",
+	adb.BackupStageRestore: "写入数据",
+}
+
+// createBackupTab 通过 run-as 与 tar 流式备份/恢复可调试应用的私有数据，
+// 用于在不同构建或设备之间迁移测试账号与应用状态
+func (ui *UI) createBackupTab() fyne.CanvasObject {
+	statusLabel := widget.NewLabel("")
+	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	packageEntry := widget.NewSelectEntry(nil)
+	packageEntry.SetPlaceHolder("输入或选择包名，如 com.example.app")
+
+	includeAPKCheck := widget.NewCheck("包含APK", nil)
+	includeAPKCheck.SetChecked(true)
+	includeDataCheck := widget.NewCheck("包含应用数据（需要 debuggable 应用）", nil)
+	includeDataCheck.SetChecked(true)
+	excludeCacheCheck := widget.NewCheck("排除缓存目录", nil)
+	excludeCacheCheck.SetChecked(true)
+
+	installAPKCheck := widget.NewCheck("恢复时先安装APK", nil)
+	installAPKCheck.SetChecked(true)
+	clearDataCheck := widget.NewCheck("恢复前清除现有数据", nil)
+	clearDataCheck.SetChecked(true)
+
+	outputDisplay := widget.NewMultiLineEntry()
+	outputDisplay.Wrapping = fyne.TextWrapWord
+	outputDisplay.SetPlaceHolder("备份/恢复输出将显示在这里...")
+	outputDisplay.Disable()
+
+	scrollContainer := container.NewScroll(outputDisplay)
+	scrollContainer.SetMinSize(fyne.NewSize(600, 240))
+
+	appendOutput := func(line string) {
+		fyne.Do(func() {
+			outputDisplay.SetText(outputDisplay.Text + line + "\n")
+			scrollContainer.ScrollToBottom()
+		})
+	}
+
+	progress := func(stage string, bytes int64) {
+		fyne.Do(func() {
+			statusLabel.SetText(fmt.Sprintf("状态: %s... 已传输 %s", backupStageNames[stage], formatSize(bytes)))
+		})
+	}
+
+	loadPackagesBtn := widget.NewButton("加载应用列表", func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+		statusLabel.SetText("状态: 正在加载第三方应用列表...")
+		go func() {
+			packages, err := adb.ListPackages(deviceID, true)
+			fyne.Do(func() {
+				if err != nil {
+					statusLabel.SetText(fmt.Sprintf("状态: 加载应用列表失败 - %v", err))
+					return
+				}
+				packageEntry.SetOptions(packages)
+				statusLabel.SetText(fmt.Sprintf("状态: 已加载 %d 个第三方应用", len(packages)))
+			})
+		}()
+	})
+
+	var backupBtn, restoreBtn *widget.Button
+	setBusy := func(busy bool) {
+		if busy {
+			backupBtn.Disable()
+			restoreBtn.Disable()
+		} else {
+			backupBtn.Enable()
+			restoreBtn.Enable()
+		}
+	}
+
+	runBackup := func(deviceID, packageName, dest string, opts adb.BackupOptions) {
+		setBusy(true)
+		outputDisplay.SetText("")
+		statusLabel.SetText("状态: 正在备份 " + packageName + "...")
+		appendOutput("备份到: " + dest)
+
+		go func() {
+			meta, err := adb.BackupApp(deviceID, packageName, dest, opts, progress)
+			fyne.Do(func() {
+				setBusy(false)
+				if err != nil {
+					logging.Error("应用备份失败: package=%s, 错误: %v", packageName, err)
+					statusLabel.SetText(fmt.Sprintf("状态: 备份失败 - %v", err))
+					ui.showMessagePopup("错误", "备份失败: "+err.Error())
+					return
+				}
+				logging.Info("应用备份完成: package=%s, dest=%s", packageName, dest)
+				if len(meta.APKs) > 0 {
+					appendOutput("APK: " + strings.Join(meta.APKs, ", "))
+				}
+				if meta.HasData {
+					appendOutput("数据: " + formatSize(meta.DataSize))
+				}
+				statusLabel.SetText("状态: 备份完成 - " + packageName)
+			})
+		}()
+	}
+
+	backupBtn = widget.NewButton("备份到文件", func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		packageName := strings.TrimSpace(packageEntry.Text)
+		if packageName == "" {
+			ui.showMessagePopup("提示", "请输入包名")
+			return
+		}
+		opts := adb.BackupOptions{
+			IncludeAPK:   includeAPKCheck.Checked,
+			IncludeData:  includeDataCheck.Checked,
+			ExcludeCache: excludeCacheCheck.Checked,
+		}
+		if !opts.IncludeAPK && !opts.IncludeData {
+			ui.showMessagePopup("提示", "请至少选择备份APK或应用数据")
+			return
+		}
+		deviceID := ui.selectedDevice
+
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			dest := writer.URI().Path()
+			// 归档由 BackupApp 自行创建，这里只取路径
+			writer.Close()
+			if !strings.HasSuffix(dest, adb.BackupExtension) {
+				dest += adb.BackupExtension
+			}
+			runBackup(deviceID, packageName, dest, opts)
+		}, ui.window)
+		saveDialog.SetFileName(fmt.Sprintf("%s_%s%s", packageName, time.Now().Format("20060102_150405"), adb.BackupExtension))
+		saveDialog.Show()
+	})
+	backupBtn.Importance = widget.HighImportance
+
+	runRestore := func(deviceID, archivePath string, opts adb.RestoreOptions) {
+		setBusy(true)
+		outputDisplay.SetText("")
+		statusLabel.SetText("状态: 正在恢复...")
+
+		go func() {
+			err := adb.RestoreApp(deviceID, archivePath, opts, progress, appendOutput)
+			fyne.Do(func() {
+				setBusy(false)
+				if err != nil {
+					logging.Error("应用恢复失败: archive=%s, 错误: %v", archivePath, err)
+					statusLabel.SetText(fmt.Sprintf("状态: 恢复失败 - %v", err))
+					ui.showMessagePopup("错误", "恢复失败: "+err.Error())
+					return
+				}
+				statusLabel.SetText("状态: 恢复完成")
+			})
+		}()
+	}
+
+	restoreBtn = widget.NewButton("从文件恢复", func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+
+		fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			archivePath := reader.URI().Path()
+			reader.Close()
+
+			meta, err := adb.ReadBackupMetadata(archivePath)
+			if err != nil {
+				ui.showMessagePopup("错误", err.Error())
+				return
+			}
+
+			var b strings.Builder
+			fmt.Fprintf(&b, "包名: %s\n", meta.PackageName)
+			fmt.Fprintf(&b, "版本: %s (%d)\n", meta.VersionName, meta.VersionCode)
+			fmt.Fprintf(&b, "来源设备: %s (%s)\n", meta.DeviceModel, meta.DeviceSerial)
+			fmt.Fprintf(&b, "备份时间: %s\n", meta.CreatedAt.Format("2006-01-02 15:04:05"))
+			if len(meta.APKs) > 0 {
+				fmt.Fprintf(&b, "APK: %d 个\n", len(meta.APKs))
+			}
+			if meta.HasData {
+				fmt.Fprintf(&b, "数据: %s\n", formatSize(meta.DataSize))
+			}
+			b.WriteString("\n恢复将覆盖设备上该应用的数据，确定继续吗？")
+
+			opts := adb.RestoreOptions{
+				InstallAPK: installAPKCheck.Checked,
+				ClearData:  clearDataCheck.Checked,
+			}
+			dialog.ShowConfirm("恢复应用备份", b.String(), func(ok bool) {
+				if ok {
+					runRestore(deviceID, archivePath, opts)
+				}
+			}, ui.window)
+		}, ui.window)
+		fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{adb.BackupExtension}))
+		fileDialog.Show()
+	})
+	restoreBtn.Importance = widget.WarningImportance
+
+	hintLabel := widget.NewLabel("提示: 应用数据通过 run-as 读取，仅支持 debuggable 应用；不可调试的应用只能备份APK")
+	hintLabel.Wrapping = fyne.TextWrapWord
+
+	content := container.NewBorder(
+		container.NewVBox(
+			container.NewBorder(nil, nil, nil, loadPackagesBtn, packageEntry),
+			widget.NewLabel("备份选项"),
+			container.NewHBox(includeAPKCheck, includeDataCheck, excludeCacheCheck),
+			widget.NewLabel("恢复选项"),
+			container.NewHBox(installAPKCheck, clearDataCheck),
+			container.NewHBox(backupBtn, restoreBtn),
+			hintLabel,
+			statusLabel,
+			widget.NewSeparator(),
+		),
+		nil, nil, nil,
+		scrollContainer,
+	)
+
+	return container.NewPadded(content)
+}
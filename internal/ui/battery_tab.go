@@ -0,0 +1,308 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"yikong/internal/adb"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// createBatteryTab 电池模拟：解析 dumpsys battery 显示当前状态，模拟电量、充电来源与拔出电源，
+// 并可按脚本逐步降低电量以测试低电量行为
+func (ui *UI) createBatteryTab() fyne.CanvasObject {
+	var drainCancel context.CancelFunc
+
+	statusLabel := widget.NewLabel("")
+	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	levelValue := widget.NewLabel("-")
+	stateValue := widget.NewLabel("-")
+	healthValue := widget.NewLabel("-")
+	tempValue := widget.NewLabel("-")
+	sourceValue := widget.NewLabel("-")
+	simulatedValue := widget.NewLabel("-")
+
+	levelLabel := widget.NewLabel("50%")
+	levelSlider := widget.NewSlider(0, 100)
+	levelSlider.Step = 1
+	levelSlider.SetValue(50)
+	levelSlider.OnChanged = func(value float64) {
+		levelLabel.SetText(fmt.Sprintf("%d%%", int(value)))
+	}
+
+	statusCodes := make([]int, 0, len(adb.BatteryStatusNames))
+	for code := range adb.BatteryStatusNames {
+		statusCodes = append(statusCodes, code)
+	}
+	slices.Sort(statusCodes)
+	statusOptions := make([]string, len(statusCodes))
+	for i, code := range statusCodes {
+		statusOptions[i] = adb.BatteryStatusNames[code]
+	}
+	statusSelect := widget.NewSelect(statusOptions, nil)
+	statusSelect.PlaceHolder = "电池状态"
+
+	sourceNames := map[string]string{
+		adb.PowerSourceAC:       "AC 充电器",
+		adb.PowerSourceUSB:      "USB",
+		adb.PowerSourceWireless: "无线充电",
+	}
+	sourceChecks := make(map[string]*widget.Check)
+
+	// showInfo 显示解析后的电池信息，并同步滑块与开关（直接修改字段，避免触发回调）
+	showInfo := func(info *adb.BatteryInfo) {
+		levelValue.SetText(fmt.Sprintf("%d%%", info.Percent()))
+		stateValue.SetText(adb.BatteryStatusNames[info.Status])
+		healthValue.SetText(adb.BatteryHealthNames[info.Health])
+		tempValue.SetText(fmt.Sprintf("%.1f ℃，%d mV，%s", float64(info.Temperature)/10, info.Voltage, info.Technology))
+
+		powered := map[string]bool{
+			adb.PowerSourceAC:       info.ACPowered,
+			adb.PowerSourceUSB:      info.USBPowered,
+			adb.PowerSourceWireless: info.Wireless,
+		}
+		var sources []string
+		for _, source := range adb.PowerSources {
+			if powered[source] {
+				sources = append(sources, sourceNames[source])
+			}
+			sourceChecks[source].Checked = powered[source]
+			sourceChecks[source].Refresh()
+		}
+		if len(sources) == 0 {
+			sourceValue.SetText("未连接电源")
+		} else {
+			sourceValue.SetText(strings.Join(sources, ", "))
+		}
+		if info.Simulated {
+			simulatedValue.SetText("是（已停止跟随真实电池）")
+		} else {
+			simulatedValue.SetText("否")
+		}
+
+		levelSlider.Value = float64(info.Percent())
+		levelSlider.Refresh()
+		levelLabel.SetText(fmt.Sprintf("%d%%", info.Percent()))
+		statusSelect.Selected = adb.BatteryStatusNames[info.Status]
+		statusSelect.Refresh()
+	}
+
+	var refreshBtn *widget.Button
+	refresh := func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+		refreshBtn.Disable()
+		go func() {
+			info, err := adb.GetBatteryInfo(deviceID)
+			fyne.Do(func() {
+				refreshBtn.Enable()
+				if err != nil {
+					logging.Error("读取电池信息失败: deviceID=%s, 错误: %v", deviceID, err)
+					statusLabel.SetText("状态: 读取电池信息失败 - " + err.Error())
+					return
+				}
+				showInfo(info)
+			})
+		}()
+	}
+	refreshBtn = widget.NewButtonWithIcon("刷新", theme.ViewRefreshIcon(), refresh)
+
+	// run 在后台执行一项模拟操作，完成后刷新电池状态
+	run := func(desc string, change func(deviceID string) error) {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+		statusLabel.SetText(fmt.Sprintf("状态: 正在%s...", desc))
+		go func() {
+			err := change(deviceID)
+			fyne.Do(func() {
+				if err != nil {
+					logging.Error("%s失败: deviceID=%s, 错误: %v", desc, deviceID, err)
+					statusLabel.SetText(fmt.Sprintf("状态: %s失败 - %v", desc, err))
+				} else {
+					logging.Info("%s: deviceID=%s", desc, deviceID)
+					statusLabel.SetText("状态: 已" + desc)
+				}
+				refresh()
+			})
+		}()
+	}
+
+	levelSlider.OnChangeEnded = func(value float64) {
+		level := int(value)
+		run(fmt.Sprintf("模拟电量 %d%%", level), func(deviceID string) error {
+			return adb.SetBatteryLevel(deviceID, level)
+		})
+	}
+	statusBtn := widget.NewButton("设置状态", func() {
+		index := slices.Index(statusOptions, statusSelect.Selected)
+		if index < 0 {
+			ui.showMessagePopup("提示", "请先选择电池状态")
+			return
+		}
+		status := statusCodes[index]
+		run("模拟电池状态为"+statusOptions[index], func(deviceID string) error {
+			return adb.SetBatteryStatus(deviceID, status)
+		})
+	})
+
+	sourceBox := container.NewHBox()
+	for _, source := range adb.PowerSources {
+		check := widget.NewCheck(sourceNames[source], func(connected bool) {
+			action := "断开"
+			if connected {
+				action = "接通"
+			}
+			run(action+sourceNames[source], func(deviceID string) error {
+				return adb.SetPowerSource(deviceID, source, connected)
+			})
+		})
+		sourceChecks[source] = check
+		sourceBox.Add(check)
+	}
+	unplugBtn := widget.NewButtonWithIcon("拔出电源", theme.CancelIcon(), func() {
+		run("模拟拔出电源", adb.UnplugBattery)
+	})
+	resetBtn := widget.NewButtonWithIcon("恢复真实电池", theme.HistoryIcon(), func() {
+		run("恢复真实电池状态", adb.ResetBattery)
+	})
+	resetBtn.Importance = widget.DangerImportance
+
+	// 耗电场景
+	fromEntry := widget.NewEntry()
+	fromEntry.SetText("30")
+	toEntry := widget.NewEntry()
+	toEntry.SetText("1")
+	stepEntry := widget.NewEntry()
+	stepEntry.SetText("1")
+	intervalEntry := widget.NewEntry()
+	intervalEntry.SetText("3")
+	drainProgress := widget.NewProgressBar()
+
+	var startDrainBtn, stopDrainBtn *widget.Button
+	startDrainBtn = widget.NewButtonWithIcon("开始耗电", theme.MediaPlayIcon(), func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		from, errFrom := strconv.Atoi(strings.TrimSpace(fromEntry.Text))
+		to, errTo := strconv.Atoi(strings.TrimSpace(toEntry.Text))
+		step, errStep := strconv.Atoi(strings.TrimSpace(stepEntry.Text))
+		seconds, errInterval := strconv.ParseFloat(strings.TrimSpace(intervalEntry.Text), 64)
+		if errFrom != nil || errTo != nil || errStep != nil || errInterval != nil {
+			ui.showMessagePopup("错误", "请输入有效的数字")
+			return
+		}
+		drain := adb.BatteryDrain{From: from, To: to, Step: step, Interval: time.Duration(seconds * float64(time.Second))}
+		if err := drain.Validate(); err != nil {
+			ui.showMessagePopup("错误", err.Error())
+			return
+		}
+
+		deviceID := ui.selectedDevice
+		ctx, cancel := context.WithCancel(context.Background())
+		drainCancel = cancel
+		startDrainBtn.Disable()
+		stopDrainBtn.Enable()
+		drainProgress.SetValue(0)
+		total := from - to
+		statusLabel.SetText(fmt.Sprintf("状态: 耗电场景开始，%d%% → %d%%", from, to))
+
+		go func() {
+			err := adb.RunBatteryDrain(ctx, deviceID, drain, func(level int) {
+				fyne.Do(func() {
+					levelValue.SetText(fmt.Sprintf("%d%%", level))
+					levelSlider.Value = float64(level)
+					levelSlider.Refresh()
+					levelLabel.SetText(fmt.Sprintf("%d%%", level))
+					if total > 0 {
+						drainProgress.SetValue(float64(from-level) / float64(total))
+					}
+					statusLabel.SetText(fmt.Sprintf("状态: 耗电中，当前电量 %d%%", level))
+				})
+			})
+			stopped := ctx.Err() != nil
+			cancel()
+			fyne.Do(func() {
+				drainCancel = nil
+				startDrainBtn.Enable()
+				stopDrainBtn.Disable()
+				switch {
+				case stopped:
+					statusLabel.SetText("状态: 耗电场景已停止")
+				case err != nil:
+					logging.Error("耗电场景失败: deviceID=%s, 错误: %v", deviceID, err)
+					statusLabel.SetText("状态: 耗电场景失败 - " + err.Error())
+				default:
+					drainProgress.SetValue(1)
+					statusLabel.SetText(fmt.Sprintf("状态: 耗电场景完成，电量已降至 %d%%", to))
+				}
+				refresh()
+			})
+		}()
+	})
+	startDrainBtn.Importance = widget.HighImportance
+	stopDrainBtn = widget.NewButtonWithIcon("停止", theme.MediaStopIcon(), func() {
+		if drainCancel != nil {
+			drainCancel()
+		}
+	})
+	stopDrainBtn.Disable()
+
+	infoCard := widget.NewCard("当前状态", "", widget.NewForm(
+		widget.NewFormItem("电量", levelValue),
+		widget.NewFormItem("状态", stateValue),
+		widget.NewFormItem("健康", healthValue),
+		widget.NewFormItem("温度/电压", tempValue),
+		widget.NewFormItem("电源", sourceValue),
+		widget.NewFormItem("模拟中", simulatedValue),
+	))
+	simulateCard := widget.NewCard("模拟", "", widget.NewForm(
+		widget.NewFormItem("电量", container.NewBorder(nil, nil, nil, levelLabel, levelSlider)),
+		widget.NewFormItem("状态", container.NewBorder(nil, nil, nil, statusBtn, statusSelect)),
+		widget.NewFormItem("充电来源", sourceBox),
+		widget.NewFormItem("", container.NewHBox(unplugBtn, layout.NewSpacer(), resetBtn)),
+	))
+	drainCard := widget.NewCard("耗电场景", "拔出电源后按间隔逐步降低电量，用于测试低电量提醒与省电模式", container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("起始电量 (%)", fromEntry),
+			widget.NewFormItem("结束电量 (%)", toEntry),
+			widget.NewFormItem("每步降低 (%)", stepEntry),
+			widget.NewFormItem("间隔 (秒)", intervalEntry),
+		),
+		drainProgress,
+		container.NewHBox(startDrainBtn, stopDrainBtn),
+	))
+
+	hintLabel := widget.NewLabel("提示: 模拟状态会一直保持到“恢复真实电池”或设备重启；通过 USB 连接时拔出电源不会断开 adb")
+	hintLabel.Wrapping = fyne.TextWrapWord
+
+	if ui.selectedDevice != "" {
+		refresh()
+	}
+
+	return container.NewPadded(container.NewBorder(
+		container.NewVBox(container.NewHBox(refreshBtn), statusLabel, widget.NewSeparator()),
+		hintLabel, nil, nil,
+		container.NewVScroll(container.NewVBox(
+			container.NewGridWithColumns(2, infoCard, simulateCard),
+			drainCard,
+		)),
+	))
+}
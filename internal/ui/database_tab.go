@@ -0,0 +1,490 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"yikong/internal/adb"
+	"yikong/internal/database"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// databasePageSize 数据浏览每页的行数
+const databasePageSize = 100
+
+// newResultTable 创建显示查询结果的表格，第一行为列名；返回表格与设置结果的函数
+func newResultTable() (*widget.Table, func(result *database.Result)) {
+	var current *database.Result
+	table := widget.NewTable(
+		func() (int, int) {
+			if current == nil || len(current.Columns) == 0 {
+				return 0, 0
+			}
+			return len(current.Rows) + 1, len(current.Columns)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.TableCellID, o fyne.CanvasObject) {
+			label := o.(*widget.Label)
+			if id.Row == 0 {
+				label.TextStyle = fyne.TextStyle{Bold: true}
+				label.SetText(current.Columns[id.Col])
+				return
+			}
+			label.TextStyle = fyne.TextStyle{}
+			row := current.Rows[id.Row-1]
+			// 单元格只显示第一行，完整内容通过选中查看
+			label.SetText(strings.SplitN(row[id.Col], "\n", 2)[0])
+		},
+	)
+	setResult := func(result *database.Result) {
+		current = result
+		if result != nil {
+			for i := range result.Columns {
+				table.SetColumnWidth(i, 160)
+			}
+		}
+		table.UnselectAll()
+		table.Refresh()
+		table.ScrollToTop()
+		table.ScrollToLeading()
+	}
+	table.OnSelected = func(id widget.TableCellID) {
+		if current == nil || id.Row == 0 || id.Row > len(current.Rows) {
+			return
+		}
+		// 在剪贴板中放入单元格的完整值，便于查看长文本
+		fyne.CurrentApp().Clipboard().SetContent(current.Rows[id.Row-1][id.Col])
+	}
+	return table, setResult
+}
+
+// createDatabaseTab 数据库查看器：通过 run-as（或 su）拉取应用数据库及 -wal/-shm，
+// 在本地打开后浏览表结构与数据、执行 SQL，修改后可在强制停止应用后写回设备
+func (ui *UI) createDatabaseTab() fyne.CanvasObject {
+	var (
+		db            *database.DB
+		tables        []database.Table
+		current       string // 当前浏览的表
+		offset        int64
+		total         int64
+		openedDevice  string // 当前数据库来源的设备与应用，本地文件时为空
+		openedPackage string
+		openedAccess  adb.FileAccess
+	)
+
+	statusLabel := widget.NewLabel("")
+	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	packageEntry := widget.NewSelectEntry(nil)
+	packageEntry.SetPlaceHolder("输入或选择包名，如 com.example.app")
+
+	databaseSelect := widget.NewSelect(nil, nil)
+	databaseSelect.PlaceHolder = "选择数据库"
+
+	loadPackagesBtn := widget.NewButton("加载应用列表", func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+		statusLabel.SetText("状态: 正在加载第三方应用列表...")
+		go func() {
+			packages, err := adb.ListPackages(deviceID, true)
+			fyne.Do(func() {
+				if err != nil {
+					statusLabel.SetText(fmt.Sprintf("状态: 加载应用列表失败 - %v", err))
+					return
+				}
+				packageEntry.SetOptions(packages)
+				statusLabel.SetText(fmt.Sprintf("状态: 已加载 %d 个第三方应用", len(packages)))
+			})
+		}()
+	})
+
+	listDatabasesBtn := widget.NewButton("列出数据库", func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		pkg := strings.TrimSpace(packageEntry.Text)
+		if pkg == "" {
+			ui.showMessagePopup("提示", "请输入包名")
+			return
+		}
+		device := ui.selectedDevice
+		statusLabel.SetText("状态: 正在列出 " + pkg + " 的数据库...")
+		go func() {
+			fileAccess, err := adb.AppPrivateAccess(device, pkg)
+			var names []string
+			if err == nil {
+				names, err = adb.ListDatabases(device, pkg, fileAccess)
+			}
+			fyne.Do(func() {
+				if err != nil {
+					logging.Error("列出数据库失败: package=%s, 错误: %v", pkg, err)
+					statusLabel.SetText(fmt.Sprintf("状态: 列出数据库失败 - %v", err))
+					return
+				}
+				databaseSelect.Options = names
+				databaseSelect.ClearSelected()
+				if len(names) > 0 {
+					databaseSelect.SetSelectedIndex(0)
+				}
+				statusLabel.SetText(fmt.Sprintf("状态: %s 共 %d 个数据库（%s）", pkg, len(names), fileAccess))
+			})
+		}()
+	})
+
+	// 表列表
+	tableList := widget.NewList(
+		func() int {
+			return len(tables)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("表名")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, widget.NewIcon(theme.ListIcon()), nil, label)
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			row := o.(*fyne.Container)
+			label := row.Objects[0].(*widget.Label)
+			icon := row.Objects[1].(*widget.Icon)
+			label.SetText(tables[i].Name)
+			if tables[i].Type == "view" {
+				icon.SetResource(theme.VisibilityIcon())
+			} else {
+				icon.SetResource(theme.ListIcon())
+			}
+		},
+	)
+
+	// 数据页
+	dataTable, setDataResult := newResultTable()
+	pageLabel := widget.NewLabel("")
+	var prevBtn, nextBtn *widget.Button
+
+	// 结构页
+	schemaDisplay := widget.NewMultiLineEntry()
+	schemaDisplay.TextStyle = fyne.TextStyle{Monospace: true}
+	schemaDisplay.Wrapping = fyne.TextWrapWord
+	schemaDisplay.SetPlaceHolder("选择左侧的表查看结构")
+
+	// SQL 页
+	sqlEntry := widget.NewMultiLineEntry()
+	sqlEntry.TextStyle = fyne.TextStyle{Monospace: true}
+	sqlEntry.SetPlaceHolder("输入 SQL，如 SELECT * FROM users WHERE id < 10")
+	sqlEntry.SetMinRowsVisible(4)
+	sqlTable, setSQLResult := newResultTable()
+	sqlStatusLabel := widget.NewLabel("")
+
+	loadPage := func() {
+		if db == nil || current == "" {
+			return
+		}
+		table, start := current, offset
+		go func() {
+			count, err := db.Count(table)
+			var result *database.Result
+			if err == nil {
+				result, err = db.Page(table, start, databasePageSize)
+			}
+			fyne.Do(func() {
+				if err != nil {
+					statusLabel.SetText(fmt.Sprintf("状态: 读取 %s 失败 - %v", table, err))
+					return
+				}
+				total = count
+				setDataResult(result)
+				end := start + int64(len(result.Rows))
+				pageLabel.SetText(fmt.Sprintf("%d-%d / 共 %d 行", min(start+1, end), end, count))
+				if start > 0 {
+					prevBtn.Enable()
+				} else {
+					prevBtn.Disable()
+				}
+				if end < count {
+					nextBtn.Enable()
+				} else {
+					nextBtn.Disable()
+				}
+			})
+		}()
+	}
+
+	prevBtn = widget.NewButtonWithIcon("上一页", theme.NavigateBackIcon(), func() {
+		offset = max(offset-databasePageSize, 0)
+		loadPage()
+	})
+	nextBtn = widget.NewButtonWithIcon("下一页", theme.NavigateNextIcon(), func() {
+		if offset+databasePageSize < total {
+			offset += databasePageSize
+			loadPage()
+		}
+	})
+	prevBtn.Disable()
+	nextBtn.Disable()
+
+	showSchema := func(t database.Table) {
+		var b strings.Builder
+		b.WriteString(t.SQL)
+		b.WriteString(";\n\n")
+		if columns, err := db.Columns(t.Name); err == nil {
+			for _, c := range columns {
+				fmt.Fprintf(&b, "%-24s %-12s", c.Name, c.Type)
+				if c.PrimaryKey {
+					b.WriteString(" PRIMARY KEY")
+				}
+				if c.NotNull {
+					b.WriteString(" NOT NULL")
+				}
+				if c.Default != "" {
+					b.WriteString(" DEFAULT " + c.Default)
+				}
+				b.WriteString("\n")
+			}
+		}
+		if indexes, err := db.Indexes(t.Name); err == nil && len(indexes) > 0 {
+			b.WriteString("\n")
+			for _, index := range indexes {
+				b.WriteString(index + ";\n")
+			}
+		}
+		schemaDisplay.SetText(b.String())
+	}
+
+	tableList.OnSelected = func(id widget.ListItemID) {
+		t := tables[id]
+		current = t.Name
+		offset = 0
+		showSchema(t)
+		loadPage()
+	}
+
+	resetView := func() {
+		tables = nil
+		current = ""
+		tableList.UnselectAll()
+		tableList.Refresh()
+		setDataResult(nil)
+		setSQLResult(nil)
+		pageLabel.SetText("")
+		schemaDisplay.SetText("")
+		sqlStatusLabel.SetText("")
+		prevBtn.Disable()
+		nextBtn.Disable()
+	}
+
+	// openLocal 打开本地数据库文件，替换当前打开的数据库
+	openLocal := func(path string) error {
+		opened, err := database.Open(path)
+		if err != nil {
+			return err
+		}
+		list, err := opened.Tables()
+		if err != nil {
+			opened.Close()
+			return err
+		}
+		if db != nil {
+			db.Close()
+		}
+		db = opened
+		resetView()
+		tables = list
+		tableList.Refresh()
+		return nil
+	}
+
+	var pushBtn *widget.Button
+
+	pullBtn := widget.NewButton("拉取并打开", func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		pkg := strings.TrimSpace(packageEntry.Text)
+		name := databaseSelect.Selected
+		if pkg == "" || name == "" {
+			ui.showMessagePopup("提示", "请输入包名并选择数据库")
+			return
+		}
+		device := ui.selectedDevice
+		localDir := filepath.Join(os.TempDir(), "yikong", "databases", device, pkg)
+		statusLabel.SetText("状态: 正在拉取 " + name + "...")
+
+		go func() {
+			fileAccess, err := adb.AppPrivateAccess(device, pkg)
+			localPath := ""
+			if err == nil {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+				localPath, err = adb.PullDatabase(ctx, device, pkg, name, localDir, fileAccess)
+				cancel()
+			}
+			fyne.Do(func() {
+				if err == nil {
+					err = openLocal(localPath)
+				}
+				if err != nil {
+					logging.Error("拉取数据库失败: package=%s, db=%s, 错误: %v", pkg, name, err)
+					statusLabel.SetText(fmt.Sprintf("状态: 打开数据库失败 - %v", err))
+					ui.showMessagePopup("错误", "打开数据库失败: "+err.Error())
+					return
+				}
+				openedDevice, openedPackage, openedAccess = device, pkg, fileAccess
+				pushBtn.Enable()
+				logging.Info("已拉取数据库: package=%s, db=%s, local=%s", pkg, name, localPath)
+				statusLabel.SetText(fmt.Sprintf("状态: 已打开 %s（%d 个表），本地副本: %s", name, len(tables), localPath))
+			})
+		}()
+	})
+	pullBtn.Importance = widget.HighImportance
+
+	openFileBtn := widget.NewButton("打开本地文件", func() {
+		fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			path := reader.URI().Path()
+			reader.Close()
+			if err := openLocal(path); err != nil {
+				ui.showMessagePopup("错误", "打开数据库失败: "+err.Error())
+				return
+			}
+			// 本地文件不对应设备上的数据库，不能写回
+			openedDevice, openedPackage = "", ""
+			pushBtn.Disable()
+			statusLabel.SetText(fmt.Sprintf("状态: 已打开 %s（%d 个表）", path, len(tables)))
+		}, ui.window)
+		fileDialog.Show()
+	})
+
+	pushBtn = widget.NewButton("写回设备", func() {
+		if db == nil || openedPackage == "" {
+			return
+		}
+		message := fmt.Sprintf("将本地修改后的 %s 写回 %s 的 databases 目录。\n写入前会强制停止应用，设备上的 -wal/-shm 文件将被删除。确定继续吗？",
+			filepath.Base(db.Path), openedPackage)
+		if !db.Modified() {
+			message = "本地副本没有通过 SQL 修改过。\n" + message
+		}
+		dialog.ShowConfirm("写回数据库", message, func(ok bool) {
+			if !ok {
+				return
+			}
+			localDB, device, pkg, fileAccess := db, openedDevice, openedPackage, openedAccess
+			statusLabel.SetText("状态: 正在写回 " + filepath.Base(localDB.Path) + "...")
+			go func() {
+				err := localDB.Checkpoint()
+				if err == nil {
+					ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+					err = adb.PushDatabase(ctx, device, pkg, localDB.Path, fileAccess)
+					cancel()
+				}
+				fyne.Do(func() {
+					if err != nil {
+						logging.Error("写回数据库失败: package=%s, 错误: %v", pkg, err)
+						statusLabel.SetText(fmt.Sprintf("状态: 写回失败 - %v", err))
+						ui.showMessagePopup("错误", "写回数据库失败: "+err.Error())
+						return
+					}
+					logging.Info("数据库已写回: package=%s, db=%s", pkg, localDB.Path)
+					statusLabel.SetText("状态: 已写回 " + filepath.Base(localDB.Path) + "，应用已被强制停止")
+				})
+			}()
+		}, ui.window)
+	})
+	pushBtn.Importance = widget.WarningImportance
+	pushBtn.Disable()
+
+	runSQLBtn := widget.NewButtonWithIcon("执行", theme.MediaPlayIcon(), func() {
+		if db == nil {
+			ui.showMessagePopup("提示", "请先打开一个数据库")
+			return
+		}
+		statement := sqlEntry.Text
+		localDB := db
+		sqlStatusLabel.SetText("正在执行...")
+		go func() {
+			start := time.Now()
+			result, err := localDB.Execute(statement)
+			elapsed := time.Since(start).Round(time.Millisecond)
+			var list []database.Table
+			if err == nil && len(result.Columns) == 0 {
+				// 语句可能修改了表结构，重新读取表列表
+				list, _ = localDB.Tables()
+			}
+			fyne.Do(func() {
+				if err != nil {
+					sqlStatusLabel.SetText("执行失败: " + err.Error())
+					return
+				}
+				setSQLResult(result)
+				if len(result.Columns) > 0 {
+					sqlStatusLabel.SetText(fmt.Sprintf("%d 行，耗时 %v", len(result.Rows), elapsed))
+					return
+				}
+				sqlStatusLabel.SetText(fmt.Sprintf("影响 %d 行，耗时 %v（修改仅作用于本地副本，需要“写回设备”）", result.RowsAffected, elapsed))
+				if list != nil {
+					tables = list
+					tableList.Refresh()
+				}
+				loadPage()
+			})
+		}()
+	})
+
+	dataPage := container.NewBorder(
+		nil,
+		container.NewHBox(prevBtn, nextBtn, pageLabel),
+		nil, nil,
+		dataTable,
+	)
+	sqlPage := container.NewBorder(
+		container.NewVBox(sqlEntry, container.NewBorder(nil, nil, runSQLBtn, nil, sqlStatusLabel)),
+		nil, nil, nil,
+		sqlTable,
+	)
+	viewTabs := container.NewAppTabs(
+		container.NewTabItem("数据", dataPage),
+		container.NewTabItem("结构", container.NewScroll(schemaDisplay)),
+		container.NewTabItem("SQL", sqlPage),
+	)
+
+	sizer := canvas.NewRectangle(color.Transparent)
+	sizer.SetMinSize(fyne.NewSize(180, 300))
+	split := container.NewHSplit(container.NewStack(sizer, tableList), viewTabs)
+	split.Offset = 0.22
+
+	hintLabel := widget.NewLabel("提示: 数据库通过 run-as（不可调试的应用需 root）读取，在本地副本上浏览和修改；单击单元格复制完整内容")
+	hintLabel.Wrapping = fyne.TextWrapWord
+
+	content := container.NewBorder(
+		container.NewVBox(
+			container.NewBorder(nil, nil, nil, loadPackagesBtn, packageEntry),
+			container.NewBorder(nil, nil, nil, container.NewHBox(listDatabasesBtn, pullBtn, openFileBtn, pushBtn), databaseSelect),
+			hintLabel,
+			statusLabel,
+			widget.NewSeparator(),
+		),
+		nil, nil, nil,
+		split,
+	)
+
+	return container.NewPadded(content)
+}
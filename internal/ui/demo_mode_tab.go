@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"yikong/internal/adb"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 信号强度选项，第一项表示隐藏图标
+var demoSignalOptions = []string{"隐藏", "0", "1", "2", "3", "4"}
+
+// createDemoModeTab 状态栏演示模式：固定时间、电量、信号并隐藏通知图标，用于应用商店截图
+func (ui *UI) createDemoModeTab() fyne.CanvasObject {
+	statusLabel := widget.NewLabel("")
+	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	clockEntry := widget.NewEntry()
+	clockEntry.SetPlaceHolder("HHMM，如 1200")
+	batteryLabel := widget.NewLabel("100%")
+	batterySlider := widget.NewSlider(0, 100)
+	batterySlider.Step = 1
+	batterySlider.OnChanged = func(value float64) {
+		batteryLabel.SetText(fmt.Sprintf("%d%%", int(value)))
+	}
+	pluggedCheck := widget.NewCheck("充电中", nil)
+	wifiSelect := widget.NewSelect(demoSignalOptions, nil)
+	mobileSelect := widget.NewSelect(demoSignalOptions, nil)
+	dataTypeSelect := widget.NewSelect(adb.DemoMobileDataTypes, nil)
+	hideNotificationsCheck := widget.NewCheck("隐藏通知图标", nil)
+	hideStatusIconsCheck := widget.NewCheck("隐藏系统图标（音量、蓝牙、闹钟等）", nil)
+	disallowCheck := widget.NewCheck("退出时同时关闭演示模式开关", nil)
+	disallowCheck.SetChecked(true)
+
+	signalOption := func(level int) string {
+		if level < 0 {
+			return demoSignalOptions[0]
+		}
+		return strconv.Itoa(level)
+	}
+	signalLevel := func(option string) int {
+		if level, err := strconv.Atoi(option); err == nil {
+			return level
+		}
+		return -1
+	}
+
+	showState := func(s adb.DemoState) {
+		clockEntry.SetText(s.Clock)
+		batterySlider.SetValue(float64(s.BatteryLevel))
+		pluggedCheck.SetChecked(s.BatteryPlugged)
+		wifiSelect.SetSelected(signalOption(s.WifiLevel))
+		mobileSelect.SetSelected(signalOption(s.MobileLevel))
+		dataTypeSelect.SetSelected(s.MobileDataType)
+		hideNotificationsCheck.SetChecked(s.HideNotifications)
+		hideStatusIconsCheck.SetChecked(s.HideStatusIcons)
+	}
+	currentState := func() adb.DemoState {
+		return adb.DemoState{
+			Clock:             strings.TrimSpace(clockEntry.Text),
+			BatteryLevel:      int(batterySlider.Value),
+			BatteryPlugged:    pluggedCheck.Checked,
+			WifiLevel:         signalLevel(wifiSelect.Selected),
+			MobileLevel:       signalLevel(mobileSelect.Selected),
+			MobileDataType:    dataTypeSelect.Selected,
+			HideNotifications: hideNotificationsCheck.Checked,
+			HideStatusIcons:   hideStatusIconsCheck.Checked,
+		}
+	}
+
+	presetNames := make([]string, len(adb.DemoPresets))
+	for i, p := range adb.DemoPresets {
+		presetNames[i] = p.Name
+	}
+	presetSelect := widget.NewSelect(presetNames, func(name string) {
+		if index := slices.Index(presetNames, name); index >= 0 {
+			showState(adb.DemoPresets[index].State)
+		}
+	})
+	presetSelect.SetSelected(presetNames[0])
+
+	// run 在后台执行演示模式操作
+	run := func(desc string, action func(deviceID string) error) {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+		statusLabel.SetText(fmt.Sprintf("状态: 正在%s...", desc))
+		go func() {
+			err := action(deviceID)
+			fyne.Do(func() {
+				if err != nil {
+					logging.Error("%s失败: deviceID=%s, 错误: %v", desc, deviceID, err)
+					statusLabel.SetText(fmt.Sprintf("状态: %s失败 - %v", desc, err))
+					return
+				}
+				logging.Info("%s: deviceID=%s", desc, deviceID)
+				statusLabel.SetText("状态: 已" + desc)
+			})
+		}()
+	}
+
+	applyBtn := widget.NewButtonWithIcon("进入演示模式", theme.ConfirmIcon(), func() {
+		state := currentState()
+		if err := state.Validate(); err != nil {
+			ui.showMessagePopup("错误", err.Error())
+			return
+		}
+		run("应用演示状态", func(deviceID string) error {
+			return adb.ApplyDemoState(deviceID, state)
+		})
+	})
+	applyBtn.Importance = widget.HighImportance
+	exitBtn := widget.NewButtonWithIcon("退出演示模式", theme.CancelIcon(), func() {
+		disallow := disallowCheck.Checked
+		run("退出演示模式", func(deviceID string) error {
+			return adb.ExitDemoMode(deviceID, disallow)
+		})
+	})
+
+	form := widget.NewForm(
+		widget.NewFormItem("预设", presetSelect),
+		widget.NewFormItem("时间", clockEntry),
+		widget.NewFormItem("电量", container.NewBorder(nil, nil, nil, container.NewHBox(batteryLabel, pluggedCheck), batterySlider)),
+		widget.NewFormItem("Wi-Fi 信号", wifiSelect),
+		widget.NewFormItem("移动信号", mobileSelect),
+		widget.NewFormItem("网络类型", dataTypeSelect),
+		widget.NewFormItem("", hideNotificationsCheck),
+		widget.NewFormItem("", hideStatusIconsCheck),
+	)
+
+	hintLabel := widget.NewLabel("提示: 演示模式下状态栏显示固定内容，不再反映设备真实状态；截图完成后请退出演示模式")
+	hintLabel.Wrapping = fyne.TextWrapWord
+
+	if ui.selectedDevice != "" {
+		deviceID := ui.selectedDevice
+		go func() {
+			allowed, err := adb.DemoModeAllowed(deviceID)
+			if err != nil {
+				return
+			}
+			fyne.Do(func() {
+				if allowed {
+					statusLabel.SetText("状态: 设备已允许演示模式")
+				} else {
+					statusLabel.SetText("状态: 设备未开启演示模式，应用时会自动允许")
+				}
+			})
+		}()
+	}
+
+	return container.NewPadded(container.NewBorder(
+		container.NewVBox(statusLabel, widget.NewSeparator()),
+		container.NewVBox(
+			widget.NewSeparator(),
+			container.NewHBox(applyBtn, exitBtn, disallowCheck),
+			hintLabel,
+		),
+		nil, nil,
+		container.NewVScroll(form),
+	))
+}
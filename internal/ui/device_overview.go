@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"yikong/internal/adb"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// overviewRow 概览卡片中的一行
+type overviewRow struct {
+	label string
+	value string
+}
+
+// newOverviewCard 以两列表格的形式显示一组信息，值可选中复制
+func newOverviewCard(title string, rows []overviewRow) *widget.Card {
+	form := widget.NewForm()
+	for _, row := range rows {
+		value := row.value
+		if value == "" {
+			value = "-"
+		}
+		label := widget.NewLabel(value)
+		label.Wrapping = fyne.TextWrapBreak
+		label.Selectable = true
+		form.Append(row.label, label)
+	}
+	return widget.NewCard(title, "", form)
+}
+
+// deviceOverviewCards 将设备信息按类别组织为卡片
+func deviceOverviewCards(info *adb.DeviceInfo) []fyne.CanvasObject {
+	rooted := "否"
+	if info.Rooted {
+		rooted = "是"
+	}
+	density := fmt.Sprintf("%d dpi", info.Density)
+	if info.DensityOverride > 0 {
+		density += fmt.Sprintf("（覆盖为 %d dpi）", info.DensityOverride)
+	}
+	screen := ""
+	if info.ScreenWidth > 0 {
+		screen = fmt.Sprintf("%d × %d", info.ScreenWidth, info.ScreenHeight)
+	}
+	ram := ""
+	if info.RAMTotal > 0 {
+		ram = fmt.Sprintf("%s（可用 %s）", formatSize(info.RAMTotal), formatSize(info.RAMAvailable))
+	}
+	storageText := ""
+	if info.StorageTotal > 0 {
+		storageText = fmt.Sprintf("%s（可用 %s）", formatSize(info.StorageTotal), formatSize(info.StorageFree))
+	}
+
+	cards := []fyne.CanvasObject{
+		newOverviewCard("基本信息", []overviewRow{
+			{"型号", info.Model},
+			{"制造商", info.Manufacturer},
+			{"品牌", info.Brand},
+			{"设备代号", info.Device},
+			{"已 root", rooted},
+			{"运行时长", info.Uptime},
+		}),
+		newOverviewCard("系统", []overviewRow{
+			{"Android 版本", fmt.Sprintf("%s（SDK %d）", info.AndroidVersion, info.SDK)},
+			{"安全补丁", info.SecurityPatch},
+			{"版本号", info.BuildID},
+			{"构建类型", info.BuildType},
+			{"内核", info.Kernel},
+			{"构建指纹", info.Fingerprint},
+		}),
+		newOverviewCard("硬件", []overviewRow{
+			{"CPU", info.CPUHardware},
+			{"核心数", fmt.Sprintf("%d", info.CPUCores)},
+			{"ABI", strings.Join(info.ABIs, ", ")},
+			{"内存", ram},
+			{"存储 (/data)", storageText},
+			{"屏幕", screen},
+			{"密度", density},
+		}),
+	}
+	if b := info.Battery; b != nil {
+		charging := []string{}
+		if b.ACPowered {
+			charging = append(charging, "AC")
+		}
+		if b.USBPowered {
+			charging = append(charging, "USB")
+		}
+		if b.Wireless {
+			charging = append(charging, "无线")
+		}
+		source := "未连接电源"
+		if len(charging) > 0 {
+			source = strings.Join(charging, ", ")
+		}
+		cards = append(cards, newOverviewCard("电池", []overviewRow{
+			{"电量", fmt.Sprintf("%d%%", b.Percent())},
+			{"状态", adb.BatteryStatusNames[b.Status]},
+			{"健康", adb.BatteryHealthNames[b.Health]},
+			{"温度", fmt.Sprintf("%.1f ℃", float64(b.Temperature)/10)},
+			{"电压", fmt.Sprintf("%d mV", b.Voltage)},
+			{"电源", source},
+		}))
+	}
+	cards = append(cards, newOverviewCard("标识（不含 IMEI）", []overviewRow{
+		{"序列号", info.Serial},
+		{"Android ID", info.AndroidID},
+	}))
+	return cards
+}
+
+// createDeviceOverview 选中设备后的概览：型号、系统版本、硬件、屏幕、电池等信息，可导出为 JSON
+func (ui *UI) createDeviceOverview() fyne.CanvasObject {
+	deviceID := ui.selectedDevice
+	var info *adb.DeviceInfo
+
+	statusLabel := widget.NewLabel("状态: 正在读取设备信息...")
+	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	cardsContainer := container.NewGridWithColumns(2)
+	scroll := container.NewVScroll(cardsContainer)
+	scroll.SetMinSize(fyne.NewSize(0, 360))
+
+	var refreshBtn, exportBtn *widget.Button
+
+	load := func() {
+		refreshBtn.Disable()
+		exportBtn.Disable()
+		statusLabel.SetText("状态: 正在读取设备信息...")
+		go func() {
+			result, err := adb.GetDeviceInfo(deviceID)
+			fyne.Do(func() {
+				refreshBtn.Enable()
+				if err != nil {
+					logging.Error("读取设备信息失败: deviceID=%s, 错误: %v", deviceID, err)
+					statusLabel.SetText("状态: 读取失败 - " + err.Error())
+					return
+				}
+				info = result
+				cardsContainer.Objects = deviceOverviewCards(info)
+				cardsContainer.Refresh()
+				exportBtn.Enable()
+				statusLabel.SetText(fmt.Sprintf("状态: %s %s（%s）", info.Manufacturer, info.Model, deviceID))
+			})
+		}()
+	}
+
+	refreshBtn = widget.NewButtonWithIcon("刷新", theme.ViewRefreshIcon(), func() { load() })
+	exportBtn = widget.NewButtonWithIcon("导出 JSON", theme.DocumentSaveIcon(), func() {
+		if info == nil {
+			return
+		}
+		snapshot := info
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			dest := writer.URI().Path()
+			writer.Close()
+			if !strings.HasSuffix(dest, ".json") {
+				dest += ".json"
+			}
+			if err := adb.ExportDeviceInfo(dest, snapshot); err != nil {
+				ui.showMessagePopup("错误", "导出失败: "+err.Error())
+				return
+			}
+			statusLabel.SetText("状态: 已导出到 " + dest)
+		}, ui.window)
+		saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+		saveDialog.SetFileName(adb.ExpandFileTemplate("{model}_{serial}_{date}", snapshot.Serial, snapshot.Model, snapshot.CollectedAt) + ".json")
+		saveDialog.Show()
+	})
+
+	if deviceID == "" {
+		statusLabel.SetText("状态: 未选择设备")
+		refreshBtn.Disable()
+		exportBtn.Disable()
+	} else {
+		load()
+	}
+
+	return container.NewBorder(
+		container.NewHBox(statusLabel, layout.NewSpacer(), refreshBtn, exportBtn),
+		nil, nil, nil,
+		scroll,
+	)
+}
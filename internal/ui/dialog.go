@@ -28,3 +28,16 @@ func InfoDialog(window fyne.Window, message string, isAbout bool) error {
 
 	return nil
 }
+
+// showMessagePopup 显示带“确定”按钮的模态提示卡片
+func (ui *UI) showMessagePopup(title string, message string) {
+	var popup *widget.PopUp
+	btn := widget.NewButton("确定", func() {
+		if popup != nil {
+			popup.Hide()
+		}
+	})
+	card := widget.NewCard(title, message, btn)
+	popup = widget.NewModalPopUp(card, ui.window.Canvas())
+	popup.Show()
+}
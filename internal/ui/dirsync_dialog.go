@@ -0,0 +1,241 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"yikong/internal/adb"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 同步方向与比较方式的显示名称
+var (
+	syncDirectionOptions = []string{"推送到设备", "从设备拉取", "双向同步"}
+	syncDirectionValues  = map[string]string{
+		"推送到设备": adb.SyncPush,
+		"从设备拉取": adb.SyncPull,
+		"双向同步":  adb.SyncBidirectional,
+	}
+	syncCompareOptions = []string{"大小 + 修改时间", "MD5", "SHA256"}
+	syncCompareValues  = map[string]string{
+		"大小 + 修改时间": adb.CompareSizeMtime,
+		"MD5":       adb.CompareMD5,
+		"SHA256":    adb.CompareSHA256,
+	}
+	syncActionNames = map[string]string{
+		adb.ActionPush:         "推送",
+		adb.ActionPull:         "拉取",
+		adb.ActionDeleteRemote: "删除设备文件",
+		adb.ActionDeleteLocal:  "删除本地文件",
+	}
+)
+
+// showDirSyncDialog 将本地目录与一台或多台设备上的目录进行同步：
+// 先生成 dry-run 计划供确认，再只传输有变化的文件
+func (ui *UI) showDirSyncDialog(localDir string, remoteDir string) {
+	localEntry := widget.NewEntry()
+	localEntry.SetText(localDir)
+	remoteEntry := widget.NewEntry()
+	remoteEntry.SetText(remoteDir)
+
+	directionRadio := widget.NewRadioGroup(syncDirectionOptions, nil)
+	directionRadio.Horizontal = true
+	directionRadio.SetSelected(syncDirectionOptions[0])
+
+	compareSelect := widget.NewSelect(syncCompareOptions, nil)
+	compareSelect.SetSelected(syncCompareOptions[0])
+
+	deleteExtrasCheck := widget.NewCheck("删除目标端多余的文件（双向同步时无效）", nil)
+
+	var deviceIDs []string
+	for _, d := range ui.devices {
+		deviceIDs = append(deviceIDs, d.ID)
+	}
+	deviceCheck := widget.NewCheckGroup(deviceIDs, nil)
+	deviceCheck.Horizontal = true
+	if ui.selectedDevice != "" {
+		deviceCheck.SetSelected([]string{ui.selectedDevice})
+	}
+
+	planDisplay := widget.NewMultiLineEntry()
+	planDisplay.TextStyle = fyne.TextStyle{Monospace: true}
+	planDisplay.SetPlaceHolder("点击“生成计划”预览将要执行的操作，确认后再执行同步")
+	planDisplay.Disable()
+	planScroll := container.NewScroll(planDisplay)
+	planScroll.SetMinSize(fyne.NewSize(640, 260))
+
+	appendOutput := func(text string) {
+		fyne.Do(func() {
+			planDisplay.SetText(planDisplay.Text + text + "\n")
+			planScroll.ScrollToBottom()
+		})
+	}
+
+	statusLabel := widget.NewLabel("")
+	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	var (
+		plans  []*adb.SyncPlan
+		cancel context.CancelFunc
+		popup  *widget.PopUp
+	)
+	var planBtn, runBtn, cancelBtn, closeBtn *widget.Button
+
+	setBusy := func(busy bool) {
+		if busy {
+			planBtn.Disable()
+			runBtn.Disable()
+			closeBtn.Disable()
+			cancelBtn.Enable()
+		} else {
+			planBtn.Enable()
+			closeBtn.Enable()
+			cancelBtn.Disable()
+			if len(plans) > 0 {
+				runBtn.Enable()
+			}
+		}
+	}
+
+	planBtn = widget.NewButton("生成计划", func() {
+		devices := deviceCheck.Selected
+		local := strings.TrimSpace(localEntry.Text)
+		remote := strings.TrimSpace(remoteEntry.Text)
+		if len(devices) == 0 || local == "" || remote == "" {
+			ui.showMessagePopup("提示", "请填写本地目录、设备目录并至少选择一台设备")
+			return
+		}
+		opts := adb.DirSyncOptions{
+			Direction:    syncDirectionValues[directionRadio.Selected],
+			Compare:      syncCompareValues[compareSelect.Selected],
+			DeleteExtras: deleteExtrasCheck.Checked,
+		}
+
+		plans = nil
+		planDisplay.SetText("")
+		statusLabel.SetText(fmt.Sprintf("正在比较 %d 台设备上的目录...", len(devices)))
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		cancel = cancelFunc
+		setBusy(true)
+
+		go func() {
+			var result []*adb.SyncPlan
+			for _, deviceID := range devices {
+				plan, err := adb.PlanDirSync(ctx, deviceID, local, remote, opts)
+				if err != nil {
+					logging.Error("生成同步计划失败: deviceID=%s, 错误: %v", deviceID, err)
+					appendOutput(fmt.Sprintf("[%s] 生成计划失败: %v", deviceID, err))
+					continue
+				}
+				var b strings.Builder
+				fmt.Fprintf(&b, "[%s] %s\n", deviceID, plan.Summary())
+				for _, action := range plan.Actions {
+					fmt.Fprintf(&b, "  %-8s %s  (%s)\n", syncActionNames[action.Kind], action.RelPath, action.Reason)
+				}
+				appendOutput(b.String())
+				result = append(result, plan)
+			}
+			cancelFunc()
+			fyne.Do(func() {
+				plans = result
+				cancel = nil
+				setBusy(false)
+				statusLabel.SetText(fmt.Sprintf("计划已生成（%d 台设备），确认无误后点击“执行同步”", len(result)))
+			})
+		}()
+	})
+
+	runBtn = widget.NewButton("执行同步", func() {
+		if len(plans) == 0 {
+			return
+		}
+		toRun := plans
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		cancel = cancelFunc
+		setBusy(true)
+		planDisplay.SetText("")
+		statusLabel.SetText(fmt.Sprintf("正在同步 %d 台设备...", len(toRun)))
+
+		go func() {
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			failed := 0
+			for _, plan := range toRun {
+				wg.Add(1)
+				go func(plan *adb.SyncPlan) {
+					defer wg.Done()
+					prefix := "[" + plan.DeviceID + "] "
+					err := adb.ExecuteDirSync(ctx, plan, nil, func(action adb.SyncAction, err error) {
+						if err != nil {
+							appendOutput(fmt.Sprintf("%s%s %s 失败: %v", prefix, syncActionNames[action.Kind], action.RelPath, err))
+						} else {
+							appendOutput(fmt.Sprintf("%s%s %s", prefix, syncActionNames[action.Kind], action.RelPath))
+						}
+					})
+					if err != nil {
+						mu.Lock()
+						failed++
+						mu.Unlock()
+						appendOutput(prefix + "同步失败: " + err.Error())
+						return
+					}
+					appendOutput(prefix + "同步完成")
+				}(plan)
+			}
+			wg.Wait()
+			cancelFunc()
+			fyne.Do(func() {
+				// 执行后计划已过期，需要重新生成
+				plans = nil
+				cancel = nil
+				setBusy(false)
+				runBtn.Disable()
+				if failed > 0 {
+					statusLabel.SetText(fmt.Sprintf("同步结束，%d 台设备存在失败项", failed))
+				} else {
+					statusLabel.SetText("同步完成")
+				}
+			})
+		}()
+	})
+	runBtn.Importance = widget.HighImportance
+	runBtn.Disable()
+
+	cancelBtn = widget.NewButton("取消", func() {
+		if cancel != nil {
+			cancel()
+		}
+	})
+	cancelBtn.Importance = widget.DangerImportance
+	cancelBtn.Disable()
+
+	closeBtn = widget.NewButton("关闭", func() {
+		if popup != nil {
+			popup.Hide()
+		}
+	})
+
+	form := widget.NewForm(
+		widget.NewFormItem("本地目录", localEntry),
+		widget.NewFormItem("设备目录", remoteEntry),
+		widget.NewFormItem("方向", directionRadio),
+		widget.NewFormItem("比较方式", compareSelect),
+		widget.NewFormItem("", deleteExtrasCheck),
+		widget.NewFormItem("设备", deviceCheck),
+	)
+
+	content := container.NewVBox(
+		form,
+		container.NewHBox(planBtn, runBtn, cancelBtn, closeBtn),
+		statusLabel,
+		planScroll,
+	)
+	popup = widget.NewModalPopUp(widget.NewCard("目录同步", "", content), ui.window.Canvas())
+	popup.Show()
+}
@@ -0,0 +1,265 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"yikong/internal/adb"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// createDisplayTab 显示控制：查看和覆盖分辨率、密度，按预设模拟不同尺寸的手机与平板，
+// 控制旋转锁定、强制旋转与字体缩放，并可一键还原
+func (ui *UI) createDisplayTab() fyne.CanvasObject {
+	statusLabel := widget.NewLabel("")
+	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	sizeValue := widget.NewLabel("-")
+	densityValue := widget.NewLabel("-")
+	rotationValue := widget.NewLabel("-")
+	fontScaleValue := widget.NewLabel("-")
+
+	widthEntry := widget.NewEntry()
+	widthEntry.SetPlaceHolder("宽")
+	heightEntry := widget.NewEntry()
+	heightEntry.SetPlaceHolder("高")
+	densityEntry := widget.NewEntry()
+	densityEntry.SetPlaceHolder("dpi")
+
+	autoRotateCheck := widget.NewCheck("自动旋转", nil)
+	rotationSelect := widget.NewSelect(adb.RotationNames, nil)
+	rotationSelect.PlaceHolder = "强制方向"
+
+	fontScaleOptions := make([]string, len(adb.FontScales))
+	for i, scale := range adb.FontScales {
+		fontScaleOptions[i] = strconv.FormatFloat(scale, 'f', -1, 64)
+	}
+	fontScaleSelect := widget.NewSelect(fontScaleOptions, nil)
+	fontScaleSelect.PlaceHolder = "字体缩放"
+
+	// showState 将读取到的状态显示到界面上，自定义输入框填入当前生效的值
+	showState := func(state *adb.DisplayState) {
+		size := fmt.Sprintf("%dx%d", state.PhysicalWidth, state.PhysicalHeight)
+		width, height := state.PhysicalWidth, state.PhysicalHeight
+		if state.OverrideWidth > 0 {
+			size = fmt.Sprintf("%dx%d（物理 %s）", state.OverrideWidth, state.OverrideHeight, size)
+			width, height = state.OverrideWidth, state.OverrideHeight
+		}
+		sizeValue.SetText(size)
+
+		density := fmt.Sprintf("%d dpi", state.PhysicalDensity)
+		current := state.PhysicalDensity
+		if state.OverrideDensity > 0 {
+			density = fmt.Sprintf("%d dpi（物理 %s）", state.OverrideDensity, density)
+			current = state.OverrideDensity
+		}
+		densityValue.SetText(density)
+
+		rotation := adb.RotationNames[state.Rotation]
+		if state.AutoRotate {
+			rotation += "，自动旋转"
+		} else {
+			rotation += "，已锁定为 " + adb.RotationNames[state.UserRotation]
+		}
+		rotationValue.SetText(rotation)
+		fontScaleValue.SetText(strconv.FormatFloat(state.FontScale, 'f', -1, 64))
+
+		widthEntry.SetText(strconv.Itoa(width))
+		heightEntry.SetText(strconv.Itoa(height))
+		densityEntry.SetText(strconv.Itoa(current))
+		// 直接修改字段而不调用 SetChecked，避免显示状态时触发 OnChanged
+		autoRotateCheck.Checked = state.AutoRotate
+		autoRotateCheck.Refresh()
+		fontScaleSelect.Selected = strconv.FormatFloat(state.FontScale, 'f', -1, 64)
+		fontScaleSelect.Refresh()
+	}
+
+	var refreshBtn *widget.Button
+	refresh := func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+		refreshBtn.Disable()
+		go func() {
+			state, err := adb.GetDisplayState(deviceID)
+			fyne.Do(func() {
+				refreshBtn.Enable()
+				if err != nil {
+					logging.Error("读取显示状态失败: deviceID=%s, 错误: %v", deviceID, err)
+					statusLabel.SetText("状态: 读取显示状态失败 - " + err.Error())
+					return
+				}
+				showState(state)
+			})
+		}()
+	}
+	refreshBtn = widget.NewButtonWithIcon("刷新", theme.ViewRefreshIcon(), refresh)
+
+	// run 在后台执行一项修改，完成后刷新当前状态
+	run := func(desc string, change func(deviceID string) error) {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+		statusLabel.SetText(fmt.Sprintf("状态: 正在%s...", desc))
+		go func() {
+			err := change(deviceID)
+			fyne.Do(func() {
+				if err != nil {
+					logging.Error("%s失败: deviceID=%s, 错误: %v", desc, deviceID, err)
+					statusLabel.SetText(fmt.Sprintf("状态: %s失败 - %v", desc, err))
+				} else {
+					logging.Info("%s: deviceID=%s", desc, deviceID)
+					statusLabel.SetText("状态: 已" + desc)
+				}
+				refresh()
+			})
+		}()
+	}
+
+	// 预设
+	presetNames := make([]string, len(adb.DisplayPresets))
+	for i, p := range adb.DisplayPresets {
+		presetNames[i] = p.String()
+	}
+	presetSelect := widget.NewSelect(presetNames, nil)
+	presetSelect.PlaceHolder = "选择手机或平板预设"
+	landscapeCheck := widget.NewCheck("横屏尺寸", nil)
+	applyPresetBtn := widget.NewButtonWithIcon("应用预设", theme.ConfirmIcon(), func() {
+		index := slices.Index(presetNames, presetSelect.Selected)
+		if index < 0 {
+			ui.showMessagePopup("提示", "请先选择一个预设")
+			return
+		}
+		preset := adb.DisplayPresets[index]
+		width, height := preset.Width, preset.Height
+		if landscapeCheck.Checked {
+			width, height = height, width
+		}
+		run(fmt.Sprintf("应用预设 %s", preset.Name), func(deviceID string) error {
+			if err := adb.SetDisplaySize(deviceID, width, height); err != nil {
+				return err
+			}
+			return adb.SetDensity(deviceID, preset.Density)
+		})
+	})
+	applyPresetBtn.Importance = widget.HighImportance
+
+	// 自定义
+	applySizeBtn := widget.NewButton("设置分辨率", func() {
+		width, errW := strconv.Atoi(strings.TrimSpace(widthEntry.Text))
+		height, errH := strconv.Atoi(strings.TrimSpace(heightEntry.Text))
+		if errW != nil || errH != nil {
+			ui.showMessagePopup("错误", "宽和高应为整数")
+			return
+		}
+		run(fmt.Sprintf("设置分辨率为 %dx%d", width, height), func(deviceID string) error {
+			return adb.SetDisplaySize(deviceID, width, height)
+		})
+	})
+	resetSizeBtn := widget.NewButton("恢复", func() {
+		run("恢复物理分辨率", adb.ResetDisplaySize)
+	})
+	applyDensityBtn := widget.NewButton("设置密度", func() {
+		density, err := strconv.Atoi(strings.TrimSpace(densityEntry.Text))
+		if err != nil {
+			ui.showMessagePopup("错误", "密度应为整数")
+			return
+		}
+		run(fmt.Sprintf("设置密度为 %d dpi", density), func(deviceID string) error {
+			return adb.SetDensity(deviceID, density)
+		})
+	})
+	resetDensityBtn := widget.NewButton("恢复", func() {
+		run("恢复物理密度", adb.ResetDensity)
+	})
+
+	// 旋转与字体
+	autoRotateCheck.OnChanged = func(checked bool) {
+		if checked {
+			run("开启自动旋转", func(deviceID string) error { return adb.SetAutoRotate(deviceID, true) })
+		} else {
+			run("锁定屏幕方向", func(deviceID string) error { return adb.SetAutoRotate(deviceID, false) })
+		}
+	}
+	rotateBtn := widget.NewButtonWithIcon("强制旋转", theme.ViewRestoreIcon(), func() {
+		rotation := slices.Index(adb.RotationNames, rotationSelect.Selected)
+		if rotation < 0 {
+			ui.showMessagePopup("提示", "请先选择方向")
+			return
+		}
+		run("旋转到 "+adb.RotationNames[rotation], func(deviceID string) error {
+			return adb.SetRotation(deviceID, rotation)
+		})
+	})
+	fontScaleBtn := widget.NewButton("设置字体缩放", func() {
+		scale, err := strconv.ParseFloat(fontScaleSelect.Selected, 64)
+		if err != nil {
+			ui.showMessagePopup("提示", "请先选择字体缩放")
+			return
+		}
+		run(fmt.Sprintf("设置字体缩放为 %g", scale), func(deviceID string) error {
+			return adb.SetFontScale(deviceID, scale)
+		})
+	})
+
+	resetAllBtn := widget.NewButtonWithIcon("一键还原", theme.HistoryIcon(), func() {
+		run("还原分辨率、密度、字体缩放与自动旋转", adb.ResetDisplay)
+	})
+	resetAllBtn.Importance = widget.DangerImportance
+
+	stateCard := widget.NewCard("当前状态", "", widget.NewForm(
+		widget.NewFormItem("分辨率", sizeValue),
+		widget.NewFormItem("密度", densityValue),
+		widget.NewFormItem("方向", rotationValue),
+		widget.NewFormItem("字体缩放", fontScaleValue),
+	))
+	presetCard := widget.NewCard("预设", "", container.NewVBox(
+		presetSelect,
+		container.NewHBox(landscapeCheck, layout.NewSpacer(), applyPresetBtn),
+	))
+	customCard := widget.NewCard("自定义", "", widget.NewForm(
+		widget.NewFormItem("分辨率", container.NewBorder(nil, nil, nil,
+			container.NewHBox(applySizeBtn, resetSizeBtn),
+			container.NewGridWithColumns(2, widthEntry, heightEntry))),
+		widget.NewFormItem("密度", container.NewBorder(nil, nil, nil,
+			container.NewHBox(applyDensityBtn, resetDensityBtn),
+			densityEntry)),
+	))
+	rotationCard := widget.NewCard("旋转与字体", "", widget.NewForm(
+		widget.NewFormItem("旋转锁定", autoRotateCheck),
+		widget.NewFormItem("强制方向", container.NewBorder(nil, nil, nil, rotateBtn, rotationSelect)),
+		widget.NewFormItem("字体缩放", container.NewBorder(nil, nil, nil, fontScaleBtn, fontScaleSelect)),
+	))
+
+	hintLabel := widget.NewLabel("提示: 修改分辨率和密度后，部分应用需要重启才会按新的尺寸布局；测试结束后请使用“一键还原”")
+	hintLabel.Wrapping = fyne.TextWrapWord
+
+	if ui.selectedDevice != "" {
+		refresh()
+	}
+
+	return container.NewPadded(container.NewBorder(
+		container.NewVBox(
+			container.NewHBox(refreshBtn, resetAllBtn),
+			statusLabel,
+			widget.NewSeparator(),
+		),
+		hintLabel, nil, nil,
+		container.NewVScroll(container.NewVBox(
+			container.NewGridWithColumns(2, stateCard, presetCard),
+			container.NewGridWithColumns(2, customCard, rotationCard),
+		)),
+	))
+}
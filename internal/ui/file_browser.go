@@ -0,0 +1,389 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"yikong/internal/adb"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// fileSystem 文件浏览器一侧面板所操作的文件系统（本地或设备）
+type fileSystem interface {
+	List(dir string) ([]adb.FileEntry, error)
+	Join(dir, name string) string
+	Parent(dir string) string
+	MakeDir(dir string) error
+	Rename(from, to string) error
+	Remove(target string) error
+	ReadHead(target string, limit int) ([]byte, error)
+}
+
+// localFileSystem 本机文件系统
+type localFileSystem struct{}
+
+func (localFileSystem) List(dir string) ([]adb.FileEntry, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]adb.FileEntry, 0, len(dirEntries))
+	for _, d := range dirEntries {
+		info, err := d.Info()
+		if err != nil {
+			continue
+		}
+		// 指向目录的符号链接按目录处理，便于进入
+		if info.Mode()&os.ModeSymlink != 0 {
+			if target, err := os.Stat(filepath.Join(dir, d.Name())); err == nil {
+				info = target
+			}
+		}
+		entries = append(entries, adb.FileEntry{Name: d.Name(), Size: info.Size(), Mode: info.Mode(), ModTime: info.ModTime()})
+	}
+	adb.SortFileEntries(entries)
+	return entries, nil
+}
+
+func (localFileSystem) Join(dir, name string) string { return filepath.Join(dir, name) }
+func (localFileSystem) Parent(dir string) string     { return filepath.Dir(filepath.Clean(dir)) }
+func (localFileSystem) MakeDir(dir string) error     { return os.MkdirAll(dir, 0755) }
+func (localFileSystem) Rename(from, to string) error { return os.Rename(from, to) }
+func (localFileSystem) Remove(target string) error   { return os.RemoveAll(target) }
+
+func (localFileSystem) ReadHead(target string, limit int) ([]byte, error) {
+	file, err := os.Open(target)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(io.LimitReader(file, int64(limit)))
+}
+
+// 设备文件访问方式选项
+const (
+	deviceAccessAuto   = "自动"
+	deviceAccessDirect = "直接访问"
+	deviceAccessRunAs  = "run-as"
+	deviceAccessSu     = "su"
+)
+
+var deviceAccessOptions = []string{deviceAccessAuto, deviceAccessDirect, deviceAccessRunAs, deviceAccessSu}
+
+// deviceFileSystem 当前选中设备的文件系统，每次操作时读取 ui.selectedDevice。
+// 应用私有目录通过 run-as（或 root 设备上的 su）访问。
+// 列目录在后台 goroutine 中执行，accessMode 与 lastAccess 由 mu 保护
+type deviceFileSystem struct {
+	ui         *UI
+	mu         sync.Mutex
+	accessMode string
+	lastAccess adb.FileAccess
+}
+
+// SetAccessMode 设置访问方式，在 UI 线程调用
+func (d *deviceFileSystem) SetAccessMode(mode string) {
+	d.mu.Lock()
+	d.accessMode = mode
+	d.mu.Unlock()
+}
+
+// accessFor 根据访问方式设置为路径选择访问身份
+func (d *deviceFileSystem) accessFor(p string) (string, adb.FileAccess, error) {
+	deviceID := d.ui.selectedDevice
+	if deviceID == "" {
+		return "", adb.FileAccess{}, fmt.Errorf("请先选择一个设备")
+	}
+	d.mu.Lock()
+	mode := d.accessMode
+	d.mu.Unlock()
+	switch mode {
+	case deviceAccessDirect:
+		return deviceID, adb.FileAccess{Mode: adb.AccessDirect}, nil
+	case deviceAccessRunAs:
+		pkg := adb.AppPackageFromPath(p)
+		if pkg == "" {
+			return "", adb.FileAccess{}, fmt.Errorf("run-as 只能访问应用私有目录 /data/data/<包名>")
+		}
+		return deviceID, adb.FileAccess{Mode: adb.AccessRunAs, Package: pkg}, nil
+	case deviceAccessSu:
+		return deviceID, adb.FileAccess{Mode: adb.AccessSu}, nil
+	}
+	return deviceID, adb.DetectFileAccess(deviceID, p), nil
+}
+
+func (d *deviceFileSystem) List(dir string) ([]adb.FileEntry, error) {
+	deviceID, access, err := d.accessFor(dir)
+	if err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	d.lastAccess = access
+	d.mu.Unlock()
+	return adb.ListDirAs(deviceID, dir, access)
+}
+
+// AccessLabel 最近一次列目录使用的访问身份
+func (d *deviceFileSystem) AccessLabel() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lastAccess.String()
+}
+
+func (*deviceFileSystem) Join(dir, name string) string { return path.Join(dir, name) }
+func (*deviceFileSystem) Parent(dir string) string     { return path.Dir(path.Clean(dir)) }
+
+func (d *deviceFileSystem) MakeDir(dir string) error {
+	deviceID, access, err := d.accessFor(dir)
+	if err != nil {
+		return err
+	}
+	return adb.MakeDirAs(deviceID, dir, access)
+}
+
+func (d *deviceFileSystem) Rename(from, to string) error {
+	deviceID, access, err := d.accessFor(from)
+	if err != nil {
+		return err
+	}
+	return adb.RenamePathAs(deviceID, from, to, access)
+}
+
+func (d *deviceFileSystem) Remove(target string) error {
+	deviceID, access, err := d.accessFor(target)
+	if err != nil {
+		return err
+	}
+	return adb.RemovePathAs(deviceID, target, access)
+}
+
+func (d *deviceFileSystem) ReadHead(target string, limit int) ([]byte, error) {
+	deviceID, access, err := d.accessFor(target)
+	if err != nil {
+		return nil, err
+	}
+	return adb.ReadFileHeadAs(deviceID, target, limit, access)
+}
+
+// fileRow 文件列表中的一行，支持单击选中、双击打开以及拖动到另一侧面板
+type fileRow struct {
+	widget.BaseWidget
+
+	icon    *widget.Icon
+	name    *widget.Label
+	details *widget.Label
+
+	onTapped       func()
+	onDoubleTapped func()
+	onDragEnd      func(pos fyne.Position)
+	dragPos        fyne.Position
+}
+
+func newFileRow() *fileRow {
+	row := &fileRow{
+		icon:    widget.NewIcon(theme.FileIcon()),
+		name:    widget.NewLabel("文件名"),
+		details: widget.NewLabel(""),
+	}
+	row.name.Truncation = fyne.TextTruncateEllipsis
+	row.details.TextStyle = fyne.TextStyle{Monospace: true}
+	row.ExtendBaseWidget(row)
+	return row
+}
+
+func (r *fileRow) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewBorder(nil, nil, r.icon, r.details, r.name))
+}
+
+func (r *fileRow) setEntry(entry adb.FileEntry) {
+	switch {
+	case entry.IsDir():
+		r.icon.SetResource(theme.FolderIcon())
+	case entry.Mode&os.ModeSymlink != 0:
+		r.icon.SetResource(theme.FolderOpenIcon())
+	default:
+		r.icon.SetResource(theme.FileIcon())
+	}
+	r.name.SetText(entry.Name)
+
+	size := ""
+	if !entry.IsDir() {
+		size = formatSize(entry.Size)
+	}
+	modTime := ""
+	if !entry.ModTime.IsZero() && entry.ModTime.Unix() > 0 {
+		modTime = entry.ModTime.Format("2006-01-02 15:04")
+	}
+	r.details.SetText(fmt.Sprintf("%10s  %s  %16s", size, entry.Mode.String(), modTime))
+}
+
+func (r *fileRow) Tapped(*fyne.PointEvent) {
+	if r.onTapped != nil {
+		r.onTapped()
+	}
+}
+
+func (r *fileRow) DoubleTapped(*fyne.PointEvent) {
+	if r.onDoubleTapped != nil {
+		r.onDoubleTapped()
+	}
+}
+
+func (r *fileRow) Dragged(ev *fyne.DragEvent) {
+	r.dragPos = ev.AbsolutePosition
+}
+
+func (r *fileRow) DragEnd() {
+	if r.onDragEnd != nil {
+		r.onDragEnd(r.dragPos)
+	}
+}
+
+// filePane 文件浏览器的一侧面板
+type filePane struct {
+	fs       fileSystem
+	dir      string
+	entries  []adb.FileEntry
+	selected int
+
+	pathEntry *widget.Entry
+	list      *widget.List
+	status    *widget.Label
+	root      fyne.CanvasObject
+
+	onError   func(err error)
+	onOpen    func(entry adb.FileEntry, fullPath string)
+	onDragOut func(entry adb.FileEntry, fullPath string, pos fyne.Position)
+}
+
+func newFilePane(title string, fs fileSystem, toolbar ...fyne.CanvasObject) *filePane {
+	p := &filePane{fs: fs, selected: -1}
+
+	titleLabel := widget.NewLabel(title)
+	titleLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	p.status = widget.NewLabel("")
+	p.status.TextStyle = fyne.TextStyle{Italic: true}
+	p.status.Truncation = fyne.TextTruncateEllipsis
+
+	p.pathEntry = widget.NewEntry()
+	p.pathEntry.OnSubmitted = func(dir string) {
+		p.load(dir)
+	}
+
+	upBtn := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() {
+		p.load(p.fs.Parent(p.dir))
+	})
+	refreshBtn := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), func() {
+		p.load(p.dir)
+	})
+
+	p.list = widget.NewList(
+		func() int {
+			return len(p.entries)
+		},
+		func() fyne.CanvasObject {
+			return newFileRow()
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			row := o.(*fileRow)
+			entry := p.entries[i]
+			row.setEntry(entry)
+			row.onTapped = func() {
+				p.list.Select(i)
+			}
+			row.onDoubleTapped = func() {
+				p.list.Select(i)
+				p.open(entry)
+			}
+			row.onDragEnd = func(pos fyne.Position) {
+				if p.onDragOut != nil && !p.containsPoint(pos) {
+					p.onDragOut(entry, p.fs.Join(p.dir, entry.Name), pos)
+				}
+			}
+		},
+	)
+	p.list.OnSelected = func(id widget.ListItemID) {
+		p.selected = id
+	}
+	p.list.OnUnselected = func(widget.ListItemID) {
+		p.selected = -1
+	}
+
+	header := container.NewVBox(
+		container.NewBorder(nil, nil, titleLabel, container.NewHBox(upBtn, refreshBtn), p.pathEntry),
+		container.NewHBox(toolbar...),
+	)
+	p.root = container.NewBorder(header, p.status, nil, nil, p.list)
+	return p
+}
+
+// load 在后台列出目录，成功后切换到该目录
+func (p *filePane) load(dir string) {
+	if dir == "" {
+		return
+	}
+	p.status.SetText("正在加载 " + dir + " ...")
+	go func() {
+		entries, err := p.fs.List(dir)
+		fyne.Do(func() {
+			if err != nil {
+				p.status.SetText("加载失败: " + err.Error())
+				if p.onError != nil {
+					p.onError(err)
+				}
+				return
+			}
+			p.dir = dir
+			p.entries = entries
+			p.selected = -1
+			p.list.UnselectAll()
+			p.list.Refresh()
+			p.list.ScrollToTop()
+			p.pathEntry.SetText(dir)
+			status := fmt.Sprintf("%d 项", len(entries))
+			if d, ok := p.fs.(interface{ AccessLabel() string }); ok {
+				status += " · " + d.AccessLabel()
+			}
+			p.status.SetText(status)
+		})
+	}()
+}
+
+// open 目录（或符号链接）进入，文件交给 onOpen 预览
+func (p *filePane) open(entry adb.FileEntry) {
+	fullPath := p.fs.Join(p.dir, entry.Name)
+	if entry.IsDir() || entry.Mode&os.ModeSymlink != 0 {
+		p.load(fullPath)
+		return
+	}
+	if p.onOpen != nil {
+		p.onOpen(entry, fullPath)
+	}
+}
+
+// selectedEntry 返回当前选中的条目及其完整路径
+func (p *filePane) selectedEntry() (adb.FileEntry, string, bool) {
+	if p.selected < 0 || p.selected >= len(p.entries) {
+		return adb.FileEntry{}, "", false
+	}
+	entry := p.entries[p.selected]
+	return entry, p.fs.Join(p.dir, entry.Name), true
+}
+
+// containsPoint 判断窗口坐标是否落在面板范围内
+func (p *filePane) containsPoint(pos fyne.Position) bool {
+	if !p.root.Visible() {
+		return false
+	}
+	origin := fyne.CurrentApp().Driver().AbsolutePositionForObject(p.root)
+	size := p.root.Size()
+	return pos.X >= origin.X && pos.X <= origin.X+size.Width &&
+		pos.Y >= origin.Y && pos.Y <= origin.Y+size.Height
+}
@@ -0,0 +1,353 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+	"yikong/internal/adb"
+	"yikong/internal/constants"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 预览读取的最大字节数
+const (
+	textPreviewLimit  = 512 * 1024
+	imagePreviewLimit = 16 * 1024 * 1024
+)
+
+// 可预览的图片扩展名
+var imageExtensions = []string{".png", ".jpg", ".jpeg", ".gif", ".bmp", ".webp"}
+
+func isImageFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, e := range imageExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// createFileTransferPage 双栏文件管理器：左侧本地、右侧设备，
+// 通过按钮或将文件行拖到另一侧面板进行推送/拉取
+func (ui *UI) createFileTransferPage() fyne.CanvasObject {
+	var localPane, devicePane *filePane
+
+	deviceFS := &deviceFileSystem{ui: ui, accessMode: deviceAccessAuto}
+
+	// transfer 将推送或拉取加入传输队列，完成后刷新目标面板。
+	// 设备路径的访问身份（直接/run-as/su）在后台确定，可能需要执行探测命令
+	transfer := func(upload bool, source string, targetDir string) {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		direction := adb.SyncPull
+		devicePath := source
+		if upload {
+			direction = adb.SyncPush
+			devicePath = targetDir
+		}
+		go func() {
+			deviceID, access, err := deviceFS.accessFor(devicePath)
+			if err != nil {
+				fyne.Do(func() {
+					ui.showMessagePopup("错误", err.Error())
+				})
+				return
+			}
+			ui.transferManager().Enqueue(deviceID, direction, source, targetDir, access, func(err error) {
+				if err != nil {
+					return
+				}
+				fyne.Do(func() {
+					if upload {
+						devicePane.load(devicePane.dir)
+					} else {
+						localPane.load(localPane.dir)
+					}
+				})
+			})
+		}()
+	}
+
+	accessSelect := widget.NewSelect(deviceAccessOptions, func(mode string) {
+		deviceFS.SetAccessMode(mode)
+		if devicePane != nil && devicePane.dir != "" {
+			devicePane.load(devicePane.dir)
+		}
+	})
+	accessSelect.SetSelected(deviceAccessAuto)
+
+	// 每侧面板的通用操作按钮
+	paneActions := func(pane **filePane) []fyne.CanvasObject {
+		mkdirBtn := widget.NewButtonWithIcon("新建文件夹", theme.FolderNewIcon(), func() {
+			p := *pane
+			nameEntry := widget.NewEntry()
+			dialog.ShowForm("新建文件夹", "创建", "取消", []*widget.FormItem{widget.NewFormItem("名称", nameEntry)}, func(ok bool) {
+				name := strings.TrimSpace(nameEntry.Text)
+				if !ok || name == "" {
+					return
+				}
+				ui.runFileAction(p, "新建文件夹", func() error {
+					return p.fs.MakeDir(p.fs.Join(p.dir, name))
+				})
+			}, ui.window)
+		})
+		renameBtn := widget.NewButtonWithIcon("重命名", theme.DocumentCreateIcon(), func() {
+			p := *pane
+			entry, fullPath, ok := p.selectedEntry()
+			if !ok {
+				return
+			}
+			nameEntry := widget.NewEntry()
+			nameEntry.SetText(entry.Name)
+			dialog.ShowForm("重命名", "确定", "取消", []*widget.FormItem{widget.NewFormItem("新名称", nameEntry)}, func(ok bool) {
+				name := strings.TrimSpace(nameEntry.Text)
+				if !ok || name == "" || name == entry.Name {
+					return
+				}
+				ui.runFileAction(p, "重命名", func() error {
+					return p.fs.Rename(fullPath, p.fs.Join(p.dir, name))
+				})
+			}, ui.window)
+		})
+		deleteBtn := widget.NewButtonWithIcon("删除", theme.DeleteIcon(), func() {
+			p := *pane
+			entry, fullPath, ok := p.selectedEntry()
+			if !ok {
+				return
+			}
+			dialog.ShowConfirm("删除", "确定删除 "+fullPath+" 吗？此操作不可撤销", func(ok bool) {
+				if !ok {
+					return
+				}
+				ui.runFileAction(p, "删除 "+entry.Name, func() error {
+					return p.fs.Remove(fullPath)
+				})
+			}, ui.window)
+		})
+		deleteBtn.Importance = widget.DangerImportance
+		previewBtn := widget.NewButtonWithIcon("预览", theme.VisibilityIcon(), func() {
+			p := *pane
+			if entry, _, ok := p.selectedEntry(); ok {
+				p.open(entry)
+			}
+		})
+		return []fyne.CanvasObject{mkdirBtn, renameBtn, deleteBtn, previewBtn}
+	}
+
+	uploadBtn := widget.NewButtonWithIcon("推送到设备", theme.NavigateNextIcon(), func() {
+		if _, fullPath, ok := localPane.selectedEntry(); ok {
+			transfer(true, fullPath, devicePane.dir)
+		}
+	})
+	uploadBtn.Importance = widget.HighImportance
+	downloadBtn := widget.NewButtonWithIcon("拉取到本地", theme.NavigateBackIcon(), func() {
+		if _, fullPath, ok := devicePane.selectedEntry(); ok {
+			transfer(false, fullPath, localPane.dir)
+		}
+	})
+	downloadBtn.Importance = widget.HighImportance
+
+	localPane = newFilePane("本地", localFileSystem{}, append(paneActions(&localPane), uploadBtn)...)
+	devicePane = newFilePane("设备", deviceFS, append(paneActions(&devicePane), downloadBtn, accessSelect)...)
+
+	localPane.onOpen = func(entry adb.FileEntry, fullPath string) {
+		ui.previewFile(localPane.fs, entry, fullPath)
+	}
+	devicePane.onOpen = func(entry adb.FileEntry, fullPath string) {
+		ui.previewFile(devicePane.fs, entry, fullPath)
+	}
+	localPane.onDragOut = func(_ adb.FileEntry, fullPath string, pos fyne.Position) {
+		if devicePane.containsPoint(pos) {
+			transfer(true, fullPath, devicePane.dir)
+		}
+	}
+	devicePane.onDragOut = func(_ adb.FileEntry, fullPath string, pos fyne.Position) {
+		if localPane.containsPoint(pos) {
+			transfer(false, fullPath, localPane.dir)
+		}
+	}
+
+	// 从系统文件管理器拖入设备面板的文件直接推送到当前目录，其余位置仍按拖放安装处理
+	ui.fileDropTarget = func(pos fyne.Position, uris []fyne.URI) bool {
+		if !devicePane.containsPoint(pos) {
+			return false
+		}
+		for _, uri := range uris {
+			if uri.Scheme() == "file" {
+				transfer(true, uri.Path(), devicePane.dir)
+				// 传输串行执行，一次只处理一个拖入项
+				break
+			}
+		}
+		return true
+	}
+
+	syncBtn := widget.NewButtonWithIcon("目录同步", theme.ViewRefreshIcon(), func() {
+		ui.showDirSyncDialog(localPane.dir, devicePane.dir)
+	})
+
+	split := container.NewHSplit(localPane.root, devicePane.root)
+	split.Offset = 0.5
+	// 列表本身的最小高度只有一行，用占位矩形撑开面板
+	sizer := canvas.NewRectangle(color.Transparent)
+	sizer.SetMinSize(fyne.NewSize(0, 420))
+
+	content := container.NewBorder(
+		nil,
+		container.NewVBox(
+			widget.NewSeparator(),
+			container.NewHBox(syncBtn),
+			ui.createTransferPanel(),
+		),
+		ui.createFileShortcuts(func(dir string) {
+			devicePane.load(dir)
+		}),
+		nil,
+		container.NewStack(sizer, split),
+	)
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "."
+	}
+	localPane.load(home)
+	if ui.selectedDevice != "" {
+		devicePane.load(constants.PathSDCard)
+	}
+
+	return container.NewPadded(content)
+}
+
+// createFileShortcuts 设备常用目录的快捷入口，应用相关目录需要先填写包名
+func (ui *UI) createFileShortcuts(navigate func(dir string)) fyne.CanvasObject {
+	title := widget.NewLabel("快捷位置")
+	title.TextStyle = fyne.TextStyle{Bold: true}
+
+	shortcut := func(label string, dir string) *widget.Button {
+		btn := widget.NewButton(label, func() {
+			navigate(dir)
+		})
+		btn.Alignment = widget.ButtonAlignLeading
+		return btn
+	}
+
+	packageEntry := widget.NewSelectEntry(nil)
+	packageEntry.SetPlaceHolder("包名")
+	loadPackagesBtn := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+		go func() {
+			packages, err := adb.ListPackages(deviceID, true)
+			fyne.Do(func() {
+				if err != nil {
+					logging.Error("加载应用列表失败: %v", err)
+					return
+				}
+				packageEntry.SetOptions(packages)
+			})
+		}()
+	})
+
+	appShortcut := func(label string, format string) *widget.Button {
+		btn := widget.NewButton(label, func() {
+			pkg := strings.TrimSpace(packageEntry.Text)
+			if pkg == "" {
+				ui.showMessagePopup("提示", "请先输入包名")
+				return
+			}
+			navigate(fmt.Sprintf(format, pkg))
+		})
+		btn.Alignment = widget.ButtonAlignLeading
+		return btn
+	}
+
+	return container.NewVBox(
+		title,
+		shortcut("SD卡", constants.PathSDCard),
+		shortcut("外部SD卡", constants.PathExternalSD),
+		shortcut("用户应用", constants.PathDataApp),
+		shortcut("系统应用", constants.PathSystemApp),
+		widget.NewSeparator(),
+		widget.NewLabel("应用目录"),
+		container.NewBorder(nil, nil, nil, loadPackagesBtn, packageEntry),
+		appShortcut("应用数据", constants.PathDataData),
+		appShortcut("数据库", constants.PathDatabases),
+		appShortcut("SharedPreferences", constants.PathSharedPrefs),
+	)
+}
+
+// runFileAction 在后台执行文件操作，完成后刷新面板
+func (ui *UI) runFileAction(p *filePane, description string, action func() error) {
+	p.status.SetText("正在" + description + "...")
+	go func() {
+		err := action()
+		fyne.Do(func() {
+			if err != nil {
+				logging.Error("%s失败: %v", description, err)
+				ui.showMessagePopup("错误", description+"失败: "+err.Error())
+			}
+			p.load(p.dir)
+		})
+	}()
+}
+
+// previewFile 预览文本或图片文件，其它二进制文件只显示大小
+func (ui *UI) previewFile(fs fileSystem, entry adb.FileEntry, fullPath string) {
+	limit := textPreviewLimit
+	if isImageFile(entry.Name) {
+		limit = imagePreviewLimit
+	}
+
+	go func() {
+		data, err := fs.ReadHead(fullPath, limit)
+		fyne.Do(func() {
+			if err != nil {
+				ui.showMessagePopup("错误", "读取文件失败: "+err.Error())
+				return
+			}
+
+			var content fyne.CanvasObject
+			switch {
+			case isImageFile(entry.Name):
+				img := canvas.NewImageFromResource(fyne.NewStaticResource(entry.Name, data))
+				img.FillMode = canvas.ImageFillContain
+				img.SetMinSize(fyne.NewSize(480, 360))
+				content = img
+			case utf8.Valid(data) && !bytes.Contains(data, []byte{0}):
+				text := widget.NewMultiLineEntry()
+				text.TextStyle = fyne.TextStyle{Monospace: true}
+				body := string(data)
+				if int64(len(data)) < entry.Size {
+					body += fmt.Sprintf("\n\n... 仅显示前 %s，文件共 %s", formatSize(int64(len(data))), formatSize(entry.Size))
+				}
+				text.SetText(body)
+				text.Disable()
+				scroll := container.NewScroll(text)
+				scroll.SetMinSize(fyne.NewSize(640, 420))
+				content = scroll
+			default:
+				content = widget.NewLabel(fmt.Sprintf("二进制文件，无法预览（%s）", formatSize(entry.Size)))
+			}
+
+			previewDialog := dialog.NewCustom("预览: "+entry.Name, "关闭", content, ui.window)
+			previewDialog.Show()
+		})
+	}()
+}
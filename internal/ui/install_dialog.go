@@ -0,0 +1,273 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"yikong/internal/adb"
+	"yikong/internal/apk"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 支持拖放安装的文件扩展名
+var installableExtensions = []string{".apk", ".apks", ".xapk"}
+
+func isInstallableFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, e := range installableExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// handleDroppedURIs 处理拖放到窗口上的文件，安装到当前选中的设备
+func (ui *UI) handleDroppedURIs(pos fyne.Position, uris []fyne.URI) {
+	if ui.fileDropTarget != nil && ui.mainContainer.Objects[1].Visible() && ui.fileDropTarget(pos, uris) {
+		return
+	}
+
+	var files []string
+	for _, uri := range uris {
+		if uri.Scheme() == "file" && isInstallableFile(uri.Path()) {
+			files = append(files, uri.Path())
+		}
+	}
+	if len(files) == 0 {
+		ui.showMessagePopup("提示", "仅支持拖入 .apk / .apks / .xapk 文件")
+		return
+	}
+
+	devices := ui.installDevices
+	if len(devices) == 0 && ui.selectedDevice != "" {
+		devices = []string{ui.selectedDevice}
+	}
+	if len(devices) == 0 {
+		ui.showMessagePopup("错误", "请先选择一个设备")
+		return
+	}
+
+	logging.Info("拖放安装: 文件=%v, 设备=%v", files, devices)
+	ui.showInstallDialog(devices, files, adb.InstallOptions{Replace: true}, false)
+}
+
+// installRow 安装对话框中一个设备对应的进度行
+type installRow struct {
+	deviceID    string
+	progressBar *widget.ProgressBar
+	statusLabel *widget.Label
+	remedyBox   *fyne.Container
+}
+
+// showInstallDialog 在一个或多个设备上安装文件，显示每个设备的传输进度、
+// 流式输出，并将 INSTALL_FAILED_* 翻译为说明及一键补救按钮
+func (ui *UI) showInstallDialog(deviceIDs []string, files []string, opts adb.InstallOptions, useSession bool) {
+	outputDisplay := widget.NewMultiLineEntry()
+	outputDisplay.Wrapping = fyne.TextWrapWord
+	outputDisplay.SetPlaceHolder("安装输出将显示在这里...")
+	outputDisplay.Disable()
+
+	outputScroll := container.NewScroll(outputDisplay)
+	outputScroll.SetMinSize(fyne.NewSize(560, 200))
+
+	appendOutput := func(text string) {
+		fyne.Do(func() {
+			if outputDisplay.Text == "" {
+				outputDisplay.SetText(text)
+			} else {
+				outputDisplay.SetText(outputDisplay.Text + "\n" + text)
+			}
+			outputScroll.ScrollToBottom()
+		})
+	}
+
+	statusLabel := widget.NewLabel("正在分析安装包...")
+	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	rowsContainer := container.NewVBox()
+	rows := make([]*installRow, 0, len(deviceIDs))
+	for _, deviceID := range deviceIDs {
+		row := &installRow{
+			deviceID:    deviceID,
+			progressBar: widget.NewProgressBar(),
+			statusLabel: widget.NewLabel("等待安装..."),
+			remedyBox:   container.NewHBox(),
+		}
+		row.statusLabel.Wrapping = fyne.TextWrapWord
+
+		nameLabel := widget.NewLabel(deviceID)
+		nameLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+		rowsContainer.Add(container.NewVBox(nameLabel, row.progressBar, row.statusLabel, row.remedyBox))
+		rows = append(rows, row)
+	}
+
+	rowsScroll := container.NewVScroll(rowsContainer)
+	rowsScroll.SetMinSize(fyne.NewSize(560, 180))
+
+	var popup *widget.PopUp
+	closeBtn := widget.NewButton("关闭", func() {
+		if popup != nil {
+			popup.Hide()
+		}
+	})
+	closeBtn.Disable()
+
+	content := container.NewVBox(
+		statusLabel,
+		widget.NewSeparator(),
+		rowsScroll,
+		widget.NewSeparator(),
+		outputScroll,
+		closeBtn,
+	)
+	card := widget.NewCard("安装: "+strings.Join(baseNames(files), ", "), "", content)
+	popup = widget.NewModalPopUp(card, ui.window.Canvas())
+	popup.Show()
+
+	go func() {
+		// 解析APK需要读取文件，放在后台进行
+		groups := apk.GroupInstallFiles(files)
+		fyne.Do(func() {
+			statusLabel.SetText(fmt.Sprintf("正在安装 %d 个应用到 %d 台设备...", len(groups), len(rows)))
+		})
+
+		var wg sync.WaitGroup
+		for _, row := range rows {
+			wg.Add(1)
+			go func(row *installRow) {
+				defer wg.Done()
+				for idx, group := range groups {
+					ui.runInstallGroup(row, group, idx, len(groups), opts, useSession, appendOutput)
+				}
+			}(row)
+		}
+		wg.Wait()
+
+		fyne.Do(func() {
+			statusLabel.SetText("安装任务已完成")
+			closeBtn.Enable()
+		})
+	}()
+}
+
+// runInstallGroup 在一台设备上安装一组文件（同一应用），失败时显示原因和补救按钮
+func (ui *UI) runInstallGroup(row *installRow, group []string, index int, total int, opts adb.InstallOptions, useSession bool, appendOutput func(string)) {
+	groupName := strings.Join(baseNames(group), ", ")
+	prefix := "[" + row.deviceID + "] "
+
+	fyne.Do(func() {
+		row.statusLabel.SetText(fmt.Sprintf("(%d/%d) 正在安装 %s", index+1, total, groupName))
+	})
+
+	// 限制进度刷新频率，避免大量 fyne.Do 调用
+	var lastUpdate time.Time
+	progress := func(written, size int64) {
+		if size <= 0 || (time.Since(lastUpdate) < 100*time.Millisecond && written < size) {
+			return
+		}
+		lastUpdate = time.Now()
+		value := (float64(index) + float64(written)/float64(size)) / float64(total)
+		fyne.Do(func() {
+			row.progressBar.SetValue(value)
+		})
+	}
+
+	result, err := adb.InstallFiles(row.deviceID, group, opts, useSession, progress, func(line string) {
+		appendOutput(prefix + line)
+	})
+
+	if err == nil {
+		appendOutput(prefix + groupName + " 安装成功")
+		fyne.Do(func() {
+			row.progressBar.SetValue(float64(index+1) / float64(total))
+			row.statusLabel.SetText(fmt.Sprintf("(%d/%d) %s 安装成功", index+1, total, groupName))
+		})
+		return
+	}
+
+	text := err.Error()
+	if result != nil {
+		text = result.Output + "\n" + result.ErrorOutput + "\n" + text
+	}
+	failure := adb.ParseInstallFailure(text)
+	logging.Error("安装失败: deviceID=%s, 文件=%s, 错误: %v", row.deviceID, groupName, err)
+
+	fyne.Do(func() {
+		if failure == nil {
+			row.statusLabel.SetText(fmt.Sprintf("%s 安装失败: %v", groupName, err))
+			return
+		}
+
+		message := fmt.Sprintf("%s 安装失败: %s\n%s", groupName, failure.Code, failure.Explanation)
+		if failure.Message != "" {
+			message += "\n系统信息: " + failure.Message
+		}
+		row.statusLabel.SetText(message)
+
+		if failure.Remedy == adb.RemedyNone {
+			return
+		}
+		var remedyBtn *widget.Button
+		remedyBtn = widget.NewButton(groupName+": "+adb.RemedyLabel(failure.Remedy), func() {
+			row.remedyBox.Remove(remedyBtn)
+			go ui.applyInstallRemedy(row, group, failure.Remedy, opts, useSession, appendOutput)
+		})
+		remedyBtn.Importance = widget.WarningImportance
+		row.remedyBox.Add(remedyBtn)
+	})
+}
+
+// applyInstallRemedy 执行补救操作后重新安装
+func (ui *UI) applyInstallRemedy(row *installRow, group []string, remedy string, opts adb.InstallOptions, useSession bool, appendOutput func(string)) {
+	prefix := "[" + row.deviceID + "] "
+
+	switch remedy {
+	case adb.RemedyUninstallReinstall:
+		packageName := packageNameOf(group[0])
+		if packageName == "" {
+			appendOutput(prefix + "无法从安装包中读取包名，无法卸载")
+			return
+		}
+		appendOutput(prefix + "正在卸载 " + packageName)
+		if _, err := adb.Uninstall(row.deviceID, packageName, false); err != nil {
+			appendOutput(prefix + "卸载失败: " + err.Error())
+			return
+		}
+	case adb.RemedyTrimCaches:
+		appendOutput(prefix + "正在清理应用缓存...")
+		if err := adb.TrimCaches(row.deviceID); err != nil {
+			appendOutput(prefix + "清理缓存失败: " + err.Error())
+		}
+	}
+
+	fyne.Do(func() {
+		row.progressBar.SetValue(0)
+	})
+	ui.runInstallGroup(row, group, 0, 1, opts.ApplyRemedy(remedy), useSession, appendOutput)
+}
+
+// packageNameOf 读取安装包的包名，失败时返回空字符串
+func packageNameOf(file string) string {
+	bundle, err := apk.OpenBundle(file)
+	if err != nil {
+		return ""
+	}
+	defer bundle.Close()
+	return bundle.PackageName
+}
+
+func baseNames(files []string) []string {
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = filepath.Base(f)
+	}
+	return names
+}
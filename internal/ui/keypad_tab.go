@@ -0,0 +1,275 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"yikong/internal/adb"
+	"yikong/internal/constants"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// keypadKey 遥控器面板上的一个按键
+type keypadKey struct {
+	label   string
+	keyCode int
+}
+
+// 遥控器面板按功能分组的按键
+var keypadGroups = []struct {
+	title string
+	keys  []keypadKey
+}{
+	{"导航", []keypadKey{
+		{"返回", constants.KeyCodeBack}, {"主页", constants.KeyCodeHome},
+		{"菜单", constants.KeyCodeMenu}, {"最近任务", constants.KeyCodeAppSwitch},
+		{"通知栏", constants.KeyCodeNotification}, {"搜索", constants.KeyCodeSearch},
+	}},
+	{"音量与电源", []keypadKey{
+		{"音量+", constants.KeyCodeVolumeUp}, {"音量-", constants.KeyCodeVolumeDown},
+		{"静音", constants.KeyCodeVolumeMute}, {"电源", constants.KeyCodePower},
+		{"休眠", constants.KeyCodeSleep}, {"唤醒", constants.KeyCodeWakeup},
+	}},
+	{"媒体", []keypadKey{
+		{"上一首", constants.KeyCodeMediaPrev}, {"播放/暂停", constants.KeyCodeMediaPlayPause},
+		{"停止", constants.KeyCodeMediaStop}, {"下一首", constants.KeyCodeMediaNext},
+		{"快退", constants.KeyCodeMediaRewind}, {"快进", constants.KeyCodeMediaFastFwd},
+	}},
+	{"其它", []keypadKey{
+		{"亮度+", constants.KeyCodeBrightnessUp}, {"亮度-", constants.KeyCodeBrightnessDown},
+		{"相机", constants.KeyCodeCamera}, {"设置", constants.KeyCodeSettings},
+		{"助手", constants.KeyCodeAssist}, {"Esc", constants.KeyCodeEscape},
+	}},
+}
+
+// createKeypadTab 虚拟按键：方向键、导航、音量、电源、媒体等常用按键，
+// 以及可搜索的全部 KEYCODE_* 列表和按键序列；勾选“长按”后按键以 --longpress 发送
+func (ui *UI) createKeypadTab() fyne.CanvasObject {
+	var sequenceCancel context.CancelFunc
+
+	statusLabel := widget.NewLabel("")
+	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	longPressCheck := widget.NewCheck("长按", nil)
+
+	sendKey := func(keyCode int) {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+		longPress := longPressCheck.Checked
+		name := adb.KeyCodeName(keyCode)
+		go func() {
+			var err error
+			if longPress {
+				err = adb.LongPressKey(deviceID, keyCode)
+			} else {
+				err = adb.KeyEvent(deviceID, keyCode)
+			}
+			fyne.Do(func() {
+				if err != nil {
+					logging.Error("发送按键失败: deviceID=%s, key=%s, 错误: %v", deviceID, name, err)
+					statusLabel.SetText(fmt.Sprintf("状态: 发送 %s 失败 - %v", name, err))
+					return
+				}
+				if longPress {
+					statusLabel.SetText("状态: 已长按 " + name)
+				} else {
+					statusLabel.SetText("状态: 已发送 " + name)
+				}
+			})
+		}()
+	}
+
+	keyButton := func(label string, keyCode int) *widget.Button {
+		return widget.NewButton(label, func() { sendKey(keyCode) })
+	}
+
+	// 方向键
+	dpad := container.NewGridWithColumns(3,
+		layoutSpacer(), widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() { sendKey(constants.KeyCodeDpadUp) }), layoutSpacer(),
+		widget.NewButtonWithIcon("", theme.NavigateBackIcon(), func() { sendKey(constants.KeyCodeDpadLeft) }),
+		keyButton("OK", constants.KeyCodeDpadCenter),
+		widget.NewButtonWithIcon("", theme.NavigateNextIcon(), func() { sendKey(constants.KeyCodeDpadRight) }),
+		layoutSpacer(), widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() { sendKey(constants.KeyCodeDpadDown) }), layoutSpacer(),
+	)
+
+	panel := container.NewVBox(widget.NewCard("方向键", "", dpad))
+	for _, group := range keypadGroups {
+		grid := container.NewGridWithColumns(3)
+		for _, k := range group.keys {
+			grid.Add(keyButton(k.label, k.keyCode))
+		}
+		panel.Add(widget.NewCard(group.title, "", grid))
+	}
+
+	// 全部按键列表，支持按名称或数值搜索
+	allCodes := make([]int, 0, len(constants.KeyCodeMap))
+	for code := range constants.KeyCodeMap {
+		allCodes = append(allCodes, code)
+	}
+	slices.Sort(allCodes)
+	filtered := allCodes
+	selectedCode := -1
+
+	keyList := widget.NewList(
+		func() int { return len(filtered) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			return label
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			code := filtered[id]
+			obj.(*widget.Label).SetText(fmt.Sprintf("%3d  %s", code, constants.KeyCodeMap[code]))
+		},
+	)
+	keyList.OnSelected = func(id widget.ListItemID) {
+		if id < len(filtered) {
+			selectedCode = filtered[id]
+		}
+	}
+
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder("搜索按键名称或数值...")
+	searchEntry.OnChanged = func(text string) {
+		query := strings.ToUpper(strings.TrimSpace(text))
+		filtered = nil
+		for _, code := range allCodes {
+			if query == "" || strings.Contains(constants.KeyCodeMap[code], query) || strconv.Itoa(code) == query {
+				filtered = append(filtered, code)
+			}
+		}
+		selectedCode = -1
+		keyList.UnselectAll()
+		keyList.Refresh()
+	}
+
+	sequenceEntry := widget.NewEntry()
+	sequenceEntry.SetPlaceHolder("例如: HOME, DPAD_DOWN, DPAD_DOWN, ENTER, long:POWER")
+	intervalEntry := widget.NewEntry()
+	intervalEntry.SetText("300")
+
+	sendSelectedBtn := widget.NewButton("发送选中按键", func() {
+		if selectedCode < 0 {
+			ui.showMessagePopup("提示", "请先在列表中选择一个按键")
+			return
+		}
+		sendKey(selectedCode)
+	})
+	appendSelectedBtn := widget.NewButton("加入序列", func() {
+		if selectedCode < 0 {
+			ui.showMessagePopup("提示", "请先在列表中选择一个按键")
+			return
+		}
+		name := strings.TrimPrefix(constants.KeyCodeMap[selectedCode], "KEYCODE_")
+		if longPressCheck.Checked {
+			name = "long:" + name
+		}
+		text := strings.TrimSpace(sequenceEntry.Text)
+		if text != "" {
+			text += ", "
+		}
+		sequenceEntry.SetText(text + name)
+	})
+
+	var runSequenceBtn, stopSequenceBtn *widget.Button
+	runSequenceBtn = widget.NewButtonWithIcon("执行序列", theme.MediaPlayIcon(), func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		keys, err := adb.ParseKeySequence(sequenceEntry.Text)
+		if err != nil {
+			ui.showMessagePopup("错误", err.Error())
+			return
+		}
+		intervalMs, err := strconv.Atoi(strings.TrimSpace(intervalEntry.Text))
+		if err != nil || intervalMs < 0 {
+			ui.showMessagePopup("错误", "按键间隔应为非负整数（毫秒）")
+			return
+		}
+		deviceID := ui.selectedDevice
+		ctx, cancel := context.WithCancel(context.Background())
+		sequenceCancel = cancel
+		runSequenceBtn.Disable()
+		stopSequenceBtn.Enable()
+
+		go func() {
+			err := adb.SendKeySequence(ctx, deviceID, keys, time.Duration(intervalMs)*time.Millisecond, func(index int) {
+				fyne.Do(func() {
+					statusLabel.SetText(fmt.Sprintf("状态: 序列 %d/%d  %s", index+1, len(keys), adb.KeyCodeName(keys[index].KeyCode)))
+				})
+			})
+			stopped := ctx.Err() != nil
+			cancel()
+			fyne.Do(func() {
+				sequenceCancel = nil
+				runSequenceBtn.Enable()
+				stopSequenceBtn.Disable()
+				switch {
+				case stopped:
+					statusLabel.SetText("状态: 按键序列已停止")
+				case err != nil:
+					logging.Error("执行按键序列失败: deviceID=%s, 错误: %v", deviceID, err)
+					statusLabel.SetText("状态: 按键序列失败 - " + err.Error())
+				default:
+					statusLabel.SetText(fmt.Sprintf("状态: 按键序列完成，共 %d 个按键", len(keys)))
+				}
+			})
+		}()
+	})
+	runSequenceBtn.Importance = widget.HighImportance
+	stopSequenceBtn = widget.NewButtonWithIcon("停止", theme.MediaStopIcon(), func() {
+		if sequenceCancel != nil {
+			sequenceCancel()
+		}
+	})
+	stopSequenceBtn.Disable()
+
+	sequenceForm := widget.NewForm(
+		widget.NewFormItem("按键序列", sequenceEntry),
+		widget.NewFormItem("间隔 (毫秒)", intervalEntry),
+	)
+
+	right := container.NewBorder(
+		container.NewVBox(
+			widget.NewLabelWithStyle("全部按键", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			searchEntry,
+		),
+		container.NewVBox(
+			container.NewHBox(sendSelectedBtn, appendSelectedBtn),
+			widget.NewSeparator(),
+			sequenceForm,
+			container.NewHBox(runSequenceBtn, stopSequenceBtn),
+		),
+		nil, nil,
+		keyList,
+	)
+
+	split := container.NewHSplit(container.NewVScroll(panel), right)
+	split.Offset = 0.45
+
+	return container.NewPadded(container.NewBorder(
+		container.NewVBox(container.NewHBox(longPressCheck), statusLabel, widget.NewSeparator()),
+		nil, nil, nil,
+		split,
+	))
+}
+
+// layoutSpacer 网格中的占位
+func layoutSpacer() fyne.CanvasObject {
+	return canvas.NewRectangle(color.Transparent)
+}
@@ -0,0 +1,355 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"yikong/internal/adb"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 回放速度选项
+var (
+	macroSpeedOptions = []string{"0.5x", "1x", "2x", "4x"}
+	macroSpeedValues  = map[string]float64{"0.5x": 0.5, "1x": 1, "2x": 2, "4x": 4}
+)
+
+// createMacroTab 输入宏：从投屏页面的远程操作或设备上的真实触摸（getevent）录制，
+// 录制结果以文本形式显示、可直接编辑，并可在多台设备上按速度倍数与循环次数回放
+func (ui *UI) createMacroTab() fyne.CanvasObject {
+	var (
+		stopRecording func()             // 正在录制时非空
+		playCancel    context.CancelFunc // 正在回放时非空
+	)
+
+	statusLabel := widget.NewLabel("")
+	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	editor := widget.NewMultiLineEntry()
+	editor.TextStyle = fyne.TextStyle{Monospace: true}
+	editor.SetPlaceHolder("录制的宏将显示在这里，也可以手工编写：\nscreen 1080x2400\ntap 540 1200\nwait 500\nswipe 540 1800 540 600 300\ntext hello\nkey 4")
+	editorScroll := container.NewScroll(editor)
+	editorScroll.SetMinSize(fyne.NewSize(420, 300))
+
+	outputDisplay := widget.NewMultiLineEntry()
+	outputDisplay.Wrapping = fyne.TextWrapWord
+	outputDisplay.SetPlaceHolder("回放输出将显示在这里...")
+	outputDisplay.Disable()
+	outputScroll := container.NewScroll(outputDisplay)
+	outputScroll.SetMinSize(fyne.NewSize(300, 150))
+
+	appendOutput := func(line string) {
+		fyne.Do(func() {
+			outputDisplay.SetText(outputDisplay.Text + line + "\n")
+			outputScroll.ScrollToBottom()
+		})
+	}
+
+	deviceGroup, refreshDevices := ui.newDeviceCheckGroup()
+	refreshDevicesBtn := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), refreshDevices)
+
+	speedSelect := widget.NewSelect(macroSpeedOptions, nil)
+	speedSelect.SetSelected("1x")
+	loopsEntry := widget.NewEntry()
+	loopsEntry.SetText("1")
+	loopsEntry.SetPlaceHolder("0 表示一直循环")
+
+	var recordMirrorBtn, recordDeviceBtn, stopRecordBtn, playBtn, stopPlayBtn *widget.Button
+
+	// showRecorded 将录制器中的内容刷新到编辑框
+	showRecorded := func(recorder *adb.MacroRecorder) {
+		m := recorder.Macro()
+		fyne.Do(func() {
+			editor.SetText(adb.FormatMacro(m))
+			statusLabel.SetText(fmt.Sprintf("状态: 正在录制，已记录 %d 步", len(m.Steps)))
+		})
+	}
+
+	setRecording := func(recording bool) {
+		if recording {
+			recordMirrorBtn.Disable()
+			recordDeviceBtn.Disable()
+			stopRecordBtn.Enable()
+			playBtn.Disable()
+		} else {
+			recordMirrorBtn.Enable()
+			recordDeviceBtn.Enable()
+			stopRecordBtn.Disable()
+			playBtn.Enable()
+		}
+	}
+
+	recordMirrorBtn = widget.NewButtonWithIcon("从投屏录制", theme.MediaRecordIcon(), func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+		setRecording(true)
+		statusLabel.SetText("状态: 正在获取屏幕信息...")
+
+		go func() {
+			display, err := adb.GetDisplayInfo(deviceID)
+			fyne.Do(func() {
+				if err != nil {
+					setRecording(false)
+					statusLabel.SetText("状态: 获取屏幕信息失败 - " + err.Error())
+					return
+				}
+				width, height := display.CurrentSize()
+				var recorder *adb.MacroRecorder
+				recorder = adb.NewMacroRecorder(width, height, func(adb.MacroStep) {
+					showRecorded(recorder)
+				})
+				ui.macroRecorder = recorder
+				stopRecording = func() {
+					if ui.macroRecorder == recorder {
+						ui.macroRecorder = nil
+					}
+					editor.SetText(adb.FormatMacro(recorder.Macro()))
+				}
+				editor.SetText(adb.FormatMacro(recorder.Macro()))
+				logging.Info("开始从投屏录制宏: deviceID=%s", deviceID)
+				statusLabel.SetText("状态: 正在录制，请在“投屏”页面开启远程控制后操作")
+			})
+		}()
+	})
+
+	recordDeviceBtn = widget.NewButtonWithIcon("从设备录制", theme.MediaRecordIcon(), func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+		setRecording(true)
+		statusLabel.SetText("状态: 正在读取输入设备...")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			var recorder *adb.MacroRecorder
+			var mu sync.Mutex
+			r, done, err := adb.RecordDeviceInput(ctx, deviceID, func(adb.MacroStep) {
+				mu.Lock()
+				current := recorder
+				mu.Unlock()
+				if current != nil {
+					showRecorded(current)
+				}
+			})
+			if err != nil {
+				cancel()
+				logging.Error("录制设备输入失败: deviceID=%s, 错误: %v", deviceID, err)
+				fyne.Do(func() {
+					setRecording(false)
+					statusLabel.SetText("状态: 录制失败 - " + err.Error())
+				})
+				return
+			}
+			mu.Lock()
+			recorder = r
+			mu.Unlock()
+
+			fyne.Do(func() {
+				editor.SetText(adb.FormatMacro(r.Macro()))
+				statusLabel.SetText("状态: 正在录制，请直接在设备上操作")
+				stopRecording = func() {
+					cancel()
+					editor.SetText(adb.FormatMacro(r.Macro()))
+				}
+			})
+
+			// getevent 意外退出（如设备断开）时结束录制
+			if err := <-done; err != nil {
+				logging.Error("getevent 已退出: deviceID=%s, 错误: %v", deviceID, err)
+				fyne.Do(func() {
+					if stopRecording != nil {
+						stopRecording()
+						stopRecording = nil
+						setRecording(false)
+					}
+					statusLabel.SetText("状态: 录制中断 - " + err.Error())
+				})
+			}
+		}()
+	})
+
+	stopRecordBtn = widget.NewButtonWithIcon("停止录制", theme.MediaStopIcon(), func() {
+		if stopRecording == nil {
+			return
+		}
+		stopRecording()
+		stopRecording = nil
+		setRecording(false)
+		if m, err := adb.ParseMacro(editor.Text); err == nil {
+			statusLabel.SetText(fmt.Sprintf("状态: 录制完成，共 %d 步，时长约 %.1f 秒", len(m.Steps), m.Duration().Seconds()))
+		} else {
+			statusLabel.SetText("状态: 录制完成，没有记录到操作")
+		}
+	})
+	stopRecordBtn.Importance = widget.DangerImportance
+	stopRecordBtn.Disable()
+
+	playBtn = widget.NewButtonWithIcon("回放", theme.MediaPlayIcon(), func() {
+		m, err := adb.ParseMacro(editor.Text)
+		if err != nil {
+			ui.showMessagePopup("错误", "宏内容有误: "+err.Error())
+			return
+		}
+		devices := append([]string(nil), deviceGroup.Selected...)
+		if len(devices) == 0 {
+			ui.showMessagePopup("错误", "请至少选择一个设备")
+			return
+		}
+		loops, err := strconv.Atoi(strings.TrimSpace(loopsEntry.Text))
+		if err != nil || loops < 0 {
+			ui.showMessagePopup("错误", "循环次数应为非负整数")
+			return
+		}
+		speed := macroSpeedValues[speedSelect.Selected]
+
+		ctx, cancel := context.WithCancel(context.Background())
+		playCancel = cancel
+		playBtn.Disable()
+		stopPlayBtn.Enable()
+		recordMirrorBtn.Disable()
+		recordDeviceBtn.Disable()
+		outputDisplay.SetText("")
+		statusLabel.SetText(fmt.Sprintf("状态: 正在 %d 台设备上回放 %d 步...", len(devices), len(m.Steps)))
+
+		go func() {
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			failed := 0
+			for _, deviceID := range devices {
+				wg.Add(1)
+				go func(deviceID string) {
+					defer wg.Done()
+					lastLoop := 0
+					err := adb.PlayMacro(ctx, deviceID, m, speed, loops, func(loop, index int) {
+						if loop != lastLoop {
+							lastLoop = loop
+							appendOutput(fmt.Sprintf("%s: 第 %d 遍", deviceID, loop))
+						}
+					})
+					switch {
+					case err == nil:
+						appendOutput(deviceID + ": 回放完成")
+					case ctx.Err() != nil:
+						appendOutput(deviceID + ": 已停止")
+					default:
+						logging.Error("回放宏失败: deviceID=%s, 错误: %v", deviceID, err)
+						appendOutput(fmt.Sprintf("%s: 失败 - %v", deviceID, err))
+						mu.Lock()
+						failed++
+						mu.Unlock()
+					}
+				}(deviceID)
+			}
+			wg.Wait()
+			stopped := ctx.Err() != nil
+			cancel()
+
+			fyne.Do(func() {
+				playCancel = nil
+				playBtn.Enable()
+				stopPlayBtn.Disable()
+				recordMirrorBtn.Enable()
+				recordDeviceBtn.Enable()
+				switch {
+				case stopped:
+					statusLabel.SetText("状态: 回放已停止")
+				case failed > 0:
+					statusLabel.SetText(fmt.Sprintf("状态: 回放结束，%d 台设备失败", failed))
+				default:
+					statusLabel.SetText("状态: 回放完成")
+				}
+			})
+		}()
+	})
+	playBtn.Importance = widget.HighImportance
+
+	stopPlayBtn = widget.NewButtonWithIcon("停止回放", theme.MediaStopIcon(), func() {
+		if playCancel != nil {
+			playCancel()
+		}
+	})
+	stopPlayBtn.Importance = widget.DangerImportance
+	stopPlayBtn.Disable()
+
+	openBtn := widget.NewButtonWithIcon("打开", theme.FolderOpenIcon(), func() {
+		fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			path := reader.URI().Path()
+			reader.Close()
+			m, err := adb.LoadMacro(path)
+			if err != nil {
+				ui.showMessagePopup("错误", "读取宏失败: "+err.Error())
+				return
+			}
+			editor.SetText(adb.FormatMacro(m))
+			statusLabel.SetText(fmt.Sprintf("状态: 已打开 %s（%d 步）", path, len(m.Steps)))
+		}, ui.window)
+		fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{adb.MacroExtension}))
+		fileDialog.Show()
+	})
+
+	saveBtn := widget.NewButtonWithIcon("另存为", theme.DocumentSaveIcon(), func() {
+		m, err := adb.ParseMacro(editor.Text)
+		if err != nil {
+			ui.showMessagePopup("错误", "宏内容有误: "+err.Error())
+			return
+		}
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			dest := writer.URI().Path()
+			writer.Close()
+			if !strings.HasSuffix(dest, adb.MacroExtension) {
+				dest += adb.MacroExtension
+			}
+			if err := adb.SaveMacro(dest, m); err != nil {
+				ui.showMessagePopup("错误", "保存宏失败: "+err.Error())
+				return
+			}
+			statusLabel.SetText("状态: 宏已保存到 " + dest)
+		}, ui.window)
+		saveDialog.SetFileName("macro" + adb.MacroExtension)
+		saveDialog.Show()
+	})
+
+	hintLabel := widget.NewLabel("提示: 从投屏录制会记录“投屏”页面中转发到设备的操作；从设备录制通过 getevent 识别触摸屏上的点击、滑动和实体按键（只跟踪第一根手指）。回放到分辨率不同的设备时按 screen 行记录的尺寸换算坐标")
+	hintLabel.Wrapping = fyne.TextWrapWord
+
+	form := widget.NewForm(
+		widget.NewFormItem("回放设备", container.NewBorder(nil, nil, nil, refreshDevicesBtn, deviceGroup)),
+		widget.NewFormItem("速度", speedSelect),
+		widget.NewFormItem("循环次数", loopsEntry),
+	)
+
+	top := container.NewVBox(
+		container.NewHBox(recordMirrorBtn, recordDeviceBtn, stopRecordBtn, widget.NewSeparator(), openBtn, saveBtn),
+		form,
+		container.NewHBox(playBtn, stopPlayBtn),
+		hintLabel,
+		statusLabel,
+		widget.NewSeparator(),
+	)
+
+	split := container.NewHSplit(editorScroll, outputScroll)
+	split.Offset = 0.6
+
+	return container.NewPadded(container.NewBorder(top, nil, nil, nil, split))
+}
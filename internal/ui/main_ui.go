@@ -31,6 +31,21 @@ type UI struct {
 	logDisplay         *widget.Entry
 	logStatusLabel     *widget.Label
 	logScrollContainer *container.Scroll
+
+	// 安装目标设备（应用管理页面中勾选的设备，拖放安装时使用）
+	installDevices []string
+
+	// 文件管理页面的拖放处理，返回 true 表示已处理，不再按拖放安装处理
+	fileDropTarget func(pos fyne.Position, uris []fyne.URI) bool
+
+	// 文件传输队列，首次使用时创建并加载上次未完成的任务
+	transfers *adb.TransferManager
+
+	// 功能页面上方显示功能详情的卡片，选中设备后默认显示设备概览
+	functionCard *widget.Card
+
+	// 宏页面从投屏录制时的录制器，投屏页面的远程输入会记录到其中
+	macroRecorder *adb.MacroRecorder
 }
 
 func MainUI(window fyne.Window) fyne.CanvasObject {
@@ -47,6 +62,9 @@ func (ui *UI) createUI() fyne.CanvasObject {
 
 	ui.mainContainer = container.NewStack(devicePage, functionPage)
 
+	// 拖放APK到窗口即可安装
+	ui.window.SetOnDropped(ui.handleDroppedURIs)
+
 	return ui.mainContainer
 }
 
@@ -159,12 +177,19 @@ func (ui *UI) createFunctionPage() fyne.CanvasObject {
 		"",
 		widget.NewLabel("等待选择功能..."),
 	)
+	ui.functionCard = deviceInfo
+
+	overviewBtn := widget.NewButtonWithIcon("设备概览", theme.InfoIcon(), func() {
+		ui.showDeviceOverview()
+	})
 
 	iconMap := map[string]func() fyne.Resource{
 		"SettingsIcon":      theme.SettingsIcon,
 		"DocumentSaveIcon":  theme.DocumentSaveIcon,
 		"DocumentPrintIcon": theme.DocumentPrintIcon,
 		"MailSendIcon":      theme.MailSendIcon,
+		"MediaPhotoIcon":    theme.MediaPhotoIcon,
+		"ComputerIcon":      theme.ComputerIcon,
 	}
 
 	buttonGrid := container.NewGridWithColumns(2,
@@ -172,7 +197,7 @@ func (ui *UI) createFunctionPage() fyne.CanvasObject {
 		container.NewVBox(),
 	)
 
-	featureOrder := []string{"device_management", "app_management", "log_viewing", "file_transfer", "settings"}
+	featureOrder := []string{"device_management", "app_management", "log_viewing", "file_transfer", "screen_control", "system_tools", "settings"}
 
 	for idx, featureID := range featureOrder {
 		config, exists := constants.FeatureMap[featureID]
@@ -203,13 +228,14 @@ func (ui *UI) createFunctionPage() fyne.CanvasObject {
 		callback := func(featureConfig constants.FeatureConfig) func() {
 			return func() {
 				// 创建功能详情界面
+				ui.fileDropTarget = nil
 				content := ui.createFeatureDetailContent(featureConfig)
 				deviceInfo.SetContent(content)
 			}
 		}(config)
 
 		btnContainer := ui.createFeatureButton(config.Name, icon, callback)
-		if idx < 3 {
+		if idx < (len(featureOrder)+1)/2 {
 			buttonGrid.Objects[0].(*fyne.Container).Add(btnContainer)
 		} else {
 			buttonGrid.Objects[1].(*fyne.Container).Add(btnContainer)
@@ -218,7 +244,7 @@ func (ui *UI) createFunctionPage() fyne.CanvasObject {
 
 	mainContent := container.NewBorder(
 		container.NewVBox(
-			backBtnContainer,
+			container.NewBorder(nil, nil, nil, overviewBtn, backBtnContainer),
 			widget.NewSeparator(),
 		),
 		nil, nil, nil,
@@ -291,9 +317,19 @@ func (ui *UI) refreshDevices() {
 
 func (ui *UI) selectDevice(deviceID string) {
 	ui.selectedDevice = deviceID
+	ui.showDeviceOverview()
 	ui.showFunctionPage()
 }
 
+// showDeviceOverview 在功能页面的卡片中显示当前设备的概览
+func (ui *UI) showDeviceOverview() {
+	if ui.functionCard == nil {
+		return
+	}
+	ui.fileDropTarget = nil
+	ui.functionCard.SetContent(ui.createDeviceOverview())
+}
+
 func (ui *UI) showDevicePage() {
 	currentPage := ui.mainContainer.Objects[1]
 	newPage := ui.mainContainer.Objects[0]
@@ -541,10 +577,6 @@ func (ui *UI) createDeviceManagementPage() fyne.CanvasObject {
 	return container.NewPadded(content)
 }
 
-func (ui *UI) createAppManagementPage() fyne.CanvasObject {
-	return widget.NewLabel("应用管理功能正在开发中...")
-}
-
 func (ui *UI) createLogViewingPage() fyne.CanvasObject {
 	// 创建标题
 	title := widget.NewLabel("日志查看")
@@ -1016,10 +1048,6 @@ func (ui *UI) createLogViewingPage() fyne.CanvasObject {
 	return container.NewPadded(content)
 }
 
-func (ui *UI) createFileTransferPage() fyne.CanvasObject {
-	return widget.NewLabel("文件传输功能正在开发中...")
-}
-
 func (ui *UI) createSettingsPage() fyne.CanvasObject {
 	return widget.NewLabel("设置功能正在开发中...")
 }
@@ -1035,6 +1063,10 @@ func (ui *UI) createFeatureDetailContent(config constants.FeatureConfig) fyne.Ca
 		return ui.createLogViewingPage()
 	case "file_transfer":
 		return ui.createFileTransferPage()
+	case "screen_control":
+		return ui.createScreenPage()
+	case "system_tools":
+		return ui.createSystemPage()
 	case "settings":
 		return ui.createSettingsPage()
 	}
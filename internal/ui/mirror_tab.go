@@ -0,0 +1,523 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"time"
+	"yikong/internal/adb"
+	"yikong/internal/constants"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 投屏缩放与帧率选项
+var (
+	mirrorScaleOptions = []string{"100%", "75%", "50%", "25%"}
+	mirrorScaleValues  = map[string]float64{"100%": 1, "75%": 0.75, "50%": 0.5, "25%": 0.25}
+	mirrorFPSOptions   = []string{"不限", "30", "15", "5"}
+	mirrorFPSValues    = map[string]int{"不限": 0, "30": 30, "15": 15, "5": 5}
+)
+
+// mirrorView 显示设备画面的画布，记录当前帧在设备上的原始尺寸。
+// 开启远程控制时把点击、拖动、滚轮与键盘输入换算为画面坐标后交给回调
+type mirrorView struct {
+	widget.BaseWidget
+
+	image       *canvas.Image
+	frameWidth  int
+	frameHeight int
+
+	onTap          func(x, y float32)
+	onSecondaryTap func()
+	onSwipe        func(x1, y1, x2, y2 float32, duration time.Duration)
+	onScroll       func(x, y, dy float32)
+	onKey          func(key fyne.KeyName)
+	onRune         func(r rune)
+
+	dragging  bool
+	dragStart fyne.Position
+	dragLast  fyne.Position
+	dragFrom  time.Time
+}
+
+func newMirrorView() *mirrorView {
+	v := &mirrorView{image: canvas.NewImageFromImage(nil)}
+	v.image.FillMode = canvas.ImageFillContain
+	v.image.ScaleMode = canvas.ImageScaleFastest
+	v.ExtendBaseWidget(v)
+	return v
+}
+
+func (v *mirrorView) CreateRenderer() fyne.WidgetRenderer {
+	background := canvas.NewRectangle(color.Black)
+	return widget.NewSimpleRenderer(container.NewStack(background, v.image))
+}
+
+// setFrame 显示一帧，width/height 为缩放前的设备画面尺寸
+func (v *mirrorView) setFrame(img image.Image, width int, height int) {
+	v.frameWidth, v.frameHeight = width, height
+	v.image.Image = img
+	v.image.Refresh()
+}
+
+// setOriginalSize 按原始大小显示时让画布的最小尺寸等于图像尺寸
+func (v *mirrorView) setOriginalSize(original bool) {
+	if original {
+		v.image.FillMode = canvas.ImageFillOriginal
+	} else {
+		v.image.FillMode = canvas.ImageFillContain
+	}
+	v.image.Refresh()
+}
+
+// framePoint 将画布坐标换算为设备画面上的像素坐标。
+// 图像按比例居中显示（两种填充方式都是如此），落在黑边上时返回 false
+func (v *mirrorView) framePoint(pos fyne.Position) (float32, float32, bool) {
+	if v.frameWidth == 0 || v.frameHeight == 0 {
+		return 0, 0, false
+	}
+	size := v.Size()
+	if size.Width <= 0 || size.Height <= 0 {
+		return 0, 0, false
+	}
+	aspect := float32(v.frameWidth) / float32(v.frameHeight)
+	width, height := size.Width, size.Height
+	var padX, padY float32
+	if size.Width/size.Height > aspect {
+		width = size.Height * aspect
+		padX = (size.Width - width) / 2
+	} else {
+		height = size.Width / aspect
+		padY = (size.Height - height) / 2
+	}
+	x := (pos.X - padX) / width
+	y := (pos.Y - padY) / height
+	if x < 0 || x > 1 || y < 0 || y > 1 {
+		return 0, 0, false
+	}
+	return x * float32(v.frameWidth), y * float32(v.frameHeight), true
+}
+
+func (v *mirrorView) requestFocus() {
+	if c := fyne.CurrentApp().Driver().CanvasForObject(v); c != nil {
+		c.Focus(v)
+	}
+}
+
+func (v *mirrorView) Tapped(ev *fyne.PointEvent) {
+	v.requestFocus()
+	if x, y, ok := v.framePoint(ev.Position); ok && v.onTap != nil {
+		v.onTap(x, y)
+	}
+}
+
+// TappedSecondary 右键作为返回键
+func (v *mirrorView) TappedSecondary(*fyne.PointEvent) {
+	if v.onSecondaryTap != nil {
+		v.onSecondaryTap()
+	}
+}
+
+func (v *mirrorView) Dragged(ev *fyne.DragEvent) {
+	if !v.dragging {
+		v.dragging = true
+		v.dragStart = ev.Position.Subtract(ev.Dragged)
+		v.dragFrom = time.Now()
+	}
+	v.dragLast = ev.Position
+}
+
+// DragEnd 拖动结束后按实际拖动时长发送一次滑动
+func (v *mirrorView) DragEnd() {
+	if !v.dragging {
+		return
+	}
+	v.dragging = false
+	x1, y1, ok1 := v.framePoint(v.dragStart)
+	x2, y2, ok2 := v.framePoint(v.dragLast)
+	if !ok1 || !ok2 || v.onSwipe == nil {
+		return
+	}
+	duration := min(max(time.Since(v.dragFrom), 100*time.Millisecond), 3*time.Second)
+	v.onSwipe(x1, y1, x2, y2, duration)
+}
+
+func (v *mirrorView) Scrolled(ev *fyne.ScrollEvent) {
+	if x, y, ok := v.framePoint(ev.Position); ok && v.onScroll != nil {
+		v.onScroll(x, y, ev.Scrolled.DY)
+	}
+}
+
+func (v *mirrorView) FocusGained() {}
+func (v *mirrorView) FocusLost()   {}
+
+func (v *mirrorView) TypedRune(r rune) {
+	if v.onRune != nil {
+		v.onRune(r)
+	}
+}
+
+func (v *mirrorView) TypedKey(ev *fyne.KeyEvent) {
+	if v.onKey != nil {
+		v.onKey(ev.Name)
+	}
+}
+
+// mirrorKeyCodes 键盘按键到 Android 按键码的映射，字符通过 input text 发送
+var mirrorKeyCodes = map[fyne.KeyName]int{
+	fyne.KeyReturn:    constants.KeyCodeEnter,
+	fyne.KeyEnter:     constants.KeyCodeEnter,
+	fyne.KeyBackspace: constants.KeyCodeDelete,
+	fyne.KeyDelete:    constants.KeyCodeForwardDel,
+	fyne.KeyTab:       constants.KeyCodeTab,
+	fyne.KeyEscape:    constants.KeyCodeBack,
+	fyne.KeyUp:        constants.KeyCodeDpadUp,
+	fyne.KeyDown:      constants.KeyCodeDpadDown,
+	fyne.KeyLeft:      constants.KeyCodeDpadLeft,
+	fyne.KeyRight:     constants.KeyCodeDpadRight,
+	fyne.KeyHome:      constants.KeyCodeMoveHome,
+	fyne.KeyEnd:       constants.KeyCodeMoveEnd,
+	fyne.KeyPageUp:    constants.KeyCodePageUp,
+	fyne.KeyPageDown:  constants.KeyCodePageDown,
+}
+
+// mirrorButtons 投屏下方的导航与音量按钮
+var mirrorButtons = []struct {
+	label   string
+	keyCode int
+}{
+	{"返回", constants.KeyCodeBack},
+	{"主页", constants.KeyCodeHome},
+	{"最近任务", constants.KeyCodeAppSwitch},
+	{"电源", constants.KeyCodePower},
+	{"音量+", constants.KeyCodeVolumeUp},
+	{"音量-", constants.KeyCodeVolumeDown},
+	{"静音", constants.KeyCodeVolumeMute},
+}
+
+// createMirrorTab 投屏：循环通过 exec-out screencap 获取原始 RGBA 帧并显示，
+// 显示帧率与单帧延迟，可调整缩放比例与帧率上限。
+// 开启远程控制后，画布上的点击、拖动、滚轮和键盘输入通过 input 命令转发到设备
+func (ui *UI) createMirrorTab() fyne.CanvasObject {
+	var (
+		cancel       context.CancelFunc
+		mirrorDevice string           // 正在投屏的设备
+		display      *adb.DisplayInfo // 用于坐标换算的屏幕尺寸与方向
+		inputs       chan func()      // 按顺序执行的输入命令
+	)
+
+	view := newMirrorView()
+
+	statusLabel := widget.NewLabel("")
+	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
+	statusLabel.Truncation = fyne.TextTruncateEllipsis
+
+	statsLabel := widget.NewLabel("FPS: -  延迟: -")
+	statsLabel.TextStyle = fyne.TextStyle{Monospace: true}
+
+	scaleSelect := widget.NewSelect(mirrorScaleOptions, nil)
+	scaleSelect.SetSelected("50%")
+	fpsSelect := widget.NewSelect(mirrorFPSOptions, nil)
+	fpsSelect.SetSelected(mirrorFPSOptions[0])
+	originalCheck := widget.NewCheck("原始大小", func(checked bool) {
+		view.setOriginalSize(checked)
+	})
+	controlCheck := widget.NewCheck("远程控制", nil)
+	controlCheck.SetChecked(true)
+
+	// sendInput 将输入操作放入队列按顺序执行；未投屏时（按钮栏）直接发送到当前设备。
+	// 宏页面正在从投屏录制时同时记录该操作
+	sendInput := func(step adb.MacroStep) {
+		deviceID := mirrorDevice
+		if inputs == nil {
+			deviceID = ui.selectedDevice
+		}
+		if deviceID == "" {
+			return
+		}
+		if recorder := ui.macroRecorder; recorder != nil {
+			recorder.Record(step)
+		}
+		run := func() {
+			if err := adb.RunMacroStep(deviceID, step); err != nil {
+				logging.Error("远程输入失败: deviceID=%s, 错误: %v", deviceID, err)
+				fyne.Do(func() {
+					statusLabel.SetText("状态: 输入失败 - " + err.Error())
+				})
+			}
+		}
+		if inputs == nil {
+			go run()
+			return
+		}
+		select {
+		case inputs <- run:
+		default:
+			logging.Warn("远程输入队列已满，丢弃一次输入")
+		}
+	}
+
+	// toDevice 画面坐标换算为设备 input 坐标
+	toDevice := func(x, y float32) (int, int, bool) {
+		if display == nil || !controlCheck.Checked || inputs == nil {
+			return 0, 0, false
+		}
+		dx, dy := display.MapFramePoint(x, y, view.frameWidth, view.frameHeight)
+		return dx, dy, true
+	}
+
+	view.onTap = func(x, y float32) {
+		if dx, dy, ok := toDevice(x, y); ok {
+			sendInput(adb.MacroStep{Action: adb.MacroTap, X: dx, Y: dy})
+		}
+	}
+	view.onSecondaryTap = func() {
+		if controlCheck.Checked && inputs != nil {
+			sendInput(adb.MacroStep{Action: adb.MacroKey, KeyCode: constants.KeyCodeBack})
+		}
+	}
+	view.onSwipe = func(x1, y1, x2, y2 float32, duration time.Duration) {
+		dx1, dy1, ok := toDevice(x1, y1)
+		dx2, dy2, _ := toDevice(x2, y2)
+		if ok {
+			sendInput(adb.MacroStep{Action: adb.MacroSwipe, X: dx1, Y: dy1, X2: dx2, Y2: dy2, Duration: duration})
+		}
+	}
+
+	// 滚轮事件合并后作为一次滑动发送，避免每格滚动都执行一次 input
+	var (
+		scrollX, scrollY, scrollDY float32
+		scrollTimer                *time.Timer
+	)
+	view.onScroll = func(x, y, dy float32) {
+		if _, _, ok := toDevice(x, y); !ok {
+			return
+		}
+		scrollX, scrollY = x, y
+		scrollDY += dy
+		if scrollTimer != nil {
+			return
+		}
+		scrollTimer = time.AfterFunc(120*time.Millisecond, func() {
+			fyne.Do(func() {
+				scrollTimer = nil
+				limit := float32(view.frameHeight) * 0.8
+				distance := min(max(scrollDY*float32(view.frameHeight)/200, -limit), limit)
+				scrollDY = 0
+				x1, y1, ok := toDevice(scrollX, scrollY)
+				x2, y2, _ := toDevice(scrollX, scrollY+distance)
+				if ok {
+					sendInput(adb.MacroStep{Action: adb.MacroSwipe, X: x1, Y: y1, X2: x2, Y2: y2, Duration: 200 * time.Millisecond})
+				}
+			})
+		})
+	}
+
+	// 连续输入的字符合并为一次 input text
+	var (
+		pendingText []rune
+		textTimer   *time.Timer
+	)
+	flushText := func() {
+		if textTimer != nil {
+			textTimer.Stop()
+			textTimer = nil
+		}
+		if len(pendingText) == 0 {
+			return
+		}
+		text := string(pendingText)
+		pendingText = nil
+		sendInput(adb.MacroStep{Action: adb.MacroText, Text: text})
+	}
+	view.onRune = func(r rune) {
+		if !controlCheck.Checked || inputs == nil {
+			return
+		}
+		pendingText = append(pendingText, r)
+		if textTimer == nil {
+			textTimer = time.AfterFunc(150*time.Millisecond, func() {
+				fyne.Do(flushText)
+			})
+		}
+	}
+	view.onKey = func(key fyne.KeyName) {
+		keyCode, ok := mirrorKeyCodes[key]
+		if !ok || !controlCheck.Checked || inputs == nil {
+			return
+		}
+		flushText()
+		sendInput(adb.MacroStep{Action: adb.MacroKey, KeyCode: keyCode})
+	}
+
+	buttonBar := container.NewHBox()
+	for _, b := range mirrorButtons {
+		keyCode := b.keyCode
+		buttonBar.Add(widget.NewButton(b.label, func() {
+			sendInput(adb.MacroStep{Action: adb.MacroKey, KeyCode: keyCode})
+		}))
+	}
+
+	var startBtn, stopBtn *widget.Button
+
+	stop := func() {
+		if cancel != nil {
+			cancel()
+			cancel = nil
+		}
+		inputs = nil
+		display = nil
+		startBtn.Enable()
+		stopBtn.Disable()
+	}
+
+	startBtn = widget.NewButtonWithIcon("开始投屏", theme.MediaPlayIcon(), func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+		ctx, cancelFunc := context.WithCancel(context.Background())
+		cancel = cancelFunc
+		mirrorDevice = deviceID
+		queue := make(chan func(), 32)
+		inputs = queue
+		startBtn.Disable()
+		stopBtn.Enable()
+		statusLabel.SetText("状态: 正在投屏 " + deviceID)
+		logging.Info("开始投屏: deviceID=%s", deviceID)
+
+		// 输入命令在单独的协程中按顺序执行，不阻塞取帧
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case run := <-queue:
+					run()
+				}
+			}
+		}()
+
+		go func() {
+			var (
+				frames     int
+				windowFrom = time.Now()
+				fps        float64
+				lastWidth  int
+				lastHeight int
+			)
+			for ctx.Err() == nil {
+				// 选项在循环中读取，运行时调整立即生效
+				var scale float64
+				var fpsLimit int
+				fyne.DoAndWait(func() {
+					scale = mirrorScaleValues[scaleSelect.Selected]
+					fpsLimit = mirrorFPSValues[fpsSelect.Selected]
+				})
+
+				start := time.Now()
+				frameCtx, frameCancel := context.WithTimeout(ctx, 10*time.Second)
+				frame, err := adb.CaptureRawFrame(frameCtx, deviceID)
+				frameCancel()
+				if ctx.Err() != nil {
+					return
+				}
+				if err != nil {
+					logging.Error("投屏取帧失败: deviceID=%s, 错误: %v", deviceID, err)
+					fyne.Do(func() {
+						statusLabel.SetText(fmt.Sprintf("状态: 投屏中断 - %v", err))
+						stop()
+					})
+					return
+				}
+				width, height := frame.Bounds().Dx(), frame.Bounds().Dy()
+				scaled := adb.ScaleImage(frame, scale)
+				latency := time.Since(start)
+
+				// 首帧或画面方向变化（旋转）时重新获取屏幕尺寸与方向
+				if width != lastWidth || height != lastHeight {
+					lastWidth, lastHeight = width, height
+					info, err := adb.GetDisplayInfo(deviceID)
+					if err != nil {
+						logging.Warn("获取屏幕信息失败，远程控制不可用: %v", err)
+					}
+					fyne.DoAndWait(func() {
+						if ctx.Err() == nil {
+							display = info
+						}
+					})
+				}
+
+				frames++
+				if elapsed := time.Since(windowFrom); elapsed >= time.Second {
+					fps = float64(frames) / elapsed.Seconds()
+					frames, windowFrom = 0, time.Now()
+				}
+
+				detached := false
+				currentFPS := fps
+				fyne.DoAndWait(func() {
+					// 切换到其他功能页后画布已不在窗口中，停止取帧
+					if fyne.CurrentApp().Driver().CanvasForObject(view) == nil {
+						detached = true
+						return
+					}
+					view.setFrame(scaled, width, height)
+					statsLabel.SetText(fmt.Sprintf("FPS: %.1f  延迟: %dms  %dx%d", currentFPS, latency.Milliseconds(), width, height))
+				})
+				if detached {
+					logging.Info("投屏页面已关闭，停止投屏: deviceID=%s", deviceID)
+					fyne.Do(stop)
+					return
+				}
+
+				if fpsLimit > 0 {
+					if wait := time.Second/time.Duration(fpsLimit) - time.Since(start); wait > 0 {
+						select {
+						case <-ctx.Done():
+						case <-time.After(wait):
+						}
+					}
+				}
+			}
+		}()
+	})
+	startBtn.Importance = widget.HighImportance
+
+	stopBtn = widget.NewButtonWithIcon("停止", theme.MediaStopIcon(), func() {
+		stop()
+		statusLabel.SetText("状态: 投屏已停止")
+	})
+	stopBtn.Disable()
+
+	toolbar := container.NewHBox(
+		startBtn, stopBtn,
+		widget.NewLabel("缩放:"), scaleSelect,
+		widget.NewLabel("帧率上限:"), fpsSelect,
+		originalCheck, controlCheck,
+	)
+
+	hintLabel := widget.NewLabel("提示: 单击画面后可直接用键盘输入（仅 ASCII 字符），右键为返回，拖动为滑动，滚轮为上下滑动")
+	hintLabel.TextStyle = fyne.TextStyle{Italic: true}
+	hintLabel.Wrapping = fyne.TextWrapWord
+
+	sizer := canvas.NewRectangle(color.Transparent)
+	sizer.SetMinSize(fyne.NewSize(360, 480))
+
+	content := container.NewBorder(
+		container.NewVBox(toolbar, container.NewBorder(nil, nil, nil, statsLabel, statusLabel)),
+		container.NewVBox(buttonBar, hintLabel),
+		nil, nil,
+		container.NewScroll(container.NewStack(sizer, view)),
+	)
+
+	return container.NewPadded(content)
+}
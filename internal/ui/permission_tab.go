@@ -0,0 +1,282 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"yikong/internal/adb"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// createPermissionTab 查看并切换应用的运行时权限与 AppOps 模式，
+// 便于反复复现首次启动时的权限请求流程
+func (ui *UI) createPermissionTab() fyne.CanvasObject {
+	var (
+		permissions []adb.Permission
+		appOps      []adb.AppOp
+		packageName string
+	)
+
+	statusLabel := widget.NewLabel("")
+	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	packageEntry := widget.NewSelectEntry(nil)
+	packageEntry.SetPlaceHolder("输入或选择包名，如 com.example.app")
+
+	loadPackagesBtn := widget.NewButton("加载应用列表", func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+		statusLabel.SetText("状态: 正在加载第三方应用列表...")
+		go func() {
+			packages, err := adb.ListPackages(deviceID, true)
+			fyne.Do(func() {
+				if err != nil {
+					statusLabel.SetText(fmt.Sprintf("状态: 加载应用列表失败 - %v", err))
+					return
+				}
+				packageEntry.SetOptions(packages)
+				statusLabel.SetText(fmt.Sprintf("状态: 已加载 %d 个第三方应用", len(packages)))
+			})
+		}()
+	})
+
+	var permissionList, appOpList *widget.List
+	var refresh func()
+
+	// runAction 在后台执行一个修改操作，完成后刷新列表
+	runAction := func(description string, action func(deviceID string) error) {
+		if ui.selectedDevice == "" || packageName == "" {
+			return
+		}
+		deviceID := ui.selectedDevice
+		statusLabel.SetText("状态: 正在" + description + "...")
+		go func() {
+			err := action(deviceID)
+			fyne.Do(func() {
+				if err != nil {
+					logging.Error("%s失败: package=%s, 错误: %v", description, packageName, err)
+					statusLabel.SetText(fmt.Sprintf("状态: %s失败 - %v", description, err))
+				} else {
+					statusLabel.SetText("状态: " + description + "成功")
+				}
+				refresh()
+			})
+		}()
+	}
+
+	permissionList = widget.NewList(
+		func() int {
+			return len(permissions)
+		},
+		func() fyne.CanvasObject {
+			check := widget.NewCheck("", nil)
+			name := widget.NewLabel("权限名称")
+			name.Truncation = fyne.TextTruncateEllipsis
+			flags := widget.NewLabel("")
+			flags.TextStyle = fyne.TextStyle{Italic: true}
+			return container.NewBorder(nil, nil, check, flags, name)
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			row := o.(*fyne.Container)
+			name := row.Objects[0].(*widget.Label)
+			check := row.Objects[1].(*widget.Check)
+			flags := row.Objects[2].(*widget.Label)
+
+			perm := permissions[i]
+			name.SetText(perm.Name)
+
+			flagText := strings.Join(perm.Flags, "|")
+			if !perm.Runtime {
+				if flagText == "" {
+					flagText = "安装时权限"
+				} else {
+					flagText = "安装时权限 " + flagText
+				}
+			}
+			flags.SetText(flagText)
+
+			// 先清除回调再设置状态，避免刷新列表时触发授予/撤销
+			check.OnChanged = nil
+			check.SetChecked(perm.Granted)
+			if perm.Runtime {
+				check.Enable()
+			} else {
+				check.Disable()
+			}
+			check.OnChanged = func(granted bool) {
+				if granted {
+					runAction("授予 "+perm.Name, func(deviceID string) error {
+						return adb.GrantPermission(deviceID, packageName, perm.Name)
+					})
+				} else {
+					runAction("撤销 "+perm.Name, func(deviceID string) error {
+						return adb.RevokePermission(deviceID, packageName, perm.Name)
+					})
+				}
+			}
+		},
+	)
+
+	appOpList = widget.NewList(
+		func() int {
+			return len(appOps)
+		},
+		func() fyne.CanvasObject {
+			name := widget.NewLabel("AppOp")
+			name.Truncation = fyne.TextTruncateEllipsis
+			mode := widget.NewSelect(adb.AppOpModes, nil)
+			return container.NewBorder(nil, nil, nil, mode, name)
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			row := o.(*fyne.Container)
+			name := row.Objects[0].(*widget.Label)
+			mode := row.Objects[1].(*widget.Select)
+
+			op := appOps[i]
+			name.SetText(op.Name)
+
+			mode.OnChanged = nil
+			mode.SetSelected(op.Mode)
+			mode.OnChanged = func(selected string) {
+				if selected == op.Mode {
+					return
+				}
+				runAction(fmt.Sprintf("设置 %s 为 %s", op.Name, selected), func(deviceID string) error {
+					return adb.SetAppOpMode(deviceID, packageName, op.Name, selected)
+				})
+			}
+		},
+	)
+
+	refresh = func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		if packageName == "" {
+			return
+		}
+		deviceID := ui.selectedDevice
+		pkg := packageName
+		go func() {
+			perms, permErr := adb.GetPackagePermissions(deviceID, pkg)
+			ops, opsErr := adb.GetAppOps(deviceID, pkg)
+			fyne.Do(func() {
+				if permErr != nil {
+					statusLabel.SetText(fmt.Sprintf("状态: 获取权限失败 - %v", permErr))
+					return
+				}
+				permissions = perms
+				permissionList.Refresh()
+
+				if opsErr != nil {
+					logging.Warn("获取AppOps失败: package=%s, 错误: %v", pkg, opsErr)
+					appOps = nil
+				} else {
+					appOps = ops
+				}
+				appOpList.Refresh()
+
+				granted := 0
+				for _, p := range perms {
+					if p.Granted {
+						granted++
+					}
+				}
+				if !strings.Contains(statusLabel.Text, "失败") {
+					statusLabel.SetText(fmt.Sprintf("状态: %s 共 %d 个权限，已授予 %d 个，AppOps %d 项", pkg, len(perms), granted, len(appOps)))
+				}
+			})
+		}()
+	}
+
+	queryBtn := widget.NewButton("查询", func() {
+		packageName = strings.TrimSpace(packageEntry.Text)
+		if packageName == "" {
+			ui.showMessagePopup("提示", "请输入包名")
+			return
+		}
+		statusLabel.SetText("状态: 正在查询 " + packageName + " 的权限...")
+		refresh()
+	})
+	queryBtn.Importance = widget.HighImportance
+
+	grantAllBtn := widget.NewButton("授予全部运行时权限", func() {
+		runAction("授予全部运行时权限", func(deviceID string) error {
+			var failed []string
+			for _, p := range permissions {
+				if p.Runtime && !p.Granted {
+					if err := adb.GrantPermission(deviceID, packageName, p.Name); err != nil {
+						failed = append(failed, p.Name)
+					}
+				}
+			}
+			if len(failed) > 0 {
+				return fmt.Errorf("以下权限授予失败: %s", strings.Join(failed, ", "))
+			}
+			return nil
+		})
+	})
+
+	resetPermsBtn := widget.NewButton("重置运行时权限", func() {
+		perms := append([]adb.Permission(nil), permissions...)
+		runAction("重置运行时权限", func(deviceID string) error {
+			return adb.ResetRuntimePermissions(deviceID, packageName, perms)
+		})
+	})
+	resetPermsBtn.Importance = widget.WarningImportance
+
+	resetOpsBtn := widget.NewButton("重置AppOps", func() {
+		runAction("重置AppOps", func(deviceID string) error {
+			return adb.ResetAppOps(deviceID, packageName)
+		})
+	})
+	resetOpsBtn.Importance = widget.WarningImportance
+
+	clearDataBtn := widget.NewButton("清除应用数据", func() {
+		if packageName == "" {
+			return
+		}
+		dialog.ShowConfirm("清除应用数据", "将清除 "+packageName+" 的全部数据并撤销权限，确定继续吗？", func(ok bool) {
+			if !ok {
+				return
+			}
+			runAction("清除应用数据", func(deviceID string) error {
+				return adb.ClearAppData(deviceID, packageName)
+			})
+		}, ui.window)
+	})
+	clearDataBtn.Importance = widget.DangerImportance
+
+	permissionTitle := widget.NewLabel("权限")
+	permissionTitle.TextStyle = fyne.TextStyle{Bold: true}
+	appOpTitle := widget.NewLabel("AppOps")
+	appOpTitle.TextStyle = fyne.TextStyle{Bold: true}
+
+	split := container.NewHSplit(
+		container.NewBorder(permissionTitle, nil, nil, nil, permissionList),
+		container.NewBorder(appOpTitle, nil, nil, nil, appOpList),
+	)
+	split.Offset = 0.6
+
+	content := container.NewBorder(
+		container.NewVBox(
+			container.NewBorder(nil, nil, nil, container.NewHBox(loadPackagesBtn, queryBtn), packageEntry),
+			container.NewHBox(grantAllBtn, resetPermsBtn, resetOpsBtn, clearDataBtn),
+			statusLabel,
+			widget.NewSeparator(),
+		),
+		nil, nil, nil,
+		split,
+	)
+
+	return container.NewPadded(content)
+}
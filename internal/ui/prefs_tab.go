@@ -0,0 +1,371 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"strings"
+	"time"
+	"yikong/internal/adb"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// prefDisplayValue 键值在列表中的显示文本
+func prefDisplayValue(entry adb.PrefEntry) string {
+	if entry.Opaque() {
+		return "（不支持编辑，保存时原样保留）"
+	}
+	if entry.Type == adb.PrefSet {
+		return "[" + strings.Join(entry.Values, ", ") + "]"
+	}
+	return strings.ReplaceAll(entry.Value, "\n", "⏎")
+}
+
+// createPrefsTab SharedPreferences 编辑器：通过 run-as（或 su）直接读写 shared_prefs/*.xml，
+// 不依赖应用内置的广播接收器。保存时先强制停止应用，再整体替换文件
+func (ui *UI) createPrefsTab() fyne.CanvasObject {
+	var (
+		entries       []adb.PrefEntry
+		selected      = -1
+		dirty         bool
+		openedDevice  string // 当前文件来源的设备、应用与文件名
+		openedPackage string
+		openedFile    string
+		openedAccess  adb.FileAccess
+	)
+
+	statusLabel := widget.NewLabel("")
+	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	packageEntry := widget.NewSelectEntry(nil)
+	packageEntry.SetPlaceHolder("输入或选择包名，如 com.example.app")
+
+	fileSelect := widget.NewSelect(nil, nil)
+	fileSelect.PlaceHolder = "选择 SharedPreferences 文件"
+
+	loadPackagesBtn := widget.NewButton("加载应用列表", func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+		statusLabel.SetText("状态: 正在加载第三方应用列表...")
+		go func() {
+			packages, err := adb.ListPackages(deviceID, true)
+			fyne.Do(func() {
+				if err != nil {
+					statusLabel.SetText(fmt.Sprintf("状态: 加载应用列表失败 - %v", err))
+					return
+				}
+				packageEntry.SetOptions(packages)
+				statusLabel.SetText(fmt.Sprintf("状态: 已加载 %d 个第三方应用", len(packages)))
+			})
+		}()
+	})
+
+	listFilesBtn := widget.NewButton("列出文件", func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		pkg := strings.TrimSpace(packageEntry.Text)
+		if pkg == "" {
+			ui.showMessagePopup("提示", "请输入包名")
+			return
+		}
+		device := ui.selectedDevice
+		statusLabel.SetText("状态: 正在列出 " + pkg + " 的 SharedPreferences...")
+		go func() {
+			access, err := adb.AppPrivateAccess(device, pkg)
+			var names []string
+			if err == nil {
+				names, err = adb.ListSharedPrefs(device, pkg, access)
+			}
+			fyne.Do(func() {
+				if err != nil {
+					logging.Error("列出SharedPreferences失败: package=%s, 错误: %v", pkg, err)
+					statusLabel.SetText(fmt.Sprintf("状态: 列出文件失败 - %v", err))
+					return
+				}
+				fileSelect.Options = names
+				fileSelect.ClearSelected()
+				if len(names) > 0 {
+					fileSelect.SetSelectedIndex(0)
+				}
+				statusLabel.SetText(fmt.Sprintf("状态: %s 共 %d 个 SharedPreferences 文件（%s）", pkg, len(names), access))
+			})
+		}()
+	})
+
+	// 编辑表单
+	nameEntry := widget.NewEntry()
+	nameEntry.SetPlaceHolder("键名")
+	typeSelect := widget.NewSelect(adb.PrefTypes, nil)
+	typeSelect.SetSelected(adb.PrefString)
+	valueEntry := widget.NewMultiLineEntry()
+	valueEntry.SetPlaceHolder("值；set 类型每行一个元素，boolean 填 true/false")
+	valueEntry.SetMinRowsVisible(3)
+
+	var entryList *widget.List
+	var saveBtn *widget.Button
+
+	markDirty := func() {
+		dirty = true
+		saveBtn.Enable()
+		statusLabel.SetText(fmt.Sprintf("状态: %s 有未保存的修改（%d 项）", openedFile, len(entries)))
+	}
+
+	entryList = widget.NewList(
+		func() int {
+			return len(entries)
+		},
+		func() fyne.CanvasObject {
+			name := widget.NewLabel("键名")
+			name.Truncation = fyne.TextTruncateEllipsis
+			prefType := widget.NewLabel("boolean")
+			prefType.TextStyle = fyne.TextStyle{Italic: true}
+			value := widget.NewLabel("")
+			value.Truncation = fyne.TextTruncateEllipsis
+			return container.NewGridWithColumns(2, container.NewBorder(nil, nil, nil, prefType, name), value)
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			row := o.(*fyne.Container)
+			left := row.Objects[0].(*fyne.Container)
+			name := left.Objects[0].(*widget.Label)
+			prefType := left.Objects[1].(*widget.Label)
+			value := row.Objects[1].(*widget.Label)
+
+			entry := entries[i]
+			name.SetText(entry.Name)
+			prefType.SetText(entry.Type)
+			value.SetText(prefDisplayValue(entry))
+		},
+	)
+	entryList.OnSelected = func(id widget.ListItemID) {
+		selected = id
+		entry := entries[id]
+		nameEntry.SetText(entry.Name)
+		if entry.Opaque() {
+			valueEntry.SetText(entry.Raw)
+			return
+		}
+		typeSelect.SetSelected(entry.Type)
+		if entry.Type == adb.PrefSet {
+			valueEntry.SetText(strings.Join(entry.Values, "\n"))
+		} else {
+			valueEntry.SetText(entry.Value)
+		}
+	}
+	entryList.OnUnselected = func(widget.ListItemID) {
+		selected = -1
+	}
+
+	// formEntry 从表单读取键值并校验
+	formEntry := func() (adb.PrefEntry, error) {
+		entry := adb.PrefEntry{Name: strings.TrimSpace(nameEntry.Text), Type: typeSelect.Selected}
+		if entry.Name == "" {
+			return entry, fmt.Errorf("键名不能为空")
+		}
+		if entry.Type == adb.PrefSet {
+			for _, line := range strings.Split(valueEntry.Text, "\n") {
+				if line != "" {
+					entry.Values = append(entry.Values, line)
+				}
+			}
+			return entry, nil
+		}
+		entry.Value = valueEntry.Text
+		if entry.Type != adb.PrefString {
+			entry.Value = strings.TrimSpace(entry.Value)
+		}
+		return entry, adb.ValidatePrefValue(entry.Type, entry.Value)
+	}
+
+	// indexOf 查找键名，exclude 为正在编辑的条目
+	indexOf := func(name string, exclude int) int {
+		for i, entry := range entries {
+			if i != exclude && entry.Name == name {
+				return i
+			}
+		}
+		return -1
+	}
+
+	updateBtn := widget.NewButtonWithIcon("更新选中项", theme.DocumentSaveIcon(), func() {
+		if selected < 0 || selected >= len(entries) {
+			ui.showMessagePopup("提示", "请先在列表中选择一项")
+			return
+		}
+		if entries[selected].Opaque() {
+			ui.showMessagePopup("提示", fmt.Sprintf("<%s> 类型不支持编辑，保存时会原样保留；如不需要可以删除该项", entries[selected].Type))
+			return
+		}
+		entry, err := formEntry()
+		if err != nil {
+			ui.showMessagePopup("错误", err.Error())
+			return
+		}
+		if indexOf(entry.Name, selected) >= 0 {
+			ui.showMessagePopup("错误", "键名已存在: "+entry.Name)
+			return
+		}
+		entries[selected] = entry
+		entryList.RefreshItem(selected)
+		markDirty()
+	})
+
+	addBtn := widget.NewButtonWithIcon("新增", theme.ContentAddIcon(), func() {
+		if openedFile == "" {
+			ui.showMessagePopup("提示", "请先读取一个 SharedPreferences 文件")
+			return
+		}
+		entry, err := formEntry()
+		if err != nil {
+			ui.showMessagePopup("错误", err.Error())
+			return
+		}
+		if indexOf(entry.Name, -1) >= 0 {
+			ui.showMessagePopup("错误", "键名已存在: "+entry.Name)
+			return
+		}
+		entries = append(entries, entry)
+		entryList.Refresh()
+		entryList.Select(len(entries) - 1)
+		markDirty()
+	})
+
+	deleteBtn := widget.NewButtonWithIcon("删除", theme.DeleteIcon(), func() {
+		if selected < 0 || selected >= len(entries) {
+			ui.showMessagePopup("提示", "请先在列表中选择一项")
+			return
+		}
+		entries = append(entries[:selected], entries[selected+1:]...)
+		entryList.UnselectAll()
+		entryList.Refresh()
+		markDirty()
+	})
+	deleteBtn.Importance = widget.DangerImportance
+
+	loadFile := func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		pkg := strings.TrimSpace(packageEntry.Text)
+		name := fileSelect.Selected
+		if pkg == "" || name == "" {
+			ui.showMessagePopup("提示", "请输入包名并选择文件")
+			return
+		}
+		device := ui.selectedDevice
+		statusLabel.SetText("状态: 正在读取 " + name + "...")
+		go func() {
+			access, err := adb.AppPrivateAccess(device, pkg)
+			var loaded []adb.PrefEntry
+			if err == nil {
+				loaded, err = adb.ReadSharedPrefs(device, pkg, name, access)
+			}
+			fyne.Do(func() {
+				if err != nil {
+					logging.Error("读取SharedPreferences失败: package=%s, file=%s, 错误: %v", pkg, name, err)
+					statusLabel.SetText(fmt.Sprintf("状态: 读取失败 - %v", err))
+					return
+				}
+				entries = loaded
+				selected = -1
+				dirty = false
+				openedDevice, openedPackage, openedFile, openedAccess = device, pkg, name, access
+				entryList.UnselectAll()
+				entryList.Refresh()
+				saveBtn.Disable()
+				statusLabel.SetText(fmt.Sprintf("状态: 已读取 %s，共 %d 项", name, len(loaded)))
+			})
+		}()
+	}
+
+	readBtn := widget.NewButton("读取", func() {
+		if dirty {
+			dialog.ShowConfirm("放弃修改", "当前文件有未保存的修改，重新读取将丢弃这些修改。确定继续吗？", func(ok bool) {
+				if ok {
+					loadFile()
+				}
+			}, ui.window)
+			return
+		}
+		loadFile()
+	})
+	readBtn.Importance = widget.HighImportance
+
+	saveBtn = widget.NewButton("保存到设备", func() {
+		if openedFile == "" {
+			return
+		}
+		toWrite := append([]adb.PrefEntry(nil), entries...)
+		device, pkg, name, access := openedDevice, openedPackage, openedFile, openedAccess
+		message := fmt.Sprintf("将 %d 项写回 %s 的 %s。\n写入前会强制停止应用，确定继续吗？", len(toWrite), pkg, name)
+		dialog.ShowConfirm("保存 SharedPreferences", message, func(ok bool) {
+			if !ok {
+				return
+			}
+			statusLabel.SetText("状态: 正在保存 " + name + "...")
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				err := adb.WriteSharedPrefs(ctx, device, pkg, name, toWrite, access)
+				cancel()
+				fyne.Do(func() {
+					if err != nil {
+						logging.Error("保存SharedPreferences失败: package=%s, file=%s, 错误: %v", pkg, name, err)
+						statusLabel.SetText(fmt.Sprintf("状态: 保存失败 - %v", err))
+						ui.showMessagePopup("错误", "保存失败: "+err.Error())
+						return
+					}
+					logging.Info("SharedPreferences已保存: package=%s, file=%s", pkg, name)
+					dirty = false
+					saveBtn.Disable()
+					statusLabel.SetText("状态: 已保存 " + name + "，应用已被强制停止")
+				})
+			}()
+		}, ui.window)
+	})
+	saveBtn.Importance = widget.WarningImportance
+	saveBtn.Disable()
+
+	form := widget.NewForm(
+		widget.NewFormItem("键名", nameEntry),
+		widget.NewFormItem("类型", typeSelect),
+		widget.NewFormItem("值", valueEntry),
+	)
+
+	sizer := canvas.NewRectangle(color.Transparent)
+	sizer.SetMinSize(fyne.NewSize(0, 240))
+
+	hintLabel := widget.NewLabel("提示: 通过 run-as（不可调试的应用需 root）读写 shared_prefs 目录；应用运行时会用内存中的值覆盖文件，因此保存前会先强制停止应用")
+	hintLabel.Wrapping = fyne.TextWrapWord
+
+	content := container.NewBorder(
+		container.NewVBox(
+			container.NewBorder(nil, nil, nil, loadPackagesBtn, packageEntry),
+			container.NewBorder(nil, nil, nil, container.NewHBox(listFilesBtn, readBtn, saveBtn), fileSelect),
+			hintLabel,
+			statusLabel,
+			widget.NewSeparator(),
+		),
+		container.NewVBox(
+			widget.NewSeparator(),
+			form,
+			container.NewHBox(updateBtn, addBtn, deleteBtn),
+		),
+		nil, nil,
+		container.NewStack(sizer, entryList),
+	)
+
+	return container.NewPadded(content)
+}
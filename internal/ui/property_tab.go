@@ -0,0 +1,322 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"yikong/internal/adb"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 属性分组选项中表示不过滤的项
+const propertyGroupAll = "全部分组"
+
+// createPropertyTab 系统属性浏览器：列出 getprop 的全部属性，支持搜索、按前缀分组、
+// 与另一台设备对比，以及通过 setprop 修改可写属性
+func (ui *UI) createPropertyTab() fyne.CanvasObject {
+	var (
+		props        map[string]string
+		keys         []string // 过滤后显示的属性名
+		loadedDevice string
+	)
+
+	statusLabel := widget.NewLabel("")
+	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder("搜索属性名或值...")
+	groupSelect := widget.NewSelect([]string{propertyGroupAll}, nil)
+	groupSelect.SetSelected(propertyGroupAll)
+
+	keyEntry := widget.NewEntry()
+	keyEntry.SetPlaceHolder("属性名，如 debug.hwui.profile")
+	valueEntry := widget.NewEntry()
+	valueEntry.SetPlaceHolder("属性值")
+	warningLabel := widget.NewLabel("")
+	warningLabel.Wrapping = fyne.TextWrapWord
+	warningLabel.Importance = widget.WarningImportance
+
+	propertyList := widget.NewList(
+		func() int { return len(keys) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			key := keys[id]
+			obj.(*widget.Label).SetText(key + " = " + strings.ReplaceAll(props[key], "\n", "⏎"))
+		},
+	)
+
+	applyFilter := func() {
+		query := strings.ToLower(strings.TrimSpace(searchEntry.Text))
+		group := groupSelect.Selected
+		keys = keys[:0]
+		for key, value := range props {
+			if group != "" && group != propertyGroupAll && adb.PropertyGroup(key) != group {
+				continue
+			}
+			if query != "" && !strings.Contains(strings.ToLower(key), query) && !strings.Contains(strings.ToLower(value), query) {
+				continue
+			}
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		propertyList.UnselectAll()
+		propertyList.Refresh()
+		if props != nil {
+			statusLabel.SetText(fmt.Sprintf("状态: %s 共 %d 个属性，显示 %d 个", loadedDevice, len(props), len(keys)))
+		}
+	}
+	searchEntry.OnChanged = func(string) { applyFilter() }
+	groupSelect.OnChanged = func(string) { applyFilter() }
+
+	// setGroups 按属性数量从多到少列出分组
+	setGroups := func() {
+		counts := make(map[string]int)
+		for key := range props {
+			counts[adb.PropertyGroup(key)]++
+		}
+		groups := make([]string, 0, len(counts))
+		for group := range counts {
+			groups = append(groups, group)
+		}
+		slices.SortFunc(groups, func(a, b string) int {
+			if counts[a] != counts[b] {
+				return counts[b] - counts[a]
+			}
+			return strings.Compare(a, b)
+		})
+		groupSelect.Options = append([]string{propertyGroupAll}, groups...)
+		if !slices.Contains(groupSelect.Options, groupSelect.Selected) {
+			groupSelect.Selected = propertyGroupAll
+		}
+		groupSelect.Refresh()
+	}
+
+	keyEntry.OnChanged = func(key string) {
+		warningLabel.SetText(adb.PropertyWarning(strings.TrimSpace(key)))
+	}
+	propertyList.OnSelected = func(id widget.ListItemID) {
+		if id >= len(keys) {
+			return
+		}
+		keyEntry.SetText(keys[id])
+		valueEntry.SetText(props[keys[id]])
+	}
+
+	var refreshBtn, setBtn *widget.Button
+
+	load := func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+		refreshBtn.Disable()
+		statusLabel.SetText("状态: 正在读取属性...")
+		go func() {
+			result, err := adb.GetProperties(deviceID)
+			fyne.Do(func() {
+				refreshBtn.Enable()
+				if err != nil {
+					logging.Error("读取属性失败: deviceID=%s, 错误: %v", deviceID, err)
+					statusLabel.SetText("状态: 读取属性失败 - " + err.Error())
+					return
+				}
+				props, loadedDevice = result, deviceID
+				setGroups()
+				applyFilter()
+			})
+		}()
+	}
+	refreshBtn = widget.NewButtonWithIcon("读取属性", theme.ViewRefreshIcon(), load)
+	refreshBtn.Importance = widget.HighImportance
+
+	setBtn = widget.NewButton("设置 (setprop)", func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		key, value := strings.TrimSpace(keyEntry.Text), valueEntry.Text
+		if err := adb.ValidateProperty(key, value); err != nil {
+			ui.showMessagePopup("错误", err.Error())
+			return
+		}
+		deviceID := ui.selectedDevice
+		apply := func() {
+			setBtn.Disable()
+			statusLabel.SetText(fmt.Sprintf("状态: 正在设置 %s...", key))
+			go func() {
+				err := adb.SetProperty(deviceID, key, value)
+				fyne.Do(func() {
+					setBtn.Enable()
+					if err != nil {
+						logging.Error("设置属性失败: deviceID=%s, %s, 错误: %v", deviceID, key, err)
+						statusLabel.SetText("状态: 设置失败 - " + err.Error())
+						return
+					}
+					logging.Info("已设置属性: deviceID=%s, %s=%s", deviceID, key, value)
+					if props != nil && loadedDevice == deviceID {
+						if _, existed := props[key]; !existed {
+							props[key] = value
+							setGroups()
+						}
+						props[key] = value
+						applyFilter()
+					}
+					statusLabel.SetText(fmt.Sprintf("状态: 已设置 %s = %s", key, value))
+				})
+			}()
+		}
+		if warning := adb.PropertyWarning(key); warning != "" {
+			dialog.ShowConfirm("确认修改属性", fmt.Sprintf("%s\n\n确定将 %s 设置为 %q 吗？", warning, key, value), func(ok bool) {
+				if ok {
+					apply()
+				}
+			}, ui.window)
+			return
+		}
+		apply()
+	})
+
+	compareSelect := widget.NewSelect(nil, nil)
+	compareSelect.PlaceHolder = "选择对比设备"
+	loadCompareDevices := func() {
+		devices, err := adb.GetDevices()
+		if err != nil {
+			logging.Error("获取设备失败: %v", err)
+			return
+		}
+		var options []string
+		for _, d := range devices {
+			if d.ID != ui.selectedDevice {
+				options = append(options, d.ID)
+			}
+		}
+		compareSelect.SetOptions(options)
+	}
+	loadCompareDevices()
+	refreshCompareBtn := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), loadCompareDevices)
+
+	compareBtn := widget.NewButton("对比", func() {
+		other := compareSelect.Selected
+		if ui.selectedDevice == "" || other == "" {
+			ui.showMessagePopup("提示", "请选择当前设备和对比设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+		statusLabel.SetText(fmt.Sprintf("状态: 正在读取 %s 与 %s 的属性...", deviceID, other))
+		go func() {
+			left, err := adb.GetProperties(deviceID)
+			var right map[string]string
+			if err == nil {
+				right, err = adb.GetProperties(other)
+			}
+			fyne.Do(func() {
+				if err != nil {
+					statusLabel.SetText("状态: 读取属性失败 - " + err.Error())
+					return
+				}
+				diffs := adb.DiffProperties(left, right)
+				statusLabel.SetText(fmt.Sprintf("状态: %s 与 %s 共有 %d 个属性不同", deviceID, other, len(diffs)))
+				ui.showPropertyDiff(deviceID, other, diffs)
+			})
+		}()
+	})
+
+	hintLabel := widget.NewLabel("提示: ro.* 为只读属性；受 SELinux 限制，非 root 设备通常只能修改 debug.*、log.tag.* 等少数属性")
+	hintLabel.Wrapping = fyne.TextWrapWord
+
+	editForm := widget.NewForm(
+		widget.NewFormItem("属性名", keyEntry),
+		widget.NewFormItem("值", valueEntry),
+	)
+
+	top := container.NewVBox(
+		container.NewBorder(nil, nil, refreshBtn, groupSelect, searchEntry),
+		statusLabel,
+	)
+	bottom := container.NewVBox(
+		widget.NewSeparator(),
+		editForm,
+		warningLabel,
+		container.NewHBox(setBtn),
+		widget.NewSeparator(),
+		container.NewBorder(nil, nil, widget.NewLabel("与其它设备对比:"), container.NewHBox(refreshCompareBtn, compareBtn), compareSelect),
+		hintLabel,
+	)
+
+	return container.NewPadded(container.NewBorder(top, bottom, nil, nil, propertyList))
+}
+
+// showPropertyDiff 在新窗口中显示两台设备的属性差异
+func (ui *UI) showPropertyDiff(left, right string, diffs []adb.PropertyDiff) {
+	window := fyne.CurrentApp().NewWindow(fmt.Sprintf("属性对比 - %s / %s", left, right))
+
+	filtered := diffs
+	format := func(value string, missing bool) string {
+		if missing {
+			return "（不存在）"
+		}
+		return strings.ReplaceAll(value, "\n", "⏎")
+	}
+
+	header := container.NewGridWithColumns(3,
+		widget.NewLabelWithStyle("属性", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle(left, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle(right, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+	)
+
+	list := widget.NewList(
+		func() int { return len(filtered) },
+		func() fyne.CanvasObject {
+			cells := make([]fyne.CanvasObject, 3)
+			for i := range cells {
+				label := widget.NewLabel("")
+				label.TextStyle = fyne.TextStyle{Monospace: true}
+				label.Truncation = fyne.TextTruncateEllipsis
+				cells[i] = label
+			}
+			return container.NewGridWithColumns(3, cells...)
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			d := filtered[id]
+			cells := obj.(*fyne.Container).Objects
+			cells[0].(*widget.Label).SetText(d.Key)
+			cells[1].(*widget.Label).SetText(format(d.Left, d.MissingLeft))
+			cells[2].(*widget.Label).SetText(format(d.Right, d.MissingRight))
+		},
+	)
+
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder("过滤属性名...")
+	searchEntry.OnChanged = func(text string) {
+		query := strings.ToLower(strings.TrimSpace(text))
+		filtered = nil
+		for _, d := range diffs {
+			if query == "" || strings.Contains(strings.ToLower(d.Key), query) {
+				filtered = append(filtered, d)
+			}
+		}
+		list.Refresh()
+	}
+
+	summary := widget.NewLabel(fmt.Sprintf("共 %d 个属性不同", len(diffs)))
+	window.SetContent(container.NewPadded(container.NewBorder(
+		container.NewVBox(container.NewBorder(nil, nil, nil, summary, searchEntry), header, widget.NewSeparator()),
+		nil, nil, nil,
+		list,
+	)))
+	window.Resize(fyne.NewSize(1000, 600))
+	window.Show()
+}
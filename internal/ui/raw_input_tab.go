@@ -0,0 +1,258 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"yikong/internal/adb"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// createRawInputTab 原始输入事件：通过 getevent 录制触摸屏的全部事件，
+// 再用 sendevent 脚本按原始时序回放，可还原双指缩放等多点触控手势
+func (ui *UI) createRawInputTab() fyne.CanvasObject {
+	var (
+		recordCancel context.CancelFunc
+		playCancel   context.CancelFunc
+	)
+
+	statusLabel := widget.NewLabel("")
+	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	summaryLabel := widget.NewLabel("")
+	summaryLabel.TextStyle = fyne.TextStyle{Monospace: true}
+
+	editor := widget.NewMultiLineEntry()
+	editor.TextStyle = fyne.TextStyle{Monospace: true}
+	editor.SetPlaceHolder("录制的 getevent 事件将显示在这里，可编辑后回放")
+	editorScroll := container.NewScroll(editor)
+	editorScroll.SetMinSize(fyne.NewSize(600, 300))
+
+	touchOnlyCheck := widget.NewCheck("仅录制触摸屏", nil)
+	touchOnlyCheck.SetChecked(true)
+
+	deviceGroup, refreshDevices := ui.newDeviceCheckGroup()
+	refreshDevicesBtn := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), refreshDevices)
+	speedSelect := widget.NewSelect(macroSpeedOptions, nil)
+	speedSelect.SetSelected("1x")
+
+	// showRecording 显示录制内容与按设备统计的摘要
+	showRecording := func(r *adb.RawRecording) {
+		editor.SetText(adb.FormatRawRecording(r))
+		summaryLabel.SetText(fmt.Sprintf("时长 %.2f 秒\n%s", r.Duration().Seconds(), r.Summary()))
+	}
+
+	var recordBtn, stopRecordBtn, playBtn, stopPlayBtn *widget.Button
+
+	recordBtn = widget.NewButtonWithIcon("开始录制", theme.MediaRecordIcon(), func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+		touchOnly := touchOnlyCheck.Checked
+		ctx, cancel := context.WithCancel(context.Background())
+		recordCancel = cancel
+		recordBtn.Disable()
+		playBtn.Disable()
+		stopRecordBtn.Enable()
+		statusLabel.SetText("状态: 正在录制，请直接在设备上操作")
+		logging.Info("开始录制原始输入: deviceID=%s", deviceID)
+
+		var count atomic.Int64
+		// 事件数量定时刷新，避免每个事件都刷新界面
+		go func() {
+			ticker := time.NewTicker(300 * time.Millisecond)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					n := count.Load()
+					fyne.Do(func() {
+						statusLabel.SetText(fmt.Sprintf("状态: 正在录制，已收到 %d 个事件", n))
+					})
+				}
+			}
+		}()
+
+		go func() {
+			r, err := adb.RecordRawInput(ctx, deviceID, touchOnly, func(adb.InputEvent) {
+				count.Add(1)
+			})
+			cancel()
+			fyne.Do(func() {
+				recordCancel = nil
+				recordBtn.Enable()
+				playBtn.Enable()
+				stopRecordBtn.Disable()
+				if err != nil {
+					logging.Error("录制原始输入失败: deviceID=%s, 错误: %v", deviceID, err)
+					statusLabel.SetText("状态: 录制失败 - " + err.Error())
+					if r == nil || len(r.Events) == 0 {
+						return
+					}
+				}
+				if len(r.Events) == 0 {
+					statusLabel.SetText("状态: 录制结束，没有收到输入事件")
+					return
+				}
+				showRecording(r)
+				if err == nil {
+					statusLabel.SetText(fmt.Sprintf("状态: 录制完成，共 %d 个事件", len(r.Events)))
+				}
+			})
+		}()
+	})
+	recordBtn.Importance = widget.HighImportance
+
+	stopRecordBtn = widget.NewButtonWithIcon("停止录制", theme.MediaStopIcon(), func() {
+		if recordCancel != nil {
+			recordCancel()
+		}
+	})
+	stopRecordBtn.Importance = widget.DangerImportance
+	stopRecordBtn.Disable()
+
+	playBtn = widget.NewButtonWithIcon("回放", theme.MediaPlayIcon(), func() {
+		r, err := adb.ParseRawRecording(editor.Text)
+		if err != nil {
+			ui.showMessagePopup("错误", "事件内容有误: "+err.Error())
+			return
+		}
+		devices := append([]string(nil), deviceGroup.Selected...)
+		if len(devices) == 0 {
+			ui.showMessagePopup("错误", "请至少选择一个设备")
+			return
+		}
+		speed := macroSpeedValues[speedSelect.Selected]
+
+		ctx, cancel := context.WithCancel(context.Background())
+		playCancel = cancel
+		playBtn.Disable()
+		recordBtn.Disable()
+		stopPlayBtn.Enable()
+		summaryLabel.SetText(fmt.Sprintf("时长 %.2f 秒\n%s", r.Duration().Seconds(), r.Summary()))
+		statusLabel.SetText(fmt.Sprintf("状态: 正在 %d 台设备上回放 %d 个事件...", len(devices), len(r.Events)))
+
+		go func() {
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			var failures []string
+			for _, deviceID := range devices {
+				wg.Add(1)
+				go func(deviceID string) {
+					defer wg.Done()
+					if err := adb.ReplayRawRecording(ctx, deviceID, r, speed); err != nil && ctx.Err() == nil {
+						logging.Error("回放原始输入失败: deviceID=%s, 错误: %v", deviceID, err)
+						mu.Lock()
+						failures = append(failures, fmt.Sprintf("%s: %v", deviceID, err))
+						mu.Unlock()
+					}
+				}(deviceID)
+			}
+			wg.Wait()
+			stopped := ctx.Err() != nil
+			cancel()
+
+			fyne.Do(func() {
+				playCancel = nil
+				playBtn.Enable()
+				recordBtn.Enable()
+				stopPlayBtn.Disable()
+				switch {
+				case stopped:
+					statusLabel.SetText("状态: 回放已停止")
+				case len(failures) > 0:
+					statusLabel.SetText("状态: 回放失败 - " + strings.Join(failures, "; "))
+				default:
+					statusLabel.SetText("状态: 回放完成")
+				}
+			})
+		}()
+	})
+
+	stopPlayBtn = widget.NewButtonWithIcon("停止回放", theme.MediaStopIcon(), func() {
+		if playCancel != nil {
+			playCancel()
+		}
+	})
+	stopPlayBtn.Importance = widget.DangerImportance
+	stopPlayBtn.Disable()
+
+	openBtn := widget.NewButtonWithIcon("打开", theme.FolderOpenIcon(), func() {
+		fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			path := reader.URI().Path()
+			reader.Close()
+			r, err := adb.LoadRawRecording(path)
+			if err != nil {
+				ui.showMessagePopup("错误", "读取录制文件失败: "+err.Error())
+				return
+			}
+			showRecording(r)
+			statusLabel.SetText("状态: 已打开 " + path)
+		}, ui.window)
+		fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{adb.RawInputExtension, ".txt"}))
+		fileDialog.Show()
+	})
+
+	saveBtn := widget.NewButtonWithIcon("另存为", theme.DocumentSaveIcon(), func() {
+		r, err := adb.ParseRawRecording(editor.Text)
+		if err != nil {
+			ui.showMessagePopup("错误", "事件内容有误: "+err.Error())
+			return
+		}
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			dest := writer.URI().Path()
+			writer.Close()
+			if !strings.HasSuffix(dest, adb.RawInputExtension) {
+				dest += adb.RawInputExtension
+			}
+			if err := adb.SaveRawRecording(dest, r); err != nil {
+				ui.showMessagePopup("错误", "保存失败: "+err.Error())
+				return
+			}
+			statusLabel.SetText("状态: 已保存到 " + dest)
+		}, ui.window)
+		saveDialog.SetFileName("gesture" + adb.RawInputExtension)
+		saveDialog.Show()
+	})
+
+	hintLabel := widget.NewLabel("提示: 回放使用触摸屏原始坐标，适用于录制设备本身或同型号设备；不同设备按输入设备名称匹配 /dev/input 节点。sendevent 需要 shell 用户有 /dev/input 的写权限；每条 sendevent 需数毫秒，事件密集的手势回放会略慢于录制时")
+	hintLabel.Wrapping = fyne.TextWrapWord
+
+	form := widget.NewForm(
+		widget.NewFormItem("回放设备", container.NewBorder(nil, nil, nil, refreshDevicesBtn, deviceGroup)),
+		widget.NewFormItem("速度", speedSelect),
+	)
+
+	top := container.NewVBox(
+		container.NewHBox(recordBtn, stopRecordBtn, touchOnlyCheck, widget.NewSeparator(), openBtn, saveBtn),
+		form,
+		container.NewHBox(playBtn, stopPlayBtn),
+		hintLabel,
+		statusLabel,
+		summaryLabel,
+		widget.NewSeparator(),
+	)
+
+	return container.NewPadded(container.NewBorder(top, nil, nil, nil, editorScroll))
+}
@@ -0,0 +1,263 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"yikong/internal/adb"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 录屏码率选项（Mbps），"默认" 表示由 screenrecord 决定
+var (
+	recordBitRateOptions = []string{"默认", "4", "8", "12", "20", "40"}
+	recordSizeOptions    = []string{"默认", "1920x1080", "1280x720", "720x1280", "1080x1920"}
+)
+
+// defaultRecordingTemplate 录屏文件名模板的默认值（不含扩展名）
+const defaultRecordingTemplate = "{model}_{serial}_{time}"
+
+// defaultRecordingDir 录屏默认保存目录
+func defaultRecordingDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return os.TempDir()
+	}
+	return filepath.Join(home, "Videos", "yikong")
+}
+
+// createRecordTab 录屏：自动串联 3 分钟的分段，停止时发送 SIGINT 保证文件完整，
+// 结束后拉取到本地、可选用 ffmpeg 拼接，并删除设备上的临时文件
+func (ui *UI) createRecordTab() fyne.CanvasObject {
+	var recorder *adb.ScreenRecorder
+
+	statusLabel := widget.NewLabel("")
+	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	elapsedLabel := widget.NewLabel("00:00")
+	elapsedLabel.TextStyle = fyne.TextStyle{Bold: true, Monospace: true}
+
+	sizeSelect := widget.NewSelectEntry(recordSizeOptions)
+	sizeSelect.SetText(recordSizeOptions[0])
+	bitRateSelect := widget.NewSelect(recordBitRateOptions, nil)
+	bitRateSelect.SetSelected(recordBitRateOptions[0])
+	timeLimitEntry := widget.NewEntry()
+	timeLimitEntry.SetPlaceHolder("秒，0 或留空表示直到手动停止")
+
+	dirEntry := widget.NewEntry()
+	dirEntry.SetText(defaultRecordingDir())
+	browseBtn := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+		folderDialog := dialog.NewFolderOpen(func(uri fyne.ListableURI, err error) {
+			if err != nil || uri == nil {
+				return
+			}
+			dirEntry.SetText(uri.Path())
+		}, ui.window)
+		folderDialog.Show()
+	})
+	templateEntry := widget.NewEntry()
+	templateEntry.SetText(defaultRecordingTemplate)
+
+	concatCheck := widget.NewCheck("拼接多个分段（需要 ffmpeg）", nil)
+	concatCheck.SetChecked(true)
+	deleteCheck := widget.NewCheck("拉取后删除设备上的文件", nil)
+	deleteCheck.SetChecked(true)
+
+	outputDisplay := widget.NewMultiLineEntry()
+	outputDisplay.Wrapping = fyne.TextWrapWord
+	outputDisplay.SetPlaceHolder("录屏输出将显示在这里...")
+	outputDisplay.Disable()
+	outputScroll := container.NewScroll(outputDisplay)
+	outputScroll.SetMinSize(fyne.NewSize(600, 200))
+
+	appendOutput := func(line string) {
+		fyne.Do(func() {
+			outputDisplay.SetText(outputDisplay.Text + line + "\n")
+			outputScroll.ScrollToBottom()
+		})
+	}
+
+	var startBtn, stopBtn *widget.Button
+
+	// finish 录制结束后拉取分段、拼接并清理设备文件
+	finish := func(r *adb.ScreenRecorder, dir string, template string, concat bool, deleteRemote bool) {
+		if err := r.Err(); err != nil {
+			appendOutput(err.Error())
+		}
+		segments := r.Segments()
+		if len(segments) == 0 {
+			fyne.Do(func() {
+				startBtn.Enable()
+				stopBtn.Disable()
+				statusLabel.SetText("状态: 没有录制到任何内容")
+			})
+			return
+		}
+
+		model, _ := adb.GetDeviceName(r.DeviceID)
+		baseName := adb.ExpandFileTemplate(template, r.DeviceID, model, time.Now())
+		fyne.Do(func() {
+			statusLabel.SetText(fmt.Sprintf("状态: 正在拉取 %d 个分段...", len(segments)))
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+		files, err := adb.FetchRecording(ctx, r.DeviceID, segments, dir, baseName, deleteRemote, func(p string, transferred, total int64) {
+			fyne.Do(func() {
+				statusLabel.SetText(fmt.Sprintf("状态: 正在拉取 %s  %s / %s", filepath.Base(p), formatSize(transferred), formatSize(total)))
+			})
+		})
+		for _, file := range files {
+			appendOutput("已保存: " + file)
+		}
+
+		result := ""
+		if err != nil {
+			logging.Error("拉取录屏失败: deviceID=%s, 错误: %v", r.DeviceID, err)
+			result = "状态: 拉取失败 - " + err.Error()
+		} else if concat && len(files) > 1 {
+			output := filepath.Join(dir, baseName+".mp4")
+			if cerr := adb.ConcatVideos(ctx, files, output); cerr != nil {
+				logging.Warn("拼接录屏失败: %v", cerr)
+				appendOutput("拼接失败，已保留各分段: " + cerr.Error())
+				result = fmt.Sprintf("状态: 录屏已保存为 %d 个分段", len(files))
+			} else {
+				for _, file := range files {
+					os.Remove(file)
+				}
+				appendOutput("已拼接: " + output)
+				result = "状态: 录屏已保存到 " + output
+			}
+		} else {
+			result = fmt.Sprintf("状态: 录屏已保存（%d 个文件）", len(files))
+		}
+
+		fyne.Do(func() {
+			startBtn.Enable()
+			stopBtn.Disable()
+			statusLabel.SetText(result)
+		})
+	}
+
+	startBtn = widget.NewButtonWithIcon("开始录屏", theme.MediaRecordIcon(), func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		opts := adb.RecordOptions{}
+		if size := strings.TrimSpace(sizeSelect.Text); size != "" && size != recordSizeOptions[0] {
+			opts.Size = size
+		}
+		if mbps, err := strconv.Atoi(bitRateSelect.Selected); err == nil {
+			opts.BitRate = mbps * 1000000
+		}
+		if text := strings.TrimSpace(timeLimitEntry.Text); text != "" {
+			seconds, err := strconv.Atoi(text)
+			if err != nil || seconds < 0 {
+				ui.showMessagePopup("错误", "时长上限应为非负整数（秒）")
+				return
+			}
+			opts.TimeLimit = time.Duration(seconds) * time.Second
+		}
+		dir := strings.TrimSpace(dirEntry.Text)
+		if dir == "" {
+			ui.showMessagePopup("提示", "请填写保存目录")
+			return
+		}
+		template := templateEntry.Text
+		concat, deleteRemote := concatCheck.Checked, deleteCheck.Checked
+
+		r, err := adb.StartScreenRecord(ui.selectedDevice, opts, func(index int, remotePath string) {
+			appendOutput(fmt.Sprintf("分段 %d: %s", index+1, remotePath))
+		})
+		if err != nil {
+			ui.showMessagePopup("错误", err.Error())
+			return
+		}
+		recorder = r
+		outputDisplay.SetText("")
+		startBtn.Disable()
+		stopBtn.Enable()
+		statusLabel.SetText("状态: 正在录屏 " + r.DeviceID + "...")
+
+		// 计时显示
+		go func() {
+			ticker := time.NewTicker(500 * time.Millisecond)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-r.Done():
+					return
+				case <-ticker.C:
+					elapsed := r.Elapsed()
+					fyne.Do(func() {
+						elapsedLabel.SetText(fmt.Sprintf("%02d:%02d", int(elapsed.Minutes()), int(elapsed.Seconds())%60))
+					})
+				}
+			}
+		}()
+
+		// 到达时长上限或出错时自动收尾；手动停止时同样在这里收尾
+		go func() {
+			<-r.Done()
+			fyne.Do(func() {
+				stopBtn.Disable()
+			})
+			finish(r, dir, template, concat, deleteRemote)
+		}()
+	})
+	startBtn.Importance = widget.HighImportance
+
+	stopBtn = widget.NewButtonWithIcon("停止并保存", theme.MediaStopIcon(), func() {
+		if recorder == nil {
+			return
+		}
+		r := recorder
+		stopBtn.Disable()
+		statusLabel.SetText("状态: 正在停止录屏...")
+		go func() {
+			if err := r.Stop(); err != nil {
+				appendOutput(err.Error())
+			}
+		}()
+	})
+	stopBtn.Importance = widget.DangerImportance
+	stopBtn.Disable()
+
+	form := widget.NewForm(
+		widget.NewFormItem("分辨率", sizeSelect),
+		widget.NewFormItem("码率 (Mbps)", bitRateSelect),
+		widget.NewFormItem("时长上限", timeLimitEntry),
+		widget.NewFormItem("保存目录", container.NewBorder(nil, nil, nil, browseBtn, dirEntry)),
+		widget.NewFormItem("文件名模板", templateEntry),
+	)
+
+	hintLabel := widget.NewLabel("提示: screenrecord 单次最长 3 分钟，超过后会自动开始新的分段；文件名模板支持 {serial} {model} {date} {time} {timestamp}")
+	hintLabel.Wrapping = fyne.TextWrapWord
+
+	content := container.NewBorder(
+		container.NewVBox(
+			form,
+			container.NewHBox(concatCheck, deleteCheck),
+			container.NewHBox(startBtn, stopBtn, elapsedLabel),
+			hintLabel,
+			statusLabel,
+			widget.NewSeparator(),
+		),
+		nil, nil, nil,
+		outputScroll,
+	)
+
+	return container.NewPadded(content)
+}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"yikong/internal/adb"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// createScreenPage 屏幕控制页面：截图、录屏、投屏、输入宏、虚拟按键等与设备屏幕和输入相关的功能
+func (ui *UI) createScreenPage() fyne.CanvasObject {
+	tabs := container.NewAppTabs(
+		container.NewTabItem("截图", ui.createScreenshotTab()),
+		container.NewTabItem("录屏", ui.createRecordTab()),
+		container.NewTabItem("投屏", ui.createMirrorTab()),
+		container.NewTabItem("宏", ui.createMacroTab()),
+		container.NewTabItem("原始事件", ui.createRawInputTab()),
+		container.NewTabItem("按键", ui.createKeypadTab()),
+	)
+	return tabs
+}
+
+// newDeviceCheckGroup 创建多设备勾选框，默认勾选当前设备；返回勾选框与刷新设备列表的函数
+func (ui *UI) newDeviceCheckGroup() (*widget.CheckGroup, func()) {
+	deviceGroup := widget.NewCheckGroup(nil, nil)
+	deviceGroup.Horizontal = true
+
+	refreshDevices := func() {
+		devices, err := adb.GetDevices()
+		if err != nil {
+			logging.Error("获取设备失败: %v", err)
+			return
+		}
+		var options []string
+		for _, d := range devices {
+			options = append(options, d.ID)
+		}
+		deviceGroup.Options = options
+
+		selected := deviceGroup.Selected
+		if len(selected) == 0 && ui.selectedDevice != "" {
+			selected = []string{ui.selectedDevice}
+		}
+		deviceGroup.SetSelected(selected)
+		deviceGroup.Refresh()
+	}
+	refreshDevices()
+	return deviceGroup, refreshDevices
+}
@@ -0,0 +1,340 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"yikong/internal/adb"
+	"yikong/internal/logging"
+	"yikong/internal/utilities"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// screenshot 一张已读入内存的截图
+type screenshot struct {
+	deviceID string
+	model    string
+	taken    time.Time
+	data     []byte
+	width    int
+	height   int
+}
+
+// fileName 按模板生成文件名，模板没有扩展名时补上 .png
+func (s *screenshot) fileName(template string) string {
+	name := adb.ExpandFileTemplate(template, s.deviceID, s.model, s.taken)
+	if !strings.EqualFold(filepath.Ext(name), ".png") {
+		name += ".png"
+	}
+	return name
+}
+
+// captureScreenshot 截取一台设备的屏幕
+func captureScreenshot(deviceID string) (*screenshot, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	data, err := adb.CaptureScreenshot(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	shot := &screenshot{deviceID: deviceID, taken: time.Now(), data: data}
+	shot.model, _ = adb.GetDeviceName(deviceID)
+	if config, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		shot.width, shot.height = config.Width, config.Height
+	}
+	return shot, nil
+}
+
+// defaultScreenshotDir 截图默认保存目录
+func defaultScreenshotDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return os.TempDir()
+	}
+	return filepath.Join(home, "Pictures", "yikong")
+}
+
+// saveScreenshot 将截图写入 dir，文件名由模板生成，返回完整路径
+func saveScreenshot(shot *screenshot, dir string, template string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	target := filepath.Join(dir, shot.fileName(template))
+	if err := os.WriteFile(target, shot.data, 0644); err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+// copyScreenshot 将截图复制到系统剪贴板
+func copyScreenshot(shot *screenshot) error {
+	tmp := filepath.Join(os.TempDir(), "yikong_clipboard.png")
+	if err := os.WriteFile(tmp, shot.data, 0644); err != nil {
+		return err
+	}
+	return utilities.CopyImageToClipboard(tmp)
+}
+
+// showScreenshotWindow 在独立窗口中预览截图，可另存为或复制到剪贴板
+func (ui *UI) showScreenshotWindow(shot *screenshot, template string) {
+	window := fyne.CurrentApp().NewWindow(fmt.Sprintf("截图 - %s (%s)", shot.model, shot.deviceID))
+
+	img := canvas.NewImageFromReader(bytes.NewReader(shot.data), shot.fileName(template))
+	img.FillMode = canvas.ImageFillContain
+	img.ScaleMode = canvas.ImageScaleSmooth
+
+	infoLabel := widget.NewLabel(fmt.Sprintf("%d×%d  %s  %s", shot.width, shot.height, formatSize(int64(len(shot.data))), shot.taken.Format("2006-01-02 15:04:05")))
+
+	saveBtn := widget.NewButtonWithIcon("另存为PNG", theme.DocumentSaveIcon(), func() {
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			defer writer.Close()
+			if _, err := writer.Write(shot.data); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			logging.Info("截图已保存: %s", writer.URI().Path())
+		}, window)
+		saveDialog.SetFileName(shot.fileName(template))
+		saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".png"}))
+		saveDialog.Show()
+	})
+	saveBtn.Importance = widget.HighImportance
+
+	copyBtn := widget.NewButtonWithIcon("复制到剪贴板", theme.ContentCopyIcon(), func() {
+		go func() {
+			err := copyScreenshot(shot)
+			fyne.Do(func() {
+				if err != nil {
+					logging.Error("复制截图失败: %v", err)
+					dialog.ShowError(err, window)
+					return
+				}
+				infoLabel.SetText(infoLabel.Text + "  已复制")
+			})
+		}()
+	})
+
+	window.SetContent(container.NewBorder(
+		nil,
+		container.NewBorder(nil, nil, infoLabel, container.NewHBox(copyBtn, saveBtn)),
+		nil, nil,
+		img,
+	))
+
+	// 按屏幕方向给一个合适的初始窗口大小
+	size := fyne.NewSize(420, 820)
+	if shot.width > shot.height {
+		size = fyne.NewSize(960, 560)
+	}
+	window.Resize(size)
+	window.Show()
+}
+
+// createScreenshotTab 截图：通过 exec-out screencap -p 直接读入内存，
+// 支持预览、另存为、复制到剪贴板，以及对多台设备批量截图并按模板自动命名保存
+func (ui *UI) createScreenshotTab() fyne.CanvasObject {
+	var latest *screenshot
+
+	statusLabel := widget.NewLabel("")
+	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	deviceGroup, refreshDevices := ui.newDeviceCheckGroup()
+	refreshDevicesBtn := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), refreshDevices)
+
+	dirEntry := widget.NewEntry()
+	dirEntry.SetText(defaultScreenshotDir())
+	browseBtn := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+		folderDialog := dialog.NewFolderOpen(func(uri fyne.ListableURI, err error) {
+			if err != nil || uri == nil {
+				return
+			}
+			dirEntry.SetText(uri.Path())
+		}, ui.window)
+		folderDialog.Show()
+	})
+
+	templateEntry := widget.NewEntry()
+	templateEntry.SetText(adb.DefaultScreenshotTemplate)
+	templateHint := widget.NewLabel("可用占位符: {serial} {model} {date} {time} {timestamp}")
+	templateHint.TextStyle = fyne.TextStyle{Italic: true}
+
+	autoSaveCheck := widget.NewCheck("截图后自动保存到目录", nil)
+	previewCheck := widget.NewCheck("截图后打开预览窗口", nil)
+	previewCheck.SetChecked(true)
+
+	thumbnail := canvas.NewImageFromResource(nil)
+	thumbnail.FillMode = canvas.ImageFillContain
+	thumbnail.SetMinSize(fyne.NewSize(240, 320))
+
+	outputDisplay := widget.NewMultiLineEntry()
+	outputDisplay.Wrapping = fyne.TextWrapWord
+	outputDisplay.SetPlaceHolder("批量截图结果将显示在这里...")
+	outputDisplay.Disable()
+	outputScroll := container.NewScroll(outputDisplay)
+	outputScroll.SetMinSize(fyne.NewSize(360, 200))
+
+	appendOutput := func(line string) {
+		fyne.Do(func() {
+			outputDisplay.SetText(outputDisplay.Text + line + "\n")
+			outputScroll.ScrollToBottom()
+		})
+	}
+
+	var captureBtn, batchBtn, openBtn *widget.Button
+
+	showLatest := func(shot *screenshot) {
+		latest = shot
+		thumbnail.Resource = nil
+		thumbnail.Image, _, _ = image.Decode(bytes.NewReader(shot.data))
+		thumbnail.Refresh()
+		openBtn.Enable()
+	}
+
+	captureBtn = widget.NewButtonWithIcon("截图", theme.MediaPhotoIcon(), func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+		dir, template := strings.TrimSpace(dirEntry.Text), templateEntry.Text
+		autoSave, preview := autoSaveCheck.Checked, previewCheck.Checked
+		captureBtn.Disable()
+		statusLabel.SetText("状态: 正在截图 " + deviceID + "...")
+
+		go func() {
+			shot, err := captureScreenshot(deviceID)
+			saved := ""
+			if err == nil && autoSave {
+				saved, err = saveScreenshot(shot, dir, template)
+			}
+			fyne.Do(func() {
+				captureBtn.Enable()
+				if err != nil {
+					logging.Error("截图失败: deviceID=%s, 错误: %v", deviceID, err)
+					statusLabel.SetText(fmt.Sprintf("状态: 截图失败 - %v", err))
+					ui.showMessagePopup("错误", "截图失败: "+err.Error())
+					return
+				}
+				showLatest(shot)
+				if saved != "" {
+					statusLabel.SetText("状态: 截图已保存到 " + saved)
+				} else {
+					statusLabel.SetText(fmt.Sprintf("状态: 截图完成 %d×%d", shot.width, shot.height))
+				}
+				if preview {
+					ui.showScreenshotWindow(shot, template)
+				}
+			})
+		}()
+	})
+	captureBtn.Importance = widget.HighImportance
+
+	batchBtn = widget.NewButtonWithIcon("批量截图并保存", theme.DocumentSaveIcon(), func() {
+		devices := append([]string(nil), deviceGroup.Selected...)
+		if len(devices) == 0 {
+			ui.showMessagePopup("错误", "请至少选择一个设备")
+			return
+		}
+		dir, template := strings.TrimSpace(dirEntry.Text), templateEntry.Text
+		if dir == "" {
+			ui.showMessagePopup("提示", "请填写保存目录")
+			return
+		}
+		// 同一秒内多台设备使用相同的文件名会互相覆盖
+		if len(devices) > 1 && !strings.Contains(template, "{serial}") {
+			ui.showMessagePopup("提示", "批量截图时文件名模板必须包含 {serial}，否则各设备的截图会互相覆盖")
+			return
+		}
+		batchBtn.Disable()
+		outputDisplay.SetText("")
+		statusLabel.SetText(fmt.Sprintf("状态: 正在对 %d 台设备截图...", len(devices)))
+
+		go func() {
+			var (
+				wg     sync.WaitGroup
+				mu     sync.Mutex
+				last   *screenshot
+				failed int
+			)
+			for _, deviceID := range devices {
+				wg.Add(1)
+				go func(deviceID string) {
+					defer wg.Done()
+					shot, err := captureScreenshot(deviceID)
+					saved := ""
+					if err == nil {
+						saved, err = saveScreenshot(shot, dir, template)
+					}
+					mu.Lock()
+					defer mu.Unlock()
+					if err != nil {
+						failed++
+						logging.Error("批量截图失败: deviceID=%s, 错误: %v", deviceID, err)
+						appendOutput(fmt.Sprintf("[%s] 失败: %v", deviceID, err))
+						return
+					}
+					last = shot
+					appendOutput(fmt.Sprintf("[%s] %s", deviceID, saved))
+				}(deviceID)
+			}
+			wg.Wait()
+			fyne.Do(func() {
+				batchBtn.Enable()
+				if last != nil {
+					showLatest(last)
+				}
+				statusLabel.SetText(fmt.Sprintf("状态: 批量截图完成，成功 %d 台，失败 %d 台", len(devices)-failed, failed))
+			})
+		}()
+	})
+
+	openBtn = widget.NewButtonWithIcon("预览最近截图", theme.ZoomInIcon(), func() {
+		if latest != nil {
+			ui.showScreenshotWindow(latest, templateEntry.Text)
+		}
+	})
+	openBtn.Disable()
+
+	form := widget.NewForm(
+		widget.NewFormItem("保存目录", container.NewBorder(nil, nil, nil, browseBtn, dirEntry)),
+		widget.NewFormItem("文件名模板", templateEntry),
+		widget.NewFormItem("", templateHint),
+	)
+
+	controls := container.NewVBox(
+		form,
+		container.NewHBox(autoSaveCheck, previewCheck),
+		container.NewHBox(captureBtn, openBtn),
+		widget.NewSeparator(),
+		container.NewBorder(nil, nil, widget.NewLabel("批量设备:"), refreshDevicesBtn, deviceGroup),
+		batchBtn,
+		statusLabel,
+	)
+
+	content := container.NewBorder(
+		nil, nil, nil,
+		thumbnail,
+		container.NewBorder(controls, nil, nil, nil, outputScroll),
+	)
+
+	return container.NewPadded(content)
+}
@@ -0,0 +1,397 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"yikong/internal/adb"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// createSettingsTab 系统设置编辑器：按命名空间列出 settings，支持搜索、修改、删除与重置，
+// 以及保存和应用命名的设置配置（如“关闭动画”“充电时保持唤醒”）
+func (ui *UI) createSettingsTab() fyne.CanvasObject {
+	var (
+		settings      map[string]string
+		keys          []string // 过滤后显示的键
+		loadedDevice  string
+		loadedNS      string
+		undoSettings  []adb.SettingValue // 最近一次应用配置前的快照
+		undoDevice    string
+		savedProfiles []adb.SettingsProfile
+		note          string // 重新读取后在状态中附带显示的上一次操作结果
+	)
+	profilePath := adb.DefaultSettingsProfilePath()
+	if loaded, err := adb.LoadSettingsProfiles(profilePath); err != nil {
+		logging.Error("读取设置配置失败: %v", err)
+	} else {
+		savedProfiles = loaded
+	}
+
+	statusLabel := widget.NewLabel("")
+	statusLabel.TextStyle = fyne.TextStyle{Bold: true}
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	namespaceSelect := widget.NewSelect(adb.SettingsNamespaces, nil)
+	namespaceSelect.SetSelected(adb.SettingsGlobal)
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder("搜索键或值...")
+
+	keyEntry := widget.NewEntry()
+	keyEntry.SetPlaceHolder("键，如 window_animation_scale")
+	valueEntry := widget.NewEntry()
+	valueEntry.SetPlaceHolder("值")
+
+	settingsList := widget.NewList(
+		func() int { return len(keys) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			key := keys[id]
+			obj.(*widget.Label).SetText(key + " = " + settings[key])
+		},
+	)
+	settingsList.OnSelected = func(id widget.ListItemID) {
+		if id >= len(keys) {
+			return
+		}
+		keyEntry.SetText(keys[id])
+		valueEntry.SetText(settings[keys[id]])
+	}
+
+	applyFilter := func() {
+		query := strings.ToLower(strings.TrimSpace(searchEntry.Text))
+		keys = keys[:0]
+		for key, value := range settings {
+			if query != "" && !strings.Contains(strings.ToLower(key), query) && !strings.Contains(strings.ToLower(value), query) {
+				continue
+			}
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		settingsList.UnselectAll()
+		settingsList.Refresh()
+		if settings != nil {
+			prefix := "状态: "
+			if note != "" {
+				prefix += note + "；"
+				note = ""
+			}
+			statusLabel.SetText(fmt.Sprintf("%s%s %s 共 %d 项，显示 %d 项", prefix, loadedDevice, loadedNS, len(settings), len(keys)))
+		}
+	}
+	searchEntry.OnChanged = func(string) { applyFilter() }
+
+	var refreshBtn *widget.Button
+	load := func() {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID, namespace := ui.selectedDevice, namespaceSelect.Selected
+		refreshBtn.Disable()
+		statusLabel.SetText(fmt.Sprintf("状态: 正在读取 %s 设置...", namespace))
+		go func() {
+			result, err := adb.ListSettings(deviceID, namespace)
+			fyne.Do(func() {
+				refreshBtn.Enable()
+				if err != nil {
+					logging.Error("读取设置失败: deviceID=%s, namespace=%s, 错误: %v", deviceID, namespace, err)
+					statusLabel.SetText("状态: 读取设置失败 - " + err.Error())
+					return
+				}
+				settings, loadedDevice, loadedNS = result, deviceID, namespace
+				applyFilter()
+			})
+		}()
+	}
+	refreshBtn = widget.NewButtonWithIcon("读取设置", theme.ViewRefreshIcon(), load)
+	refreshBtn.Importance = widget.HighImportance
+	namespaceSelect.OnChanged = func(string) {
+		if ui.selectedDevice != "" {
+			load()
+		}
+	}
+
+	// runChange 在后台执行一项修改，成功后重新读取当前命名空间
+	runChange := func(desc string, change func(deviceID string) error) {
+		if ui.selectedDevice == "" {
+			ui.showMessagePopup("错误", "请先选择一个设备")
+			return
+		}
+		deviceID := ui.selectedDevice
+		statusLabel.SetText(fmt.Sprintf("状态: 正在%s...", desc))
+		go func() {
+			err := change(deviceID)
+			fyne.Do(func() {
+				if err != nil {
+					logging.Error("%s失败: deviceID=%s, 错误: %v", desc, deviceID, err)
+					statusLabel.SetText(fmt.Sprintf("状态: %s失败 - %v", desc, err))
+					return
+				}
+				logging.Info("%s: deviceID=%s", desc, deviceID)
+				statusLabel.SetText(fmt.Sprintf("状态: 已%s", desc))
+				if settings != nil && loadedDevice == deviceID {
+					note = "已" + desc
+					load()
+				}
+			})
+		}()
+	}
+
+	putBtn := widget.NewButtonWithIcon("保存 (put)", theme.DocumentSaveIcon(), func() {
+		namespace, key, value := namespaceSelect.Selected, strings.TrimSpace(keyEntry.Text), valueEntry.Text
+		if err := adb.ValidateSetting(namespace, key); err != nil {
+			ui.showMessagePopup("错误", err.Error())
+			return
+		}
+		runChange(fmt.Sprintf("设置 %s %s = %s", namespace, key, value), func(deviceID string) error {
+			return adb.PutSetting(deviceID, namespace, key, value)
+		})
+	})
+	deleteBtn := widget.NewButtonWithIcon("删除", theme.DeleteIcon(), func() {
+		namespace, key := namespaceSelect.Selected, strings.TrimSpace(keyEntry.Text)
+		if err := adb.ValidateSetting(namespace, key); err != nil {
+			ui.showMessagePopup("错误", err.Error())
+			return
+		}
+		dialog.ShowConfirm("删除设置", fmt.Sprintf("确定删除 %s %s 吗？删除后将使用系统默认值。", namespace, key), func(ok bool) {
+			if ok {
+				runChange(fmt.Sprintf("删除 %s %s", namespace, key), func(deviceID string) error {
+					return adb.DeleteSetting(deviceID, namespace, key)
+				})
+			}
+		}, ui.window)
+	})
+
+	resetModes := make([]string, 0, len(adb.SettingsResetModes))
+	for mode := range adb.SettingsResetModes {
+		resetModes = append(resetModes, mode)
+	}
+	slices.Sort(resetModes)
+	resetModeLabel := widget.NewLabel("")
+	resetModeLabel.Wrapping = fyne.TextWrapWord
+	resetModeSelect := widget.NewSelect(resetModes, func(mode string) {
+		resetModeLabel.SetText(adb.SettingsResetModes[mode])
+	})
+	resetModeSelect.SetSelected("untrusted_defaults")
+	resetBtn := widget.NewButtonWithIcon("重置命名空间", theme.HistoryIcon(), func() {
+		namespace, mode := namespaceSelect.Selected, resetModeSelect.Selected
+		if namespace == adb.SettingsSystem {
+			ui.showMessagePopup("提示", "settings reset 只支持 secure 和 global 命名空间")
+			return
+		}
+		dialog.ShowConfirm("重置设置", fmt.Sprintf("确定以 %s 模式重置 %s 命名空间吗？\n%s", mode, namespace, adb.SettingsResetModes[mode]), func(ok bool) {
+			if ok {
+				runChange(fmt.Sprintf("重置 %s（%s）", namespace, mode), func(deviceID string) error {
+					return adb.ResetSettings(deviceID, namespace, mode)
+				})
+			}
+		}, ui.window)
+	})
+	resetBtn.Importance = widget.DangerImportance
+
+	// 设置配置
+	profileEditor := widget.NewMultiLineEntry()
+	profileEditor.TextStyle = fyne.TextStyle{Monospace: true}
+	profileEditor.SetPlaceHolder("每行一项: <命名空间> <键> <值>，或 delete <命名空间> <键>")
+	profileEditor.SetMinRowsVisible(6)
+	profileDesc := widget.NewLabel("")
+	profileDesc.Wrapping = fyne.TextWrapWord
+
+	allProfiles := func() []adb.SettingsProfile {
+		return append(slices.Clone(adb.BuiltinSettingsProfiles), savedProfiles...)
+	}
+	findProfile := func(name string) (adb.SettingsProfile, bool) {
+		for _, p := range allProfiles() {
+			if p.Name == name {
+				return p, true
+			}
+		}
+		return adb.SettingsProfile{}, false
+	}
+	profileNames := func() []string {
+		var names []string
+		for _, p := range allProfiles() {
+			names = append(names, p.Name)
+		}
+		return names
+	}
+
+	var deleteProfileBtn, undoBtn *widget.Button
+	profileSelect := widget.NewSelect(profileNames(), func(name string) {
+		p, ok := findProfile(name)
+		if !ok {
+			return
+		}
+		profileEditor.SetText(adb.FormatSettingValues(p.Settings))
+		profileDesc.SetText(p.Description)
+		if p.Builtin {
+			deleteProfileBtn.Disable()
+		} else {
+			deleteProfileBtn.Enable()
+		}
+	})
+	profileSelect.PlaceHolder = "选择配置"
+
+	addCurrentBtn := widget.NewButtonWithIcon("加入当前项", theme.ContentAddIcon(), func() {
+		v := adb.SettingValue{Namespace: namespaceSelect.Selected, Key: strings.TrimSpace(keyEntry.Text), Value: valueEntry.Text}
+		if err := adb.ValidateSetting(v.Namespace, v.Key); err != nil {
+			ui.showMessagePopup("错误", err.Error())
+			return
+		}
+		text := profileEditor.Text
+		if text != "" && !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+		profileEditor.SetText(text + v.String() + "\n")
+	})
+
+	saveProfileBtn := widget.NewButtonWithIcon("另存为配置", theme.DocumentSaveIcon(), func() {
+		values, err := adb.ParseSettingValues(profileEditor.Text)
+		if err != nil {
+			ui.showMessagePopup("错误", err.Error())
+			return
+		}
+		nameEntry := widget.NewEntry()
+		descEntry := widget.NewEntry()
+		if p, ok := findProfile(profileSelect.Selected); ok && !p.Builtin {
+			nameEntry.SetText(p.Name)
+			descEntry.SetText(p.Description)
+		}
+		dialog.ShowForm("保存设置配置", "保存", "取消", []*widget.FormItem{
+			widget.NewFormItem("名称", nameEntry),
+			widget.NewFormItem("说明", descEntry),
+		}, func(ok bool) {
+			name := strings.TrimSpace(nameEntry.Text)
+			if !ok || name == "" {
+				return
+			}
+			if p, exists := findProfile(name); exists && p.Builtin {
+				ui.showMessagePopup("错误", "不能覆盖内置配置: "+name)
+				return
+			}
+			profile := adb.SettingsProfile{Name: name, Description: strings.TrimSpace(descEntry.Text), Settings: values}
+			updated := slices.DeleteFunc(slices.Clone(savedProfiles), func(p adb.SettingsProfile) bool { return p.Name == name })
+			updated = append(updated, profile)
+			if err := adb.SaveSettingsProfiles(profilePath, updated); err != nil {
+				ui.showMessagePopup("错误", "保存配置失败: "+err.Error())
+				return
+			}
+			savedProfiles = updated
+			profileSelect.SetOptions(profileNames())
+			profileSelect.SetSelected(name)
+			statusLabel.SetText("状态: 已保存配置 " + name)
+		}, ui.window)
+	})
+
+	deleteProfileBtn = widget.NewButtonWithIcon("删除配置", theme.DeleteIcon(), func() {
+		name := profileSelect.Selected
+		p, ok := findProfile(name)
+		if !ok || p.Builtin {
+			return
+		}
+		dialog.ShowConfirm("删除配置", fmt.Sprintf("确定删除配置“%s”吗？", name), func(ok bool) {
+			if !ok {
+				return
+			}
+			updated := slices.DeleteFunc(slices.Clone(savedProfiles), func(p adb.SettingsProfile) bool { return p.Name == name })
+			if err := adb.SaveSettingsProfiles(profilePath, updated); err != nil {
+				ui.showMessagePopup("错误", "删除配置失败: "+err.Error())
+				return
+			}
+			savedProfiles = updated
+			profileSelect.ClearSelected()
+			profileSelect.SetOptions(profileNames())
+			profileEditor.SetText("")
+			profileDesc.SetText("")
+			deleteProfileBtn.Disable()
+		}, ui.window)
+	})
+	deleteProfileBtn.Disable()
+
+	applyProfileBtn := widget.NewButtonWithIcon("应用到设备", theme.ConfirmIcon(), func() {
+		values, err := adb.ParseSettingValues(profileEditor.Text)
+		if err != nil {
+			ui.showMessagePopup("错误", err.Error())
+			return
+		}
+		runChange(fmt.Sprintf("应用 %d 项设置", len(values)), func(deviceID string) error {
+			snapshot, err := adb.SnapshotSettings(deviceID, values)
+			if err != nil {
+				return err
+			}
+			fyne.Do(func() {
+				undoSettings, undoDevice = snapshot, deviceID
+				undoBtn.Enable()
+			})
+			return adb.ApplySettings(deviceID, values)
+		})
+	})
+	applyProfileBtn.Importance = widget.HighImportance
+
+	undoBtn = widget.NewButtonWithIcon("撤销上次应用", theme.ContentUndoIcon(), func() {
+		if undoSettings == nil {
+			return
+		}
+		if ui.selectedDevice != undoDevice {
+			ui.showMessagePopup("提示", fmt.Sprintf("上次应用的设备是 %s，请先切换到该设备", undoDevice))
+			return
+		}
+		values := undoSettings
+		runChange(fmt.Sprintf("恢复 %d 项设置", len(values)), func(deviceID string) error {
+			if err := adb.ApplySettings(deviceID, values); err != nil {
+				return err
+			}
+			fyne.Do(func() {
+				undoSettings = nil
+				undoBtn.Disable()
+			})
+			return nil
+		})
+	})
+	undoBtn.Disable()
+
+	editCard := widget.NewCard("编辑", "", container.NewVBox(
+		widget.NewForm(
+			widget.NewFormItem("键", keyEntry),
+			widget.NewFormItem("值", valueEntry),
+		),
+		container.NewHBox(putBtn, deleteBtn),
+	))
+	resetCard := widget.NewCard("重置", "", container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel("模式:"), resetBtn, resetModeSelect),
+		resetModeLabel,
+	))
+	profileCard := widget.NewCard("设置配置", "", container.NewVBox(
+		profileSelect,
+		profileDesc,
+		profileEditor,
+		container.NewHBox(addCurrentBtn, saveProfileBtn, deleteProfileBtn),
+		container.NewHBox(applyProfileBtn, undoBtn),
+	))
+
+	left := container.NewBorder(
+		container.NewBorder(nil, nil, container.NewHBox(namespaceSelect, refreshBtn), nil, searchEntry),
+		nil, nil, nil,
+		settingsList,
+	)
+	split := container.NewHSplit(left, container.NewVScroll(container.NewVBox(editCard, resetCard, profileCard)))
+	split.Offset = 0.55
+
+	return container.NewPadded(container.NewBorder(
+		container.NewVBox(statusLabel, widget.NewSeparator()),
+		nil, nil, nil,
+		split,
+	))
+}
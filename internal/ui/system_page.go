@@ -0,0 +1,18 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+)
+
+// createSystemPage 系统工具页面：系统属性、系统设置、显示、电池模拟与状态栏演示模式
+func (ui *UI) createSystemPage() fyne.CanvasObject {
+	tabs := container.NewAppTabs(
+		container.NewTabItem("系统属性", ui.createPropertyTab()),
+		container.NewTabItem("系统设置", ui.createSettingsTab()),
+		container.NewTabItem("显示", ui.createDisplayTab()),
+		container.NewTabItem("电池模拟", ui.createBatteryTab()),
+		container.NewTabItem("演示模式", ui.createDemoModeTab()),
+	)
+	return tabs
+}
@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"path/filepath"
+	"strconv"
+	"time"
+	"yikong/internal/adb"
+	"yikong/internal/logging"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 每台设备同时进行的传输数量选项
+var transferConcurrencyOptions = []string{"1", "2", "3", "4"}
+
+// transferManager 返回传输队列，首次调用时创建并恢复上次未完成的任务
+func (ui *UI) transferManager() *adb.TransferManager {
+	if ui.transfers == nil {
+		ui.transfers = adb.NewTransferManager(adb.DefaultTransferStorePath(), 2)
+		if err := ui.transfers.Load(); err != nil {
+			logging.Error("加载传输队列失败: %v", err)
+		}
+	}
+	return ui.transfers
+}
+
+// createTransferPanel 传输队列面板：显示每个任务的进度、速度与剩余时间，可取消或重试
+func (ui *UI) createTransferPanel() fyne.CanvasObject {
+	manager := ui.transferManager()
+	jobs := manager.Jobs()
+
+	title := widget.NewLabel("传输队列")
+	title.TextStyle = fyne.TextStyle{Bold: true}
+
+	summaryLabel := widget.NewLabel("")
+
+	list := widget.NewList(
+		func() int {
+			return len(jobs)
+		},
+		func() fyne.CanvasObject {
+			name := widget.NewLabel("文件")
+			name.Truncation = fyne.TextTruncateEllipsis
+			detail := widget.NewLabel("")
+			detail.TextStyle = fyne.TextStyle{Italic: true}
+			detail.Truncation = fyne.TextTruncateEllipsis
+			retryBtn := widget.NewButtonWithIcon("", theme.ViewRefreshIcon(), nil)
+			cancelBtn := widget.NewButtonWithIcon("", theme.CancelIcon(), nil)
+			return container.NewBorder(nil, nil, nil,
+				container.NewHBox(retryBtn, cancelBtn),
+				container.NewVBox(name, widget.NewProgressBar(), detail),
+			)
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			row := o.(*fyne.Container)
+			info := row.Objects[0].(*fyne.Container)
+			buttons := row.Objects[1].(*fyne.Container)
+			name := info.Objects[0].(*widget.Label)
+			bar := info.Objects[1].(*widget.ProgressBar)
+			detail := info.Objects[2].(*widget.Label)
+			retryBtn := buttons.Objects[0].(*widget.Button)
+			cancelBtn := buttons.Objects[1].(*widget.Button)
+
+			job := jobs[i]
+			arrow := "→"
+			if job.Direction == adb.SyncPull {
+				arrow = "←"
+			}
+			name.SetText(fmt.Sprintf("[%s] %s %s %s", job.DeviceID, filepath.Base(job.Source), arrow, job.Target))
+			if job.Total > 0 {
+				bar.SetValue(float64(job.Transferred) / float64(job.Total))
+			} else {
+				bar.SetValue(0)
+			}
+			detail.SetText(describeTransferJob(job))
+
+			id := job.ID
+			retryBtn.OnTapped = func() { manager.Retry(id) }
+			cancelBtn.OnTapped = func() { manager.Cancel(id) }
+			if job.Status == adb.TransferFailed || job.Status == adb.TransferCanceled {
+				retryBtn.Enable()
+			} else {
+				retryBtn.Disable()
+			}
+			if job.Finished() {
+				cancelBtn.Disable()
+			} else {
+				cancelBtn.Enable()
+			}
+		},
+	)
+
+	refresh := func() {
+		jobs = manager.Jobs()
+		active := 0
+		var speed float64
+		for _, job := range jobs {
+			if !job.Finished() {
+				active++
+			}
+			speed += job.Speed
+		}
+		summaryLabel.SetText(fmt.Sprintf("%d 个进行中 / 共 %d 个  %s/s", active, len(jobs), formatSize(int64(speed))))
+		list.Refresh()
+	}
+	manager.SetOnChange(func() {
+		fyne.Do(refresh)
+	})
+	refresh()
+
+	concurrencySelect := widget.NewSelect(transferConcurrencyOptions, func(value string) {
+		if n, err := strconv.Atoi(value); err == nil {
+			manager.SetPerDeviceLimit(n)
+		}
+	})
+	concurrencySelect.SetSelected("2")
+
+	clearBtn := widget.NewButton("清除已结束", func() {
+		manager.ClearFinished()
+	})
+
+	header := container.NewBorder(nil, nil,
+		container.NewHBox(title, summaryLabel),
+		container.NewHBox(widget.NewLabel("每台设备并发:"), concurrencySelect, clearBtn),
+	)
+	sizer := canvas.NewRectangle(color.Transparent)
+	sizer.SetMinSize(fyne.NewSize(0, 160))
+
+	return container.NewBorder(header, nil, nil, nil, container.NewStack(sizer, list))
+}
+
+// describeTransferJob 任务状态的文字描述，包含速度与剩余时间
+func describeTransferJob(job adb.TransferJob) string {
+	progress := formatSize(job.Transferred) + " / " + formatSize(job.Total)
+	switch job.Status {
+	case adb.TransferPending:
+		return "等待中"
+	case adb.TransferRunning:
+		text := fmt.Sprintf("%s  %s/s", progress, formatSize(int64(job.Speed)))
+		if eta := job.ETA(); eta > 0 {
+			text += "  剩余 " + eta.Round(time.Second).String()
+		}
+		if job.Attempts > 1 {
+			text += fmt.Sprintf("  (第 %d 次尝试)", job.Attempts)
+		}
+		return text
+	case adb.TransferRetrying:
+		wait := time.Until(job.NextAttempt).Round(time.Second)
+		return fmt.Sprintf("第 %d 次尝试失败，%v 后重试: %s", job.Attempts, max(wait, 0), job.Error)
+	case adb.TransferDone:
+		return "完成  " + formatSize(job.Total)
+	case adb.TransferFailed:
+		return "失败: " + job.Error
+	case adb.TransferCanceled:
+		return "已取消  " + progress
+	}
+	return job.Status
+}
@@ -0,0 +1,46 @@
+package utilities
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// CopyImageToClipboard 将 PNG 图片文件复制到系统剪贴板。
+// Fyne 的剪贴板只支持文本，这里调用各平台自带（或常见）的工具完成
+func CopyImageToClipboard(pngPath string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		script := "Add-Type -AssemblyName System.Windows.Forms; Add-Type -AssemblyName System.Drawing; " +
+			"[System.Windows.Forms.Clipboard]::SetImage([System.Drawing.Image]::FromFile('" +
+			strings.ReplaceAll(pngPath, "'", "''") + "'))"
+		cmd = exec.Command("powershell", "-NoProfile", "-STA", "-Command", script)
+	case "darwin":
+		script := `set the clipboard to (read (POSIX file "` +
+			strings.ReplaceAll(strings.ReplaceAll(pngPath, `\`, `\\`), `"`, `\"`) + `") as «class PNGf»)`
+		cmd = exec.Command("osascript", "-e", script)
+	default:
+		file, err := os.Open(pngPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			cmd = exec.Command("wl-copy", "--type", "image/png")
+		} else {
+			cmd = exec.Command("xclip", "-selection", "clipboard", "-t", "image/png", "-i")
+		}
+		cmd.Stdin = file
+	}
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return fmt.Errorf("复制图片到剪贴板失败（Linux 需要安装 xclip 或 wl-clipboard）: %w", err)
+	}
+	return nil
+}